@@ -0,0 +1,9 @@
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document of the HTTP API. It is written by hand and
+// kept in sync with the router and the dto types by api/openapi_test.go.
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,327 @@
+package api_test
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/api"
+	"github.com/kstsm/wb-event-booker/client"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/handler"
+	"github.com/kstsm/wb-event-booker/internal/health"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/scheduler"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// undocumented lists the routes that are deliberately left out of the spec:
+// HTML pages with their assets and the Prometheus endpoint.
+var undocumented = []string{"/", "/register", "/admin", "/event", "/docs", "/docs/assets/*", "/metrics"}
+
+// The deprecated unversioned prefix mirrors v1 and is not documented itself.
+const (
+	legacyPrefix = "/api/"
+	v1Prefix     = "/api/v1/"
+)
+
+type schema struct {
+	Ref                  string            `json:"$ref"`
+	Type                 string            `json:"type"`
+	Format               string            `json:"format"`
+	Enum                 []string          `json:"enum"`
+	Properties           map[string]schema `json:"properties"`
+	Required             []string          `json:"required"`
+	Items                *schema           `json:"items"`
+	AdditionalProperties *schema           `json:"additionalProperties"`
+}
+
+type document struct {
+	OpenAPI    string                               `json:"openapi"`
+	Paths      map[string]map[string]map[string]any `json:"paths"`
+	Components struct {
+		Schemas map[string]schema `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) *document {
+	t.Helper()
+
+	var doc document
+	if err := json.Unmarshal(api.Spec, &doc); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("openapi = %q, want 3.x", doc.OpenAPI)
+	}
+	return &doc
+}
+
+func newRouter(t *testing.T) http.Handler {
+	t.Helper()
+
+	repo := memory.NewRepository()
+	sched := scheduler.NewScheduler(repo)
+	t.Cleanup(sched.Stop)
+
+	svc := service.NewService(repo, config.BookingConfig{})
+	h := handler.NewHandler(
+		svc,
+		sched,
+		health.NewChecker(),
+		availability.NewFeed(availability.NewHub(), svc.GetEventByID),
+		config.Server{},
+		config.RateLimitConfig{},
+	)
+	return h.NewRouter()
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	doc := loadSpec(t)
+
+	var routes, legacy []string
+	walk := func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		switch {
+		case slices.Contains(undocumented, route):
+		case strings.HasPrefix(route, legacyPrefix) && !strings.HasPrefix(route, v1Prefix):
+			legacy = append(legacy, method+" "+v1Prefix+strings.TrimPrefix(route, legacyPrefix))
+		default:
+			routes = append(routes, method+" "+route)
+		}
+		return nil
+	}
+	if err := chi.Walk(newRouter(t).(chi.Routes), walk); err != nil {
+		t.Fatalf("chi.Walk: %v", err)
+	}
+
+	var v1 []string
+	for _, route := range routes {
+		if strings.Contains(route, " "+v1Prefix) {
+			v1 = append(v1, route)
+		}
+	}
+	sort.Strings(legacy)
+	sort.Strings(v1)
+	if !slices.Equal(legacy, v1) {
+		t.Errorf("legacy /api routes %v do not mirror v1 %v", legacy, v1)
+	}
+
+	sort.Strings(routes)
+
+	var documented []string
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(documented)
+	for _, route := range routes {
+		if !slices.Contains(documented, route) {
+			t.Errorf("route %s is missing from openapi.json", route)
+		}
+	}
+	for _, op := range documented {
+		if !slices.Contains(routes, op) {
+			t.Errorf("openapi.json documents %s, which the router does not serve", op)
+		}
+	}
+}
+
+func TestServedSpec(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newRouter(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if rec.Body.String() != string(api.Spec) {
+		t.Error("served document differs from api.Spec")
+	}
+}
+
+func TestSchemasMatchServerTypes(t *testing.T) {
+	checkSchemas(t, loadSpec(t), map[string]any{
+		"CreateEventRequest":     dto.CreateEventRequest{},
+		"CreateEventResponse":    dto.CreateEventResponse{},
+		"GetEventResponse":       dto.GetEventResponse{},
+		"ListEventsResponse":     dto.ListEventsResponse{},
+		"VenueRequest":           dto.VenueRequest{},
+		"VenueResponse":          dto.VenueResponse{},
+		"ListVenuesResponse":     dto.ListVenuesResponse{},
+		"CreateUserRequest":      dto.CreateUserRequest{},
+		"CreateUserResponse":     dto.CreateUserResponse{},
+		"BookEventRequest":       dto.BookEventRequest{},
+		"BookEventResponse":      dto.BookEventResponse{},
+		"ConfirmBookingRequest":  dto.ConfirmBookingRequest{},
+		"ConfirmBookingResponse": dto.ConfirmBookingResponse{},
+		"SubscribeRequest":       dto.SubscribeRequest{},
+		"SubscribeResponse":      dto.SubscribeResponse{},
+		"ListBookingsResponse":   dto.ListBookingsResponse{},
+		"JobResponse":            dto.JobResponse{},
+		"ListJobsResponse":       dto.ListJobsResponse{},
+		"RunJobResponse":         dto.RunJobResponse{},
+		"ReconcileSeatsResponse": dto.ReconcileSeatsResponse{},
+		"HealthResponse":         dto.HealthResponse{},
+		"Event":                  dto.Event{},
+		"EventAvailability":      dto.EventAvailability{},
+		"Venue":                  dto.Venue{},
+		"User":                   dto.User{},
+		"Booking":                dto.Booking{},
+		"JobRun":                 dto.JobRun{},
+		"SeatDrift":              dto.SeatDrift{},
+		"SeatReconciliation":     dto.SeatReconciliation{},
+		"CheckResult":            models.CheckResult{},
+		"Problem":                models.Problem{},
+		"FieldError":             apperrors.FieldError{},
+	}, true)
+}
+
+func TestSchemasMatchClientTypes(t *testing.T) {
+	checkSchemas(t, loadSpec(t), map[string]any{
+		"CreateEventRequest":    client.CreateEventRequest{},
+		"CreateUserRequest":     client.CreateUserRequest{},
+		"BookEventRequest":      client.BookEventRequest{},
+		"BookEventResponse":     client.BookEventResponse{},
+		"ConfirmBookingRequest": client.ConfirmBookingRequest{},
+		"SubscribeRequest":      client.SubscribeRequest{},
+		"VenueRequest":          client.VenueRequest{},
+		"Event":                 client.Event{},
+		"Venue":                 client.Venue{},
+		"User":                  client.User{},
+		"Booking":               client.Booking{},
+		"JobResponse":           client.Job{},
+		"JobRun":                client.JobRun{},
+		"ListJobsResponse":      client.ListJobsResponse{},
+		"SeatDrift":             client.SeatDrift{},
+		"SeatReconciliation":    client.SeatReconciliation{},
+		"CheckResult":           client.CheckResult{},
+		"HealthResponse":        client.Health{},
+		"Problem":               client.Problem{},
+		"FieldError":            client.FieldError{},
+	}, false)
+}
+
+func TestProblemCodesMatchCatalogue(t *testing.T) {
+	doc := loadSpec(t)
+
+	var codes []string
+	for _, entry := range apperrors.Catalogue() {
+		codes = append(codes, entry.Code)
+	}
+	if got := doc.Components.Schemas["Problem"].Properties["code"].Enum; !slices.Equal(got, codes) {
+		t.Errorf("Problem.code enum = %v, want %v", got, codes)
+	}
+}
+
+// checkSchemas compares the JSON fields of each Go type with the properties of
+// the named component schema. complete requires every schema to be covered,
+// which holds for the server types but not for the client, which unwraps the
+// single-field envelopes.
+func checkSchemas(t *testing.T, doc *document, types map[string]any, complete bool) {
+	t.Helper()
+
+	if complete {
+		for name := range doc.Components.Schemas {
+			if _, ok := types[name]; !ok {
+				t.Errorf("schema %s has no Go type to check against", name)
+			}
+		}
+	}
+
+	for name, value := range types {
+		s, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is missing from openapi.json", name)
+			continue
+		}
+		checkStruct(t, doc, name, reflect.TypeOf(value), s)
+	}
+}
+
+func checkStruct(t *testing.T, doc *document, name string, typ reflect.Type, s schema) {
+	t.Helper()
+
+	fields := map[string]bool{}
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		jsonName, opts, _ := strings.Cut(tag, ",")
+		omitempty := strings.Contains(opts, "omitempty")
+		fields[jsonName] = true
+
+		prop, ok := s.Properties[jsonName]
+		if !ok {
+			t.Errorf("%s: field %s is missing from the schema", name, jsonName)
+			continue
+		}
+		if !compatible(doc, field.Type, prop) {
+			t.Errorf("%s.%s: Go type %s does not match schema type %q%s", name, jsonName, field.Type, prop.Type, prop.Ref)
+		}
+
+		required := slices.Contains(s.Required, jsonName)
+		if required && omitempty {
+			t.Errorf("%s.%s is required but omitted when empty", name, jsonName)
+		}
+		if !required && !omitempty && !strings.HasSuffix(name, "Request") {
+			t.Errorf("%s.%s is always present but not required", name, jsonName)
+		}
+	}
+
+	for prop := range s.Properties {
+		if !fields[prop] {
+			t.Errorf("%s: schema property %s has no Go field", name, prop)
+		}
+	}
+}
+
+func compatible(doc *document, typ reflect.Type, s schema) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if s.Ref != "" {
+		s = doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+
+	switch typ {
+	case reflect.TypeFor[time.Time]():
+		return s.Type == "string" && s.Format == "date-time"
+	case reflect.TypeFor[uuid.UUID]():
+		return s.Type == "string" && s.Format == "uuid"
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return s.Type == "string"
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return s.Type == "integer"
+	case reflect.Float64:
+		return s.Type == "number"
+	case reflect.Bool:
+		return s.Type == "boolean"
+	case reflect.Slice:
+		return s.Type == "array" && s.Items != nil && compatible(doc, typ.Elem(), *s.Items)
+	case reflect.Map:
+		return s.Type == "object" && s.AdditionalProperties != nil && compatible(doc, typ.Elem(), *s.AdditionalProperties)
+	case reflect.Struct:
+		return s.Type == "object"
+	}
+	return false
+}
@@ -0,0 +1,2026 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: eventbooker/v1/event_booker.proto
+
+package eventbookerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Inventory int32
+
+const (
+	Inventory_INVENTORY_UNSPECIFIED Inventory = 0
+	Inventory_INVENTORY_COUNTER     Inventory = 1
+	Inventory_INVENTORY_SEATS       Inventory = 2
+)
+
+// Enum value maps for Inventory.
+var (
+	Inventory_name = map[int32]string{
+		0: "INVENTORY_UNSPECIFIED",
+		1: "INVENTORY_COUNTER",
+		2: "INVENTORY_SEATS",
+	}
+	Inventory_value = map[string]int32{
+		"INVENTORY_UNSPECIFIED": 0,
+		"INVENTORY_COUNTER":     1,
+		"INVENTORY_SEATS":       2,
+	}
+)
+
+func (x Inventory) Enum() *Inventory {
+	p := new(Inventory)
+	*p = x
+	return p
+}
+
+func (x Inventory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Inventory) Descriptor() protoreflect.EnumDescriptor {
+	return file_eventbooker_v1_event_booker_proto_enumTypes[0].Descriptor()
+}
+
+func (Inventory) Type() protoreflect.EnumType {
+	return &file_eventbooker_v1_event_booker_proto_enumTypes[0]
+}
+
+func (x Inventory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Inventory.Descriptor instead.
+func (Inventory) EnumDescriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{0}
+}
+
+type BookingStatus int32
+
+const (
+	BookingStatus_BOOKING_STATUS_UNSPECIFIED BookingStatus = 0
+	BookingStatus_BOOKING_STATUS_RESERVED    BookingStatus = 1
+	BookingStatus_BOOKING_STATUS_CONFIRMED   BookingStatus = 2
+	BookingStatus_BOOKING_STATUS_CANCELLED   BookingStatus = 3
+)
+
+// Enum value maps for BookingStatus.
+var (
+	BookingStatus_name = map[int32]string{
+		0: "BOOKING_STATUS_UNSPECIFIED",
+		1: "BOOKING_STATUS_RESERVED",
+		2: "BOOKING_STATUS_CONFIRMED",
+		3: "BOOKING_STATUS_CANCELLED",
+	}
+	BookingStatus_value = map[string]int32{
+		"BOOKING_STATUS_UNSPECIFIED": 0,
+		"BOOKING_STATUS_RESERVED":    1,
+		"BOOKING_STATUS_CONFIRMED":   2,
+		"BOOKING_STATUS_CANCELLED":   3,
+	}
+)
+
+func (x BookingStatus) Enum() *BookingStatus {
+	p := new(BookingStatus)
+	*p = x
+	return p
+}
+
+func (x BookingStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BookingStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_eventbooker_v1_event_booker_proto_enumTypes[1].Descriptor()
+}
+
+func (BookingStatus) Type() protoreflect.EnumType {
+	return &file_eventbooker_v1_event_booker_proto_enumTypes[1]
+}
+
+func (x BookingStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BookingStatus.Descriptor instead.
+func (BookingStatus) EnumDescriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{1}
+}
+
+type UserRole int32
+
+const (
+	UserRole_USER_ROLE_UNSPECIFIED UserRole = 0
+	UserRole_USER_ROLE_USER        UserRole = 1
+	UserRole_USER_ROLE_ADMIN       UserRole = 2
+)
+
+// Enum value maps for UserRole.
+var (
+	UserRole_name = map[int32]string{
+		0: "USER_ROLE_UNSPECIFIED",
+		1: "USER_ROLE_USER",
+		2: "USER_ROLE_ADMIN",
+	}
+	UserRole_value = map[string]int32{
+		"USER_ROLE_UNSPECIFIED": 0,
+		"USER_ROLE_USER":        1,
+		"USER_ROLE_ADMIN":       2,
+	}
+)
+
+func (x UserRole) Enum() *UserRole {
+	p := new(UserRole)
+	*p = x
+	return p
+}
+
+func (x UserRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_eventbooker_v1_event_booker_proto_enumTypes[2].Descriptor()
+}
+
+func (UserRole) Type() protoreflect.EnumType {
+	return &file_eventbooker_v1_event_booker_proto_enumTypes[2]
+}
+
+func (x UserRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserRole.Descriptor instead.
+func (UserRole) EnumDescriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{2}
+}
+
+type Event struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Date       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	TotalSeats int32                  `protobuf:"varint,4,opt,name=total_seats,json=totalSeats,proto3" json:"total_seats,omitempty"`
+	// Seats held by unconfirmed reservations.
+	ReservedSeats int32 `protobuf:"varint,5,opt,name=reserved_seats,json=reservedSeats,proto3" json:"reserved_seats,omitempty"`
+	// Seats of confirmed bookings.
+	BookedSeats    int32 `protobuf:"varint,6,opt,name=booked_seats,json=bookedSeats,proto3" json:"booked_seats,omitempty"`
+	AvailableSeats int32 `protobuf:"varint,7,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	// How long a reservation is held before it expires.
+	BookingLifetimeMinutes      int32                  `protobuf:"varint,8,opt,name=booking_lifetime_minutes,json=bookingLifetimeMinutes,proto3" json:"booking_lifetime_minutes,omitempty"`
+	RequiresPaymentConfirmation bool                   `protobuf:"varint,9,opt,name=requires_payment_confirmation,json=requiresPaymentConfirmation,proto3" json:"requires_payment_confirmation,omitempty"`
+	Inventory                   Inventory              `protobuf:"varint,10,opt,name=inventory,proto3,enum=eventbooker.v1.Inventory" json:"inventory,omitempty"`
+	CreatedAt                   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Set once the event has been cancelled.
+	CancelledAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	// Markdown.
+	Description      string                 `protobuf:"bytes,13,opt,name=description,proto3" json:"description,omitempty"`
+	Category         string                 `protobuf:"bytes,14,opt,name=category,proto3" json:"category,omitempty"`
+	Tags             []string               `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	OrganizerContact string                 `protobuf:"bytes,16,opt,name=organizer_contact,json=organizerContact,proto3" json:"organizer_contact,omitempty"`
+	CoverImageUrl    string                 `protobuf:"bytes,17,opt,name=cover_image_url,json=coverImageUrl,proto3" json:"cover_image_url,omitempty"`
+	EndDate          *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// Empty if the event is not held at a registered venue.
+	VenueId string `protobuf:"bytes,19,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	// IANA time zone to show the times of the event in.
+	TimeZone string `protobuf:"bytes,20,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// End of the ticket sales; bookings are rejected with sales_closed from
+	// this moment on.
+	SalesCloseAt *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=sales_close_at,json=salesCloseAt,proto3" json:"sales_close_at,omitempty"`
+	// Start of the ticket sales; bookings are rejected with sales_not_open
+	// before it. Not set if sales opened when the event was created.
+	SalesOpenAt *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=sales_open_at,json=salesOpenAt,proto3" json:"sales_open_at,omitempty"`
+	// upcoming, open or closed at the time of the response.
+	SalesStatus   string `protobuf:"bytes,23,opt,name=sales_status,json=salesStatus,proto3" json:"sales_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Event) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Event) GetTotalSeats() int32 {
+	if x != nil {
+		return x.TotalSeats
+	}
+	return 0
+}
+
+func (x *Event) GetReservedSeats() int32 {
+	if x != nil {
+		return x.ReservedSeats
+	}
+	return 0
+}
+
+func (x *Event) GetBookedSeats() int32 {
+	if x != nil {
+		return x.BookedSeats
+	}
+	return 0
+}
+
+func (x *Event) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *Event) GetBookingLifetimeMinutes() int32 {
+	if x != nil {
+		return x.BookingLifetimeMinutes
+	}
+	return 0
+}
+
+func (x *Event) GetRequiresPaymentConfirmation() bool {
+	if x != nil {
+		return x.RequiresPaymentConfirmation
+	}
+	return false
+}
+
+func (x *Event) GetInventory() Inventory {
+	if x != nil {
+		return x.Inventory
+	}
+	return Inventory_INVENTORY_UNSPECIFIED
+}
+
+func (x *Event) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Event) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *Event) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Event) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Event) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Event) GetOrganizerContact() string {
+	if x != nil {
+		return x.OrganizerContact
+	}
+	return ""
+}
+
+func (x *Event) GetCoverImageUrl() string {
+	if x != nil {
+		return x.CoverImageUrl
+	}
+	return ""
+}
+
+func (x *Event) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Event) GetVenueId() string {
+	if x != nil {
+		return x.VenueId
+	}
+	return ""
+}
+
+func (x *Event) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Event) GetSalesCloseAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesCloseAt
+	}
+	return nil
+}
+
+func (x *Event) GetSalesOpenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesOpenAt
+	}
+	return nil
+}
+
+func (x *Event) GetSalesStatus() string {
+	if x != nil {
+		return x.SalesStatus
+	}
+	return ""
+}
+
+type User struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	// Telegram chat for notifications, 0 if not set.
+	TelegramId    int64                  `protobuf:"varint,4,opt,name=telegram_id,json=telegramId,proto3" json:"telegram_id,omitempty"`
+	Role          UserRole               `protobuf:"varint,5,opt,name=role,proto3,enum=eventbooker.v1.UserRole" json:"role,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetTelegramId() int64 {
+	if x != nil {
+		return x.TelegramId
+	}
+	return 0
+}
+
+func (x *User) GetRole() UserRole {
+	if x != nil {
+		return x.Role
+	}
+	return UserRole_USER_ROLE_UNSPECIFIED
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Booking struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId       string                 `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        BookingStatus          `protobuf:"varint,4,opt,name=status,proto3,enum=eventbooker.v1.BookingStatus" json:"status,omitempty"`
+	Deadline      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Booking) Reset() {
+	*x = Booking{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Booking) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Booking) ProtoMessage() {}
+
+func (x *Booking) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Booking.ProtoReflect.Descriptor instead.
+func (*Booking) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Booking) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Booking) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Booking) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Booking) GetStatus() BookingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BookingStatus_BOOKING_STATUS_UNSPECIFIED
+}
+
+func (x *Booking) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *Booking) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Booking) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type EventAvailability struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EventId        string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	TotalSeats     int32                  `protobuf:"varint,2,opt,name=total_seats,json=totalSeats,proto3" json:"total_seats,omitempty"`
+	ReservedSeats  int32                  `protobuf:"varint,3,opt,name=reserved_seats,json=reservedSeats,proto3" json:"reserved_seats,omitempty"`
+	BookedSeats    int32                  `protobuf:"varint,4,opt,name=booked_seats,json=bookedSeats,proto3" json:"booked_seats,omitempty"`
+	AvailableSeats int32                  `protobuf:"varint,5,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	Cancelled      bool                   `protobuf:"varint,6,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EventAvailability) Reset() {
+	*x = EventAvailability{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventAvailability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventAvailability) ProtoMessage() {}
+
+func (x *EventAvailability) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventAvailability.ProtoReflect.Descriptor instead.
+func (*EventAvailability) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EventAvailability) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *EventAvailability) GetTotalSeats() int32 {
+	if x != nil {
+		return x.TotalSeats
+	}
+	return 0
+}
+
+func (x *EventAvailability) GetReservedSeats() int32 {
+	if x != nil {
+		return x.ReservedSeats
+	}
+	return 0
+}
+
+func (x *EventAvailability) GetBookedSeats() int32 {
+	if x != nil {
+		return x.BookedSeats
+	}
+	return 0
+}
+
+func (x *EventAvailability) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *EventAvailability) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+type CreateEventRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Must be in the future.
+	Date                        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	TotalSeats                  int32                  `protobuf:"varint,3,opt,name=total_seats,json=totalSeats,proto3" json:"total_seats,omitempty"`
+	BookingLifetimeMinutes      int32                  `protobuf:"varint,4,opt,name=booking_lifetime_minutes,json=bookingLifetimeMinutes,proto3" json:"booking_lifetime_minutes,omitempty"`
+	RequiresPaymentConfirmation bool                   `protobuf:"varint,5,opt,name=requires_payment_confirmation,json=requiresPaymentConfirmation,proto3" json:"requires_payment_confirmation,omitempty"`
+	// INVENTORY_UNSPECIFIED means INVENTORY_COUNTER.
+	Inventory Inventory `protobuf:"varint,6,opt,name=inventory,proto3,enum=eventbooker.v1.Inventory" json:"inventory,omitempty"`
+	// Markdown.
+	Description string `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	// One of concert, conference, meetup, workshop, theatre, sport, exhibition
+	// and other; other if not set.
+	Category string   `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`
+	Tags     []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	// An email address or a phone number.
+	OrganizerContact string `protobuf:"bytes,10,opt,name=organizer_contact,json=organizerContact,proto3" json:"organizer_contact,omitempty"`
+	// An absolute http or https URL.
+	CoverImageUrl string `protobuf:"bytes,11,opt,name=cover_image_url,json=coverImageUrl,proto3" json:"cover_image_url,omitempty"`
+	// Must be after date; two hours after date if not set.
+	EndDate *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// Venue of the event, if any. Events at a venue cannot overlap, and
+	// total_seats 0 takes the default capacity of the venue.
+	VenueId string `protobuf:"bytes,13,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	// IANA time zone, e.g. Europe/Moscow; the one of the venue or the server
+	// default if not set.
+	TimeZone string `protobuf:"bytes,14,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Sales open on creation if not set.
+	SalesOpenAt *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=sales_open_at,json=salesOpenAt,proto3" json:"sales_open_at,omitempty"`
+	// At most date; the configured time before date if not set.
+	SalesCloseAt  *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=sales_close_at,json=salesCloseAt,proto3" json:"sales_close_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventRequest) Reset() {
+	*x = CreateEventRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventRequest) ProtoMessage() {}
+
+func (x *CreateEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventRequest.ProtoReflect.Descriptor instead.
+func (*CreateEventRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateEventRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetTotalSeats() int32 {
+	if x != nil {
+		return x.TotalSeats
+	}
+	return 0
+}
+
+func (x *CreateEventRequest) GetBookingLifetimeMinutes() int32 {
+	if x != nil {
+		return x.BookingLifetimeMinutes
+	}
+	return 0
+}
+
+func (x *CreateEventRequest) GetRequiresPaymentConfirmation() bool {
+	if x != nil {
+		return x.RequiresPaymentConfirmation
+	}
+	return false
+}
+
+func (x *CreateEventRequest) GetInventory() Inventory {
+	if x != nil {
+		return x.Inventory
+	}
+	return Inventory_INVENTORY_UNSPECIFIED
+}
+
+func (x *CreateEventRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetOrganizerContact() string {
+	if x != nil {
+		return x.OrganizerContact
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetCoverImageUrl() string {
+	if x != nil {
+		return x.CoverImageUrl
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetVenueId() string {
+	if x != nil {
+		return x.VenueId
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *CreateEventRequest) GetSalesOpenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesOpenAt
+	}
+	return nil
+}
+
+func (x *CreateEventRequest) GetSalesCloseAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SalesCloseAt
+	}
+	return nil
+}
+
+type GetEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventRequest) Reset() {
+	*x = GetEventRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventRequest) ProtoMessage() {}
+
+func (x *GetEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventRequest.ProtoReflect.Descriptor instead.
+func (*GetEventRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetEventRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 50 if not set, at most 500.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only events of this category, if set.
+	Category string `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	// Only events that have all of these tags.
+	Tags          []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsRequest) Reset() {
+	*x = ListEventsRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsRequest) ProtoMessage() {}
+
+func (x *ListEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type ListEventsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsResponse) Reset() {
+	*x = ListEventsResponse{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsResponse) ProtoMessage() {}
+
+func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListEventsResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type CancelEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelEventRequest) Reset() {
+	*x = CancelEventRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelEventRequest) ProtoMessage() {}
+
+func (x *CancelEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelEventRequest.ProtoReflect.Descriptor instead.
+func (*CancelEventRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelEventRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelEventResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CancelledBookings int64                  `protobuf:"varint,1,opt,name=cancelled_bookings,json=cancelledBookings,proto3" json:"cancelled_bookings,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CancelEventResponse) Reset() {
+	*x = CancelEventResponse{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelEventResponse) ProtoMessage() {}
+
+func (x *CancelEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelEventResponse.ProtoReflect.Descriptor instead.
+func (*CancelEventResponse) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelEventResponse) GetCancelledBookings() int64 {
+	if x != nil {
+		return x.CancelledBookings
+	}
+	return 0
+}
+
+type WatchEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventRequest) Reset() {
+	*x = WatchEventRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventRequest) ProtoMessage() {}
+
+func (x *WatchEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchEventRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	TelegramId    int64                  `protobuf:"varint,3,opt,name=telegram_id,json=telegramId,proto3" json:"telegram_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetTelegramId() int64 {
+	if x != nil {
+		return x.TelegramId
+	}
+	return 0
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type BookEventRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Email of a registered user.
+	Email         string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookEventRequest) Reset() {
+	*x = BookEventRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookEventRequest) ProtoMessage() {}
+
+func (x *BookEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookEventRequest.ProtoReflect.Descriptor instead.
+func (*BookEventRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BookEventRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *BookEventRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type BookEventResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookingId string                 `protobuf:"bytes,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	// Confirmation deadline; not set for events without payment.
+	Deadline      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookEventResponse) Reset() {
+	*x = BookEventResponse{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookEventResponse) ProtoMessage() {}
+
+func (x *BookEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookEventResponse.ProtoReflect.Descriptor instead.
+func (*BookEventResponse) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BookEventResponse) GetBookingId() string {
+	if x != nil {
+		return x.BookingId
+	}
+	return ""
+}
+
+func (x *BookEventResponse) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+type ConfirmBookingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	BookingId     string                 `protobuf:"bytes,2,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmBookingRequest) Reset() {
+	*x = ConfirmBookingRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmBookingRequest) ProtoMessage() {}
+
+func (x *ConfirmBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmBookingRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmBookingRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ConfirmBookingRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ConfirmBookingRequest) GetBookingId() string {
+	if x != nil {
+		return x.BookingId
+	}
+	return ""
+}
+
+type ConfirmBookingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmBookingResponse) Reset() {
+	*x = ConfirmBookingResponse{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmBookingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmBookingResponse) ProtoMessage() {}
+
+func (x *ConfirmBookingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmBookingResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmBookingResponse) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{16}
+}
+
+type CancelBookingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	BookingId     string                 `protobuf:"bytes,2,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelBookingRequest) Reset() {
+	*x = CancelBookingRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBookingRequest) ProtoMessage() {}
+
+func (x *CancelBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBookingRequest.ProtoReflect.Descriptor instead.
+func (*CancelBookingRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelBookingRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *CancelBookingRequest) GetBookingId() string {
+	if x != nil {
+		return x.BookingId
+	}
+	return ""
+}
+
+type CancelBookingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelBookingResponse) Reset() {
+	*x = CancelBookingResponse{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelBookingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBookingResponse) ProtoMessage() {}
+
+func (x *CancelBookingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBookingResponse.ProtoReflect.Descriptor instead.
+func (*CancelBookingResponse) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{18}
+}
+
+type SubscribeToSalesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Email of a registered user.
+	Email         string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeToSalesRequest) Reset() {
+	*x = SubscribeToSalesRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeToSalesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToSalesRequest) ProtoMessage() {}
+
+func (x *SubscribeToSalesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToSalesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToSalesRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SubscribeToSalesRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *SubscribeToSalesRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type SubscribeToSalesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeToSalesResponse) Reset() {
+	*x = SubscribeToSalesResponse{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeToSalesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToSalesResponse) ProtoMessage() {}
+
+func (x *SubscribeToSalesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToSalesResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeToSalesResponse) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{20}
+}
+
+type ListBookingsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// 50 if not set, at most 500.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookingsRequest) Reset() {
+	*x = ListBookingsRequest{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookingsRequest) ProtoMessage() {}
+
+func (x *ListBookingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookingsRequest.ProtoReflect.Descriptor instead.
+func (*ListBookingsRequest) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListBookingsRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ListBookingsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBookingsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListBookingsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Bookings []*Booking             `protobuf:"bytes,1,rep,name=bookings,proto3" json:"bookings,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookingsResponse) Reset() {
+	*x = ListBookingsResponse{}
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookingsResponse) ProtoMessage() {}
+
+func (x *ListBookingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbooker_v1_event_booker_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookingsResponse.ProtoReflect.Descriptor instead.
+func (*ListBookingsResponse) Descriptor() ([]byte, []int) {
+	return file_eventbooker_v1_event_booker_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListBookingsResponse) GetBookings() []*Booking {
+	if x != nil {
+		return x.Bookings
+	}
+	return nil
+}
+
+func (x *ListBookingsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_eventbooker_v1_event_booker_proto protoreflect.FileDescriptor
+
+const file_eventbooker_v1_event_booker_proto_rawDesc = "" +
+	"\n" +
+	"!eventbooker/v1/event_booker.proto\x12\x0eeventbooker.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdb\a\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12.\n" +
+	"\x04date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x1f\n" +
+	"\vtotal_seats\x18\x04 \x01(\x05R\n" +
+	"totalSeats\x12%\n" +
+	"\x0ereserved_seats\x18\x05 \x01(\x05R\rreservedSeats\x12!\n" +
+	"\fbooked_seats\x18\x06 \x01(\x05R\vbookedSeats\x12'\n" +
+	"\x0favailable_seats\x18\a \x01(\x05R\x0eavailableSeats\x128\n" +
+	"\x18booking_lifetime_minutes\x18\b \x01(\x05R\x16bookingLifetimeMinutes\x12B\n" +
+	"\x1drequires_payment_confirmation\x18\t \x01(\bR\x1brequiresPaymentConfirmation\x127\n" +
+	"\tinventory\x18\n" +
+	" \x01(\x0e2\x19.eventbooker.v1.InventoryR\tinventory\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fcancelled_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x12 \n" +
+	"\vdescription\x18\r \x01(\tR\vdescription\x12\x1a\n" +
+	"\bcategory\x18\x0e \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x12+\n" +
+	"\x11organizer_contact\x18\x10 \x01(\tR\x10organizerContact\x12&\n" +
+	"\x0fcover_image_url\x18\x11 \x01(\tR\rcoverImageUrl\x125\n" +
+	"\bend_date\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x19\n" +
+	"\bvenue_id\x18\x13 \x01(\tR\avenueId\x12\x1b\n" +
+	"\ttime_zone\x18\x14 \x01(\tR\btimeZone\x12@\n" +
+	"\x0esales_close_at\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\fsalesCloseAt\x12>\n" +
+	"\rsales_open_at\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\vsalesOpenAt\x12!\n" +
+	"\fsales_status\x18\x17 \x01(\tR\vsalesStatus\"\xca\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1f\n" +
+	"\vtelegram_id\x18\x04 \x01(\x03R\n" +
+	"telegramId\x12,\n" +
+	"\x04role\x18\x05 \x01(\x0e2\x18.eventbooker.v1.UserRoleR\x04role\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb2\x02\n" +
+	"\aBooking\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x125\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1d.eventbooker.v1.BookingStatusR\x06status\x126\n" +
+	"\bdeadline\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xe0\x01\n" +
+	"\x11EventAvailability\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1f\n" +
+	"\vtotal_seats\x18\x02 \x01(\x05R\n" +
+	"totalSeats\x12%\n" +
+	"\x0ereserved_seats\x18\x03 \x01(\x05R\rreservedSeats\x12!\n" +
+	"\fbooked_seats\x18\x04 \x01(\x05R\vbookedSeats\x12'\n" +
+	"\x0favailable_seats\x18\x05 \x01(\x05R\x0eavailableSeats\x12\x1c\n" +
+	"\tcancelled\x18\x06 \x01(\bR\tcancelled\"\xc8\x05\n" +
+	"\x12CreateEventRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12.\n" +
+	"\x04date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x1f\n" +
+	"\vtotal_seats\x18\x03 \x01(\x05R\n" +
+	"totalSeats\x128\n" +
+	"\x18booking_lifetime_minutes\x18\x04 \x01(\x05R\x16bookingLifetimeMinutes\x12B\n" +
+	"\x1drequires_payment_confirmation\x18\x05 \x01(\bR\x1brequiresPaymentConfirmation\x127\n" +
+	"\tinventory\x18\x06 \x01(\x0e2\x19.eventbooker.v1.InventoryR\tinventory\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x1a\n" +
+	"\bcategory\x18\b \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x12+\n" +
+	"\x11organizer_contact\x18\n" +
+	" \x01(\tR\x10organizerContact\x12&\n" +
+	"\x0fcover_image_url\x18\v \x01(\tR\rcoverImageUrl\x125\n" +
+	"\bend_date\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x19\n" +
+	"\bvenue_id\x18\r \x01(\tR\avenueId\x12\x1b\n" +
+	"\ttime_zone\x18\x0e \x01(\tR\btimeZone\x12>\n" +
+	"\rsales_open_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\vsalesOpenAt\x12@\n" +
+	"\x0esales_close_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\fsalesCloseAt\"!\n" +
+	"\x0fGetEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x7f\n" +
+	"\x11ListEventsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\"k\n" +
+	"\x12ListEventsResponse\x12-\n" +
+	"\x06events\x18\x01 \x03(\v2\x15.eventbooker.v1.EventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"$\n" +
+	"\x12CancelEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
+	"\x13CancelEventResponse\x12-\n" +
+	"\x12cancelled_bookings\x18\x01 \x01(\x03R\x11cancelledBookings\"#\n" +
+	"\x11WatchEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"^\n" +
+	"\x11CreateUserRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1f\n" +
+	"\vtelegram_id\x18\x03 \x01(\x03R\n" +
+	"telegramId\"6\n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"C\n" +
+	"\x10BookEventRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"j\n" +
+	"\x11BookEventResponse\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\tR\tbookingId\x126\n" +
+	"\bdeadline\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\"Q\n" +
+	"\x15ConfirmBookingRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x02 \x01(\tR\tbookingId\"\x18\n" +
+	"\x16ConfirmBookingResponse\"P\n" +
+	"\x14CancelBookingRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x02 \x01(\tR\tbookingId\"\x17\n" +
+	"\x15CancelBookingResponse\"J\n" +
+	"\x17SubscribeToSalesRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"\x1a\n" +
+	"\x18SubscribeToSalesResponse\"l\n" +
+	"\x13ListBookingsRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"s\n" +
+	"\x14ListBookingsResponse\x123\n" +
+	"\bbookings\x18\x01 \x03(\v2\x17.eventbooker.v1.BookingR\bbookings\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*R\n" +
+	"\tInventory\x12\x19\n" +
+	"\x15INVENTORY_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11INVENTORY_COUNTER\x10\x01\x12\x13\n" +
+	"\x0fINVENTORY_SEATS\x10\x02*\x88\x01\n" +
+	"\rBookingStatus\x12\x1e\n" +
+	"\x1aBOOKING_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17BOOKING_STATUS_RESERVED\x10\x01\x12\x1c\n" +
+	"\x18BOOKING_STATUS_CONFIRMED\x10\x02\x12\x1c\n" +
+	"\x18BOOKING_STATUS_CANCELLED\x10\x03*N\n" +
+	"\bUserRole\x12\x19\n" +
+	"\x15USER_ROLE_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eUSER_ROLE_USER\x10\x01\x12\x13\n" +
+	"\x0fUSER_ROLE_ADMIN\x10\x022\xf9\a\n" +
+	"\vEventBooker\x12H\n" +
+	"\vCreateEvent\x12\".eventbooker.v1.CreateEventRequest\x1a\x15.eventbooker.v1.Event\x12B\n" +
+	"\bGetEvent\x12\x1f.eventbooker.v1.GetEventRequest\x1a\x15.eventbooker.v1.Event\x12S\n" +
+	"\n" +
+	"ListEvents\x12!.eventbooker.v1.ListEventsRequest\x1a\".eventbooker.v1.ListEventsResponse\x12V\n" +
+	"\vCancelEvent\x12\".eventbooker.v1.CancelEventRequest\x1a#.eventbooker.v1.CancelEventResponse\x12T\n" +
+	"\n" +
+	"WatchEvent\x12!.eventbooker.v1.WatchEventRequest\x1a!.eventbooker.v1.EventAvailability0\x01\x12E\n" +
+	"\n" +
+	"CreateUser\x12!.eventbooker.v1.CreateUserRequest\x1a\x14.eventbooker.v1.User\x12?\n" +
+	"\aGetUser\x12\x1e.eventbooker.v1.GetUserRequest\x1a\x14.eventbooker.v1.User\x12P\n" +
+	"\tBookEvent\x12 .eventbooker.v1.BookEventRequest\x1a!.eventbooker.v1.BookEventResponse\x12_\n" +
+	"\x0eConfirmBooking\x12%.eventbooker.v1.ConfirmBookingRequest\x1a&.eventbooker.v1.ConfirmBookingResponse\x12\\\n" +
+	"\rCancelBooking\x12$.eventbooker.v1.CancelBookingRequest\x1a%.eventbooker.v1.CancelBookingResponse\x12Y\n" +
+	"\fListBookings\x12#.eventbooker.v1.ListBookingsRequest\x1a$.eventbooker.v1.ListBookingsResponse\x12e\n" +
+	"\x10SubscribeToSales\x12'.eventbooker.v1.SubscribeToSalesRequest\x1a(.eventbooker.v1.SubscribeToSalesResponseBIZGgithub.com/kstsm/wb-event-booker/api/proto/eventbooker/v1;eventbookerv1b\x06proto3"
+
+var (
+	file_eventbooker_v1_event_booker_proto_rawDescOnce sync.Once
+	file_eventbooker_v1_event_booker_proto_rawDescData []byte
+)
+
+func file_eventbooker_v1_event_booker_proto_rawDescGZIP() []byte {
+	file_eventbooker_v1_event_booker_proto_rawDescOnce.Do(func() {
+		file_eventbooker_v1_event_booker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_eventbooker_v1_event_booker_proto_rawDesc), len(file_eventbooker_v1_event_booker_proto_rawDesc)))
+	})
+	return file_eventbooker_v1_event_booker_proto_rawDescData
+}
+
+var file_eventbooker_v1_event_booker_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_eventbooker_v1_event_booker_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_eventbooker_v1_event_booker_proto_goTypes = []any{
+	(Inventory)(0),                   // 0: eventbooker.v1.Inventory
+	(BookingStatus)(0),               // 1: eventbooker.v1.BookingStatus
+	(UserRole)(0),                    // 2: eventbooker.v1.UserRole
+	(*Event)(nil),                    // 3: eventbooker.v1.Event
+	(*User)(nil),                     // 4: eventbooker.v1.User
+	(*Booking)(nil),                  // 5: eventbooker.v1.Booking
+	(*EventAvailability)(nil),        // 6: eventbooker.v1.EventAvailability
+	(*CreateEventRequest)(nil),       // 7: eventbooker.v1.CreateEventRequest
+	(*GetEventRequest)(nil),          // 8: eventbooker.v1.GetEventRequest
+	(*ListEventsRequest)(nil),        // 9: eventbooker.v1.ListEventsRequest
+	(*ListEventsResponse)(nil),       // 10: eventbooker.v1.ListEventsResponse
+	(*CancelEventRequest)(nil),       // 11: eventbooker.v1.CancelEventRequest
+	(*CancelEventResponse)(nil),      // 12: eventbooker.v1.CancelEventResponse
+	(*WatchEventRequest)(nil),        // 13: eventbooker.v1.WatchEventRequest
+	(*CreateUserRequest)(nil),        // 14: eventbooker.v1.CreateUserRequest
+	(*GetUserRequest)(nil),           // 15: eventbooker.v1.GetUserRequest
+	(*BookEventRequest)(nil),         // 16: eventbooker.v1.BookEventRequest
+	(*BookEventResponse)(nil),        // 17: eventbooker.v1.BookEventResponse
+	(*ConfirmBookingRequest)(nil),    // 18: eventbooker.v1.ConfirmBookingRequest
+	(*ConfirmBookingResponse)(nil),   // 19: eventbooker.v1.ConfirmBookingResponse
+	(*CancelBookingRequest)(nil),     // 20: eventbooker.v1.CancelBookingRequest
+	(*CancelBookingResponse)(nil),    // 21: eventbooker.v1.CancelBookingResponse
+	(*SubscribeToSalesRequest)(nil),  // 22: eventbooker.v1.SubscribeToSalesRequest
+	(*SubscribeToSalesResponse)(nil), // 23: eventbooker.v1.SubscribeToSalesResponse
+	(*ListBookingsRequest)(nil),      // 24: eventbooker.v1.ListBookingsRequest
+	(*ListBookingsResponse)(nil),     // 25: eventbooker.v1.ListBookingsResponse
+	(*timestamppb.Timestamp)(nil),    // 26: google.protobuf.Timestamp
+}
+var file_eventbooker_v1_event_booker_proto_depIdxs = []int32{
+	26, // 0: eventbooker.v1.Event.date:type_name -> google.protobuf.Timestamp
+	0,  // 1: eventbooker.v1.Event.inventory:type_name -> eventbooker.v1.Inventory
+	26, // 2: eventbooker.v1.Event.created_at:type_name -> google.protobuf.Timestamp
+	26, // 3: eventbooker.v1.Event.cancelled_at:type_name -> google.protobuf.Timestamp
+	26, // 4: eventbooker.v1.Event.end_date:type_name -> google.protobuf.Timestamp
+	26, // 5: eventbooker.v1.Event.sales_close_at:type_name -> google.protobuf.Timestamp
+	26, // 6: eventbooker.v1.Event.sales_open_at:type_name -> google.protobuf.Timestamp
+	2,  // 7: eventbooker.v1.User.role:type_name -> eventbooker.v1.UserRole
+	26, // 8: eventbooker.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 9: eventbooker.v1.Booking.status:type_name -> eventbooker.v1.BookingStatus
+	26, // 10: eventbooker.v1.Booking.deadline:type_name -> google.protobuf.Timestamp
+	26, // 11: eventbooker.v1.Booking.created_at:type_name -> google.protobuf.Timestamp
+	26, // 12: eventbooker.v1.Booking.updated_at:type_name -> google.protobuf.Timestamp
+	26, // 13: eventbooker.v1.CreateEventRequest.date:type_name -> google.protobuf.Timestamp
+	0,  // 14: eventbooker.v1.CreateEventRequest.inventory:type_name -> eventbooker.v1.Inventory
+	26, // 15: eventbooker.v1.CreateEventRequest.end_date:type_name -> google.protobuf.Timestamp
+	26, // 16: eventbooker.v1.CreateEventRequest.sales_open_at:type_name -> google.protobuf.Timestamp
+	26, // 17: eventbooker.v1.CreateEventRequest.sales_close_at:type_name -> google.protobuf.Timestamp
+	3,  // 18: eventbooker.v1.ListEventsResponse.events:type_name -> eventbooker.v1.Event
+	26, // 19: eventbooker.v1.BookEventResponse.deadline:type_name -> google.protobuf.Timestamp
+	5,  // 20: eventbooker.v1.ListBookingsResponse.bookings:type_name -> eventbooker.v1.Booking
+	7,  // 21: eventbooker.v1.EventBooker.CreateEvent:input_type -> eventbooker.v1.CreateEventRequest
+	8,  // 22: eventbooker.v1.EventBooker.GetEvent:input_type -> eventbooker.v1.GetEventRequest
+	9,  // 23: eventbooker.v1.EventBooker.ListEvents:input_type -> eventbooker.v1.ListEventsRequest
+	11, // 24: eventbooker.v1.EventBooker.CancelEvent:input_type -> eventbooker.v1.CancelEventRequest
+	13, // 25: eventbooker.v1.EventBooker.WatchEvent:input_type -> eventbooker.v1.WatchEventRequest
+	14, // 26: eventbooker.v1.EventBooker.CreateUser:input_type -> eventbooker.v1.CreateUserRequest
+	15, // 27: eventbooker.v1.EventBooker.GetUser:input_type -> eventbooker.v1.GetUserRequest
+	16, // 28: eventbooker.v1.EventBooker.BookEvent:input_type -> eventbooker.v1.BookEventRequest
+	18, // 29: eventbooker.v1.EventBooker.ConfirmBooking:input_type -> eventbooker.v1.ConfirmBookingRequest
+	20, // 30: eventbooker.v1.EventBooker.CancelBooking:input_type -> eventbooker.v1.CancelBookingRequest
+	24, // 31: eventbooker.v1.EventBooker.ListBookings:input_type -> eventbooker.v1.ListBookingsRequest
+	22, // 32: eventbooker.v1.EventBooker.SubscribeToSales:input_type -> eventbooker.v1.SubscribeToSalesRequest
+	3,  // 33: eventbooker.v1.EventBooker.CreateEvent:output_type -> eventbooker.v1.Event
+	3,  // 34: eventbooker.v1.EventBooker.GetEvent:output_type -> eventbooker.v1.Event
+	10, // 35: eventbooker.v1.EventBooker.ListEvents:output_type -> eventbooker.v1.ListEventsResponse
+	12, // 36: eventbooker.v1.EventBooker.CancelEvent:output_type -> eventbooker.v1.CancelEventResponse
+	6,  // 37: eventbooker.v1.EventBooker.WatchEvent:output_type -> eventbooker.v1.EventAvailability
+	4,  // 38: eventbooker.v1.EventBooker.CreateUser:output_type -> eventbooker.v1.User
+	4,  // 39: eventbooker.v1.EventBooker.GetUser:output_type -> eventbooker.v1.User
+	17, // 40: eventbooker.v1.EventBooker.BookEvent:output_type -> eventbooker.v1.BookEventResponse
+	19, // 41: eventbooker.v1.EventBooker.ConfirmBooking:output_type -> eventbooker.v1.ConfirmBookingResponse
+	21, // 42: eventbooker.v1.EventBooker.CancelBooking:output_type -> eventbooker.v1.CancelBookingResponse
+	25, // 43: eventbooker.v1.EventBooker.ListBookings:output_type -> eventbooker.v1.ListBookingsResponse
+	23, // 44: eventbooker.v1.EventBooker.SubscribeToSales:output_type -> eventbooker.v1.SubscribeToSalesResponse
+	33, // [33:45] is the sub-list for method output_type
+	21, // [21:33] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_eventbooker_v1_event_booker_proto_init() }
+func file_eventbooker_v1_event_booker_proto_init() {
+	if File_eventbooker_v1_event_booker_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_eventbooker_v1_event_booker_proto_rawDesc), len(file_eventbooker_v1_event_booker_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_eventbooker_v1_event_booker_proto_goTypes,
+		DependencyIndexes: file_eventbooker_v1_event_booker_proto_depIdxs,
+		EnumInfos:         file_eventbooker_v1_event_booker_proto_enumTypes,
+		MessageInfos:      file_eventbooker_v1_event_booker_proto_msgTypes,
+	}.Build()
+	File_eventbooker_v1_event_booker_proto = out.File
+	file_eventbooker_v1_event_booker_proto_goTypes = nil
+	file_eventbooker_v1_event_booker_proto_depIdxs = nil
+}
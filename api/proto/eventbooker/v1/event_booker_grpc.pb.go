@@ -0,0 +1,567 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: eventbooker/v1/event_booker.proto
+
+package eventbookerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EventBooker_CreateEvent_FullMethodName      = "/eventbooker.v1.EventBooker/CreateEvent"
+	EventBooker_GetEvent_FullMethodName         = "/eventbooker.v1.EventBooker/GetEvent"
+	EventBooker_ListEvents_FullMethodName       = "/eventbooker.v1.EventBooker/ListEvents"
+	EventBooker_CancelEvent_FullMethodName      = "/eventbooker.v1.EventBooker/CancelEvent"
+	EventBooker_WatchEvent_FullMethodName       = "/eventbooker.v1.EventBooker/WatchEvent"
+	EventBooker_CreateUser_FullMethodName       = "/eventbooker.v1.EventBooker/CreateUser"
+	EventBooker_GetUser_FullMethodName          = "/eventbooker.v1.EventBooker/GetUser"
+	EventBooker_BookEvent_FullMethodName        = "/eventbooker.v1.EventBooker/BookEvent"
+	EventBooker_ConfirmBooking_FullMethodName   = "/eventbooker.v1.EventBooker/ConfirmBooking"
+	EventBooker_CancelBooking_FullMethodName    = "/eventbooker.v1.EventBooker/CancelBooking"
+	EventBooker_ListBookings_FullMethodName     = "/eventbooker.v1.EventBooker/ListBookings"
+	EventBooker_SubscribeToSales_FullMethodName = "/eventbooker.v1.EventBooker/SubscribeToSales"
+)
+
+// EventBookerClient is the client API for EventBooker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EventBooker exposes the events, users and bookings of the HTTP API to
+// internal services. Errors carry a google.rpc.ErrorInfo detail whose reason
+// is the error code of the HTTP API, e.g. "no_available_seats".
+type EventBookerClient interface {
+	CreateEvent(ctx context.Context, in *CreateEventRequest, opts ...grpc.CallOption) (*Event, error)
+	GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Event, error)
+	// ListEvents returns events ordered by date, a page at a time.
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	// CancelEvent cancels the event together with its active bookings.
+	CancelEvent(ctx context.Context, in *CancelEventRequest, opts ...grpc.CallOption) (*CancelEventResponse, error)
+	// WatchEvent sends the current availability of the event and then every
+	// change of it. The stream ends after the event is cancelled.
+	WatchEvent(ctx context.Context, in *WatchEventRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventAvailability], error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// GetUser looks a user up by id or, if id is empty, by email.
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	BookEvent(ctx context.Context, in *BookEventRequest, opts ...grpc.CallOption) (*BookEventResponse, error)
+	ConfirmBooking(ctx context.Context, in *ConfirmBookingRequest, opts ...grpc.CallOption) (*ConfirmBookingResponse, error)
+	// CancelBooking cancels a reserved or confirmed booking and frees its seat.
+	CancelBooking(ctx context.Context, in *CancelBookingRequest, opts ...grpc.CallOption) (*CancelBookingResponse, error)
+	ListBookings(ctx context.Context, in *ListBookingsRequest, opts ...grpc.CallOption) (*ListBookingsResponse, error)
+	// SubscribeToSales notifies the user over Telegram once ticket sales of
+	// the event open.
+	SubscribeToSales(ctx context.Context, in *SubscribeToSalesRequest, opts ...grpc.CallOption) (*SubscribeToSalesResponse, error)
+}
+
+type eventBookerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventBookerClient(cc grpc.ClientConnInterface) EventBookerClient {
+	return &eventBookerClient{cc}
+}
+
+func (c *eventBookerClient) CreateEvent(ctx context.Context, in *CreateEventRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, EventBooker_CreateEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, EventBooker_GetEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, EventBooker_ListEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) CancelEvent(ctx context.Context, in *CancelEventRequest, opts ...grpc.CallOption) (*CancelEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelEventResponse)
+	err := c.cc.Invoke(ctx, EventBooker_CancelEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) WatchEvent(ctx context.Context, in *WatchEventRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventAvailability], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventBooker_ServiceDesc.Streams[0], EventBooker_WatchEvent_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventRequest, EventAvailability]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventBooker_WatchEventClient = grpc.ServerStreamingClient[EventAvailability]
+
+func (c *eventBookerClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, EventBooker_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, EventBooker_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) BookEvent(ctx context.Context, in *BookEventRequest, opts ...grpc.CallOption) (*BookEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookEventResponse)
+	err := c.cc.Invoke(ctx, EventBooker_BookEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) ConfirmBooking(ctx context.Context, in *ConfirmBookingRequest, opts ...grpc.CallOption) (*ConfirmBookingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmBookingResponse)
+	err := c.cc.Invoke(ctx, EventBooker_ConfirmBooking_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) CancelBooking(ctx context.Context, in *CancelBookingRequest, opts ...grpc.CallOption) (*CancelBookingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelBookingResponse)
+	err := c.cc.Invoke(ctx, EventBooker_CancelBooking_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) ListBookings(ctx context.Context, in *ListBookingsRequest, opts ...grpc.CallOption) (*ListBookingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBookingsResponse)
+	err := c.cc.Invoke(ctx, EventBooker_ListBookings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventBookerClient) SubscribeToSales(ctx context.Context, in *SubscribeToSalesRequest, opts ...grpc.CallOption) (*SubscribeToSalesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscribeToSalesResponse)
+	err := c.cc.Invoke(ctx, EventBooker_SubscribeToSales_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EventBookerServer is the server API for EventBooker service.
+// All implementations must embed UnimplementedEventBookerServer
+// for forward compatibility.
+//
+// EventBooker exposes the events, users and bookings of the HTTP API to
+// internal services. Errors carry a google.rpc.ErrorInfo detail whose reason
+// is the error code of the HTTP API, e.g. "no_available_seats".
+type EventBookerServer interface {
+	CreateEvent(context.Context, *CreateEventRequest) (*Event, error)
+	GetEvent(context.Context, *GetEventRequest) (*Event, error)
+	// ListEvents returns events ordered by date, a page at a time.
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	// CancelEvent cancels the event together with its active bookings.
+	CancelEvent(context.Context, *CancelEventRequest) (*CancelEventResponse, error)
+	// WatchEvent sends the current availability of the event and then every
+	// change of it. The stream ends after the event is cancelled.
+	WatchEvent(*WatchEventRequest, grpc.ServerStreamingServer[EventAvailability]) error
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	// GetUser looks a user up by id or, if id is empty, by email.
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	BookEvent(context.Context, *BookEventRequest) (*BookEventResponse, error)
+	ConfirmBooking(context.Context, *ConfirmBookingRequest) (*ConfirmBookingResponse, error)
+	// CancelBooking cancels a reserved or confirmed booking and frees its seat.
+	CancelBooking(context.Context, *CancelBookingRequest) (*CancelBookingResponse, error)
+	ListBookings(context.Context, *ListBookingsRequest) (*ListBookingsResponse, error)
+	// SubscribeToSales notifies the user over Telegram once ticket sales of
+	// the event open.
+	SubscribeToSales(context.Context, *SubscribeToSalesRequest) (*SubscribeToSalesResponse, error)
+	mustEmbedUnimplementedEventBookerServer()
+}
+
+// UnimplementedEventBookerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEventBookerServer struct{}
+
+func (UnimplementedEventBookerServer) CreateEvent(context.Context, *CreateEventRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEvent not implemented")
+}
+func (UnimplementedEventBookerServer) GetEvent(context.Context, *GetEventRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvent not implemented")
+}
+func (UnimplementedEventBookerServer) ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (UnimplementedEventBookerServer) CancelEvent(context.Context, *CancelEventRequest) (*CancelEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelEvent not implemented")
+}
+func (UnimplementedEventBookerServer) WatchEvent(*WatchEventRequest, grpc.ServerStreamingServer[EventAvailability]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvent not implemented")
+}
+func (UnimplementedEventBookerServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedEventBookerServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedEventBookerServer) BookEvent(context.Context, *BookEventRequest) (*BookEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BookEvent not implemented")
+}
+func (UnimplementedEventBookerServer) ConfirmBooking(context.Context, *ConfirmBookingRequest) (*ConfirmBookingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmBooking not implemented")
+}
+func (UnimplementedEventBookerServer) CancelBooking(context.Context, *CancelBookingRequest) (*CancelBookingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelBooking not implemented")
+}
+func (UnimplementedEventBookerServer) ListBookings(context.Context, *ListBookingsRequest) (*ListBookingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBookings not implemented")
+}
+func (UnimplementedEventBookerServer) SubscribeToSales(context.Context, *SubscribeToSalesRequest) (*SubscribeToSalesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubscribeToSales not implemented")
+}
+func (UnimplementedEventBookerServer) mustEmbedUnimplementedEventBookerServer() {}
+func (UnimplementedEventBookerServer) testEmbeddedByValue()                     {}
+
+// UnsafeEventBookerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventBookerServer will
+// result in compilation errors.
+type UnsafeEventBookerServer interface {
+	mustEmbedUnimplementedEventBookerServer()
+}
+
+func RegisterEventBookerServer(s grpc.ServiceRegistrar, srv EventBookerServer) {
+	// If the following call pancis, it indicates UnimplementedEventBookerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EventBooker_ServiceDesc, srv)
+}
+
+func _EventBooker_CreateEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).CreateEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_CreateEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).CreateEvent(ctx, req.(*CreateEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_GetEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).GetEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_GetEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).GetEvent(ctx, req.(*GetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_ListEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_CancelEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).CancelEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_CancelEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).CancelEvent(ctx, req.(*CancelEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_WatchEvent_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventBookerServer).WatchEvent(m, &grpc.GenericServerStream[WatchEventRequest, EventAvailability]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventBooker_WatchEventServer = grpc.ServerStreamingServer[EventAvailability]
+
+func _EventBooker_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_BookEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).BookEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_BookEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).BookEvent(ctx, req.(*BookEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_ConfirmBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).ConfirmBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_ConfirmBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).ConfirmBooking(ctx, req.(*ConfirmBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_CancelBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).CancelBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_CancelBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).CancelBooking(ctx, req.(*CancelBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_ListBookings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBookingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).ListBookings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_ListBookings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).ListBookings(ctx, req.(*ListBookingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventBooker_SubscribeToSales_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeToSalesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventBookerServer).SubscribeToSales(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventBooker_SubscribeToSales_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventBookerServer).SubscribeToSales(ctx, req.(*SubscribeToSalesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EventBooker_ServiceDesc is the grpc.ServiceDesc for EventBooker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventBooker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "eventbooker.v1.EventBooker",
+	HandlerType: (*EventBookerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateEvent",
+			Handler:    _EventBooker_CreateEvent_Handler,
+		},
+		{
+			MethodName: "GetEvent",
+			Handler:    _EventBooker_GetEvent_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _EventBooker_ListEvents_Handler,
+		},
+		{
+			MethodName: "CancelEvent",
+			Handler:    _EventBooker_CancelEvent_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _EventBooker_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _EventBooker_GetUser_Handler,
+		},
+		{
+			MethodName: "BookEvent",
+			Handler:    _EventBooker_BookEvent_Handler,
+		},
+		{
+			MethodName: "ConfirmBooking",
+			Handler:    _EventBooker_ConfirmBooking_Handler,
+		},
+		{
+			MethodName: "CancelBooking",
+			Handler:    _EventBooker_CancelBooking_Handler,
+		},
+		{
+			MethodName: "ListBookings",
+			Handler:    _EventBooker_ListBookings_Handler,
+		},
+		{
+			MethodName: "SubscribeToSales",
+			Handler:    _EventBooker_SubscribeToSales_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvent",
+			Handler:       _EventBooker_WatchEvent_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "eventbooker/v1/event_booker.proto",
+}
@@ -0,0 +1,319 @@
+// Package client is a typed Go client for the EventBooker HTTP API described
+// by api/openapi.json.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	adminToken string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces http.DefaultClient, e.g. to set a timeout or a
+// transport.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// WithAdminToken authenticates the requests to the admin endpoints
+// (ListJobs, RunJob, ReconcileSeats) with the server's SRV_ADMIN_TOKEN.
+func WithAdminToken(token string) Option {
+	return func(cl *Client) {
+		cl.adminToken = token
+	}
+}
+
+// New returns a client for the server at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey makes the mutating request sent with ctx carry the
+// Idempotency-Key header, so that retrying it with the same key is safe.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func (c *Client) CreateEvent(ctx context.Context, req CreateEventRequest) (*Event, error) {
+	var resp struct {
+		Event *Event `json:"event"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/events", req, &resp); err != nil {
+		return nil, fmt.Errorf("Client-CreateEvent: %w", err)
+	}
+	return resp.Event, nil
+}
+
+func (c *Client) GetEvent(ctx context.Context, id uuid.UUID) (*Event, error) {
+	var resp struct {
+		Event *Event `json:"event"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/events/"+id.String(), nil, &resp); err != nil {
+		return nil, fmt.Errorf("Client-GetEvent: %w", err)
+	}
+	return resp.Event, nil
+}
+
+// ListEvents returns the events matching filter; the zero filter returns all
+// of them.
+func (c *Client) ListEvents(ctx context.Context, filter EventFilter) ([]Event, error) {
+	query := url.Values{}
+	if filter.Category != "" {
+		query.Set("category", string(filter.Category))
+	}
+	for _, tag := range filter.Tags {
+		query.Add("tag", tag)
+	}
+	path := "/api/v1/events"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var resp struct {
+		Events []Event `json:"events"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("Client-ListEvents: %w", err)
+	}
+	return resp.Events, nil
+}
+
+func (c *Client) CreateVenue(ctx context.Context, req VenueRequest) (*Venue, error) {
+	var resp struct {
+		Venue *Venue `json:"venue"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/venues", req, &resp); err != nil {
+		return nil, fmt.Errorf("Client-CreateVenue: %w", err)
+	}
+	return resp.Venue, nil
+}
+
+func (c *Client) GetVenue(ctx context.Context, id uuid.UUID) (*Venue, error) {
+	var resp struct {
+		Venue *Venue `json:"venue"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/venues/"+id.String(), nil, &resp); err != nil {
+		return nil, fmt.Errorf("Client-GetVenue: %w", err)
+	}
+	return resp.Venue, nil
+}
+
+func (c *Client) ListVenues(ctx context.Context) ([]Venue, error) {
+	var resp struct {
+		Venues []Venue `json:"venues"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/venues", nil, &resp); err != nil {
+		return nil, fmt.Errorf("Client-ListVenues: %w", err)
+	}
+	return resp.Venues, nil
+}
+
+func (c *Client) UpdateVenue(ctx context.Context, id uuid.UUID, req VenueRequest) (*Venue, error) {
+	var resp struct {
+		Venue *Venue `json:"venue"`
+	}
+	if err := c.do(ctx, http.MethodPut, "/api/v1/venues/"+id.String(), req, &resp); err != nil {
+		return nil, fmt.Errorf("Client-UpdateVenue: %w", err)
+	}
+	return resp.Venue, nil
+}
+
+func (c *Client) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/venues/"+id.String(), nil, nil); err != nil {
+		return fmt.Errorf("Client-DeleteVenue: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) BookEvent(ctx context.Context, eventID uuid.UUID, req BookEventRequest) (*BookEventResponse, error) {
+	var resp BookEventResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/events/"+eventID.String()+"/book", req, &resp); err != nil {
+		return nil, fmt.Errorf("Client-BookEvent: %w", err)
+	}
+	return &resp, nil
+}
+
+func (c *Client) ConfirmBooking(ctx context.Context, eventID uuid.UUID, req ConfirmBookingRequest) error {
+	if err := c.do(ctx, http.MethodPost, "/api/v1/events/"+eventID.String()+"/confirm", req, nil); err != nil {
+		return fmt.Errorf("Client-ConfirmBooking: %w", err)
+	}
+	return nil
+}
+
+// SubscribeToSales asks for a Telegram notification to the user once ticket
+// sales of the event open.
+func (c *Client) SubscribeToSales(ctx context.Context, eventID uuid.UUID, req SubscribeRequest) error {
+	if err := c.do(ctx, http.MethodPost, "/api/v1/events/"+eventID.String()+"/subscribe", req, nil); err != nil {
+		return fmt.Errorf("Client-SubscribeToSales: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) ListBookings(ctx context.Context, eventID uuid.UUID) ([]Booking, error) {
+	var resp struct {
+		Bookings []Booking `json:"bookings"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/events/"+eventID.String()+"/bookings", nil, &resp); err != nil {
+		return nil, fmt.Errorf("Client-ListBookings: %w", err)
+	}
+	return resp.Bookings, nil
+}
+
+func (c *Client) CreateUser(ctx context.Context, req CreateUserRequest) (*User, error) {
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/users", req, &resp); err != nil {
+		return nil, fmt.Errorf("Client-CreateUser: %w", err)
+	}
+	return resp.User, nil
+}
+
+// ListJobs returns the scheduler jobs and up to limit recent runs; 0 leaves
+// the limit to the server.
+func (c *Client) ListJobs(ctx context.Context, limit int) (*ListJobsResponse, error) {
+	path := "/api/v1/admin/jobs"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+
+	var resp ListJobsResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("Client-ListJobs: %w", err)
+	}
+	return &resp, nil
+}
+
+func (c *Client) RunJob(ctx context.Context, name string) error {
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/jobs/"+url.PathEscape(name)+"/run", nil, nil); err != nil {
+		return fmt.Errorf("Client-RunJob: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) ReconcileSeats(ctx context.Context, repair bool) (*SeatReconciliation, error) {
+	var resp struct {
+		Reconciliation *SeatReconciliation `json:"reconciliation"`
+	}
+	path := "/api/v1/admin/reconcile?repair=" + strconv.FormatBool(repair)
+	if err := c.do(ctx, http.MethodPost, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("Client-ReconcileSeats: %w", err)
+	}
+	return resp.Reconciliation, nil
+}
+
+// Ready returns the readiness of the server; the individual checks are only
+// included with the admin token. When a dependency is down the server answers
+// 503; the result is then returned together with the error.
+func (c *Client) Ready(ctx context.Context) (*Health, error) {
+	var resp Health
+	if err := c.do(ctx, http.MethodGet, "/readyz", nil, &resp); err != nil {
+		if resp.Status == "" {
+			return nil, fmt.Errorf("Client-Ready: %w", err)
+		}
+		return &resp, fmt.Errorf("Client-Ready: %w", err)
+	}
+	return &resp, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key, ok := ctx.Value(idempotencyKey{}).(string); ok && key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if c.adminToken != "" && (strings.HasPrefix(path, "/api/v1/admin/") || path == "/readyz") {
+		req.Header.Set("Authorization", "Bearer "+c.adminToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("httpClient.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if resp.StatusCode >= http.StatusBadRequest {
+		if strings.HasPrefix(contentType, "application/problem+json") {
+			return decodeProblem(resp, data)
+		}
+		// Error pages of proxies are not JSON at all; a JSON body such as the
+		// checks of a failing /readyz is passed on as far as it decodes.
+		if out != nil && strings.HasPrefix(contentType, "application/json") {
+			_ = json.Unmarshal(data, out)
+		}
+		return &Problem{
+			Status: resp.StatusCode,
+			Title:  http.StatusText(resp.StatusCode),
+			Code:   "http_" + strconv.Itoa(resp.StatusCode),
+		}
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("json.Unmarshal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func decodeProblem(resp *http.Response, data []byte) error {
+	p := &Problem{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if p.Status == 0 {
+		p.Status = resp.StatusCode
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		p.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return p
+}
@@ -0,0 +1,380 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/client"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/handler"
+	"github.com/kstsm/wb-event-booker/internal/health"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/scheduler"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const adminToken = "secret"
+
+func newClient(t *testing.T) *client.Client {
+	t.Helper()
+
+	srv := newServer(t)
+	return client.New(srv.URL+"/", client.WithHTTPClient(srv.Client()), client.WithAdminToken(adminToken))
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	repo := memory.NewRepository()
+	sched := scheduler.NewScheduler(repo)
+	err := sched.Register(scheduler.Job{Name: "noop", Spec: "@every 1h", Task: func(ctx context.Context) error { return nil }})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	t.Cleanup(sched.Stop)
+
+	svc := service.NewService(repo, config.BookingConfig{})
+	h := handler.NewHandler(
+		svc,
+		sched,
+		health.NewChecker(),
+		availability.NewFeed(availability.NewHub(), svc.GetEventByID),
+		config.Server{IdempotencyTTLHours: 24, AdminToken: adminToken},
+		config.RateLimitConfig{},
+	)
+	srv := httptest.NewServer(h.NewRouter())
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestBookingFlow(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	user, err := c.CreateUser(ctx, client.CreateUserRequest{Name: "Ivan", Email: "ivan@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.ID == uuid.Nil || user.Email != "ivan@example.com" {
+		t.Fatalf("CreateUser = %+v", user)
+	}
+
+	event, err := c.CreateEvent(ctx, client.CreateEventRequest{
+		Name:                 "Concert",
+		Date:                 time.Now().Add(48 * time.Hour),
+		TotalSeats:           2,
+		BookingLifetimeHours: 1,
+		PaymentReq:           true,
+		Category:             client.EventCategoryConcert,
+		Tags:                 []string{"Jazz", "live"},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if event.Inventory != client.InventoryCounter || event.BookingLifetime != 60 ||
+		event.Category != client.EventCategoryConcert || len(event.Tags) != 2 || event.Tags[0] != "jazz" {
+		t.Fatalf("CreateEvent = %+v", event)
+	}
+
+	booked, err := c.BookEvent(client.WithIdempotencyKey(ctx, "book-1"), event.ID, client.BookEventRequest{Email: user.Email})
+	if err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+	if booked.Deadline == nil || booked.Deadline.Before(time.Now()) {
+		t.Fatalf("BookEvent deadline = %v", booked.Deadline)
+	}
+
+	replayed, err := c.BookEvent(client.WithIdempotencyKey(ctx, "book-1"), event.ID, client.BookEventRequest{Email: user.Email})
+	if err != nil {
+		t.Fatalf("replayed BookEvent: %v", err)
+	}
+	if replayed.BookingID != booked.BookingID {
+		t.Errorf("replayed booking = %s, want %s", replayed.BookingID, booked.BookingID)
+	}
+
+	if err := c.ConfirmBooking(ctx, event.ID, client.ConfirmBookingRequest{BookingID: booked.BookingID}); err != nil {
+		t.Fatalf("ConfirmBooking: %v", err)
+	}
+
+	event, err = c.GetEvent(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetEvent: %v", err)
+	}
+	if event.BookedSeats != 1 || event.ReservedSeats != 0 {
+		t.Errorf("seats = booked %d, reserved %d", event.BookedSeats, event.ReservedSeats)
+	}
+
+	bookings, err := c.ListBookings(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("ListBookings: %v", err)
+	}
+	if len(bookings) != 1 || bookings[0].Status != client.BookingStatusConfirmed {
+		t.Errorf("ListBookings = %+v", bookings)
+	}
+
+	events, err := c.ListEvents(ctx, client.EventFilter{})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != event.ID {
+		t.Errorf("ListEvents = %+v", events)
+	}
+
+	events, err = c.ListEvents(ctx, client.EventFilter{Category: client.EventCategoryConcert, Tags: []string{"jazz", "live"}})
+	if err != nil {
+		t.Fatalf("ListEvents with filter: %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("ListEvents with filter = %+v", events)
+	}
+
+	events, err = c.ListEvents(ctx, client.EventFilter{Tags: []string{"rock"}})
+	if err != nil {
+		t.Fatalf("ListEvents with filter: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("ListEvents by a missing tag = %+v", events)
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	srv := newServer(t)
+	c := client.New(srv.URL, client.WithHTTPClient(srv.Client()), client.WithAdminToken(adminToken))
+	ctx := context.Background()
+
+	anonymous := client.New(srv.URL, client.WithHTTPClient(srv.Client()))
+	if _, err := anonymous.ListJobs(ctx, 0); !client.HasCode(err, client.CodeUnauthorized) {
+		t.Fatalf("ListJobs without a token: %v, want %s", err, client.CodeUnauthorized)
+	}
+
+	if err := c.RunJob(ctx, "noop"); err != nil {
+		t.Fatalf("RunJob: %v", err)
+	}
+	jobs, err := c.ListJobs(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if len(jobs.Jobs) != 1 || jobs.Jobs[0].Name != "noop" {
+		t.Errorf("ListJobs = %+v", jobs)
+	}
+
+	report, err := c.ReconcileSeats(ctx, false)
+	if err != nil {
+		t.Fatalf("ReconcileSeats: %v", err)
+	}
+	if report.Repair || len(report.Drifts) != 0 {
+		t.Errorf("ReconcileSeats = %+v", report)
+	}
+
+	ready, err := c.Ready(ctx)
+	if err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+	if ready.Status != "ok" {
+		t.Errorf("Ready = %+v", ready)
+	}
+}
+
+func TestVenues(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	venue, err := c.CreateVenue(ctx, client.VenueRequest{
+		Name:            "Main hall",
+		Address:         "Moscow, Tverskaya 1",
+		Latitude:        55.757,
+		Longitude:       37.615,
+		TimeZone:        "Europe/Moscow",
+		DefaultCapacity: 300,
+	})
+	if err != nil {
+		t.Fatalf("CreateVenue: %v", err)
+	}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	end := start.Add(3 * time.Hour)
+	event, err := c.CreateEvent(ctx, client.CreateEventRequest{
+		Name:    "Concert",
+		Date:    start,
+		EndDate: &end,
+		VenueID: &venue.ID,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if event.TotalSeats != 300 || event.VenueID == nil || *event.VenueID != venue.ID || !event.EndDate.Equal(end) {
+		t.Errorf("CreateEvent = %+v", event)
+	}
+	if _, offset := event.LocalDate.Zone(); event.TimeZone != "Europe/Moscow" || offset != 3*60*60 || !event.LocalDate.Equal(start) {
+		t.Errorf("event time zone %q, local start %v", event.TimeZone, event.LocalDate)
+	}
+
+	_, err = c.CreateEvent(ctx, client.CreateEventRequest{
+		Name:       "Lecture",
+		Date:       start.Add(time.Hour),
+		TotalSeats: 10,
+		VenueID:    &venue.ID,
+	})
+	if !client.HasCode(err, client.CodeVenueUnavailable) {
+		t.Errorf("overlapping CreateEvent error = %v", err)
+	}
+
+	updated, err := c.UpdateVenue(ctx, venue.ID, client.VenueRequest{
+		Name:            "Small hall",
+		Address:         venue.Address,
+		Latitude:        venue.Latitude,
+		Longitude:       venue.Longitude,
+		TimeZone:        venue.TimeZone,
+		DefaultCapacity: 50,
+	})
+	if err != nil {
+		t.Fatalf("UpdateVenue: %v", err)
+	}
+	if updated.Name != "Small hall" || updated.DefaultCapacity != 50 {
+		t.Errorf("UpdateVenue = %+v", updated)
+	}
+
+	venues, err := c.ListVenues(ctx)
+	if err != nil {
+		t.Fatalf("ListVenues: %v", err)
+	}
+	if len(venues) != 1 || venues[0].ID != venue.ID {
+		t.Errorf("ListVenues = %+v", venues)
+	}
+
+	if err := c.DeleteVenue(ctx, venue.ID); !client.HasCode(err, client.CodeVenueInUse) {
+		t.Errorf("DeleteVenue error = %v", err)
+	}
+}
+
+func TestSalesWindow(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	if _, err := c.CreateUser(ctx, client.CreateUserRequest{Name: "Anna", Email: "anna@example.com"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	opensAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	event, err := c.CreateEvent(ctx, client.CreateEventRequest{
+		Name:        "Concert",
+		Date:        time.Now().Add(48 * time.Hour),
+		TotalSeats:  2,
+		SalesOpenAt: &opensAt,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if event.SalesStatus != client.SalesUpcoming || event.SalesOpenAt == nil || !event.SalesOpenAt.Equal(opensAt) ||
+		event.SalesCountdownSeconds == nil || !event.BookingClosesAt.Equal(event.SalesCloseAt) {
+		t.Fatalf("CreateEvent = %+v", event)
+	}
+
+	_, err = c.BookEvent(ctx, event.ID, client.BookEventRequest{Email: "anna@example.com"})
+	if !client.HasCode(err, client.CodeSalesNotOpen) {
+		t.Errorf("BookEvent error = %v, want sales_not_open", err)
+	}
+
+	if err := c.SubscribeToSales(ctx, event.ID, client.SubscribeRequest{Email: "anna@example.com"}); err != nil {
+		t.Errorf("SubscribeToSales: %v", err)
+	}
+}
+
+func TestProblems(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	_, err := c.GetEvent(ctx, uuid.New())
+	var p *client.Problem
+	if !errors.As(err, &p) {
+		t.Fatalf("GetEvent error = %v, want *client.Problem", err)
+	}
+	if p.Status != http.StatusNotFound || p.Code != client.CodeEventNotFound || p.Instance == "" {
+		t.Errorf("problem = %+v", p)
+	}
+
+	_, err = c.CreateUser(ctx, client.CreateUserRequest{Name: "1", Email: "bad"})
+	if !errors.As(err, &p) || p.Code != client.CodeValidationFailed {
+		t.Fatalf("CreateUser error = %v, want validation_failed", err)
+	}
+	var fields []string
+	for _, f := range p.Errors {
+		fields = append(fields, f.Field)
+	}
+	if !slices.Equal(fields, []string{"name", "email"}) {
+		t.Errorf("rejected fields = %v", fields)
+	}
+
+	if _, err := c.CreateUser(ctx, client.CreateUserRequest{Name: "Anna", Email: "anna@example.com"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	_, err = c.CreateUser(ctx, client.CreateUserRequest{Name: "Anna", Email: "anna@example.com"})
+	if !client.HasCode(err, client.CodeEmailAlreadyExists) {
+		t.Errorf("duplicate CreateUser error = %v", err)
+	}
+
+	if err := c.RunJob(ctx, "missing"); !client.HasCode(err, client.CodeJobNotFound) {
+		t.Errorf("RunJob error = %v", err)
+	}
+}
+
+func TestProblemFromProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+	}))
+	t.Cleanup(srv.Close)
+	c := client.New(srv.URL, client.WithHTTPClient(srv.Client()))
+
+	_, err := c.GetEvent(context.Background(), uuid.New())
+	var p *client.Problem
+	if !errors.As(err, &p) {
+		t.Fatalf("GetEvent error = %v, want *client.Problem", err)
+	}
+	if p.Status != http.StatusBadGateway || p.Code != "http_502" {
+		t.Errorf("problem = %+v", p)
+	}
+}
+
+// TestCodesMatchCatalogue keeps the Code constants of problem.go in step with
+// the server's error catalogue.
+func TestCodesMatchCatalogue(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "problem.go", nil, 0)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	var codes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || !strings.HasPrefix(spec.Names[0].Name, "Code") {
+			return true
+		}
+		lit := spec.Values[0].(*ast.BasicLit)
+		code, _ := strconv.Unquote(lit.Value)
+		codes = append(codes, code)
+		return true
+	})
+
+	var want []string
+	for _, entry := range apperrors.Catalogue() {
+		want = append(want, entry.Code)
+	}
+	if !slices.Equal(codes, want) {
+		t.Errorf("client codes = %v, want %v", codes, want)
+	}
+}
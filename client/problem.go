@@ -0,0 +1,72 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Error codes the API reports in Problem.Code.
+const (
+	CodeValidationFailed         = "validation_failed"
+	CodeInvalidRequestBody       = "invalid_request_body"
+	CodeRequestBodyTooLarge      = "request_body_too_large"
+	CodeRouteNotFound            = "route_not_found"
+	CodeMethodNotAllowed         = "method_not_allowed"
+	CodeRateLimited              = "rate_limited"
+	CodeUnauthorized             = "unauthorized"
+	CodeAdminDisabled            = "admin_disabled"
+	CodeEventNotFound            = "event_not_found"
+	CodeUserNotFound             = "user_not_found"
+	CodeBookingNotFound          = "booking_not_found"
+	CodeVenueNotFound            = "venue_not_found"
+	CodeJobNotFound              = "job_not_found"
+	CodeNoAvailableSeats         = "no_available_seats"
+	CodeAlreadyBooked            = "already_booked"
+	CodeBookingAlreadyCancelled  = "booking_already_cancelled"
+	CodeTooManyReservations      = "too_many_reservations"
+	CodeEmailAlreadyExists       = "email_already_exists"
+	CodeTelegramIDAlreadyExists  = "telegram_id_already_exists"
+	CodeVenueUnavailable         = "venue_unavailable"
+	CodeVenueInUse               = "venue_in_use"
+	CodeJobAlreadyRunning        = "job_already_running"
+	CodeIdempotencyKeyInProgress = "idempotency_key_in_progress"
+	CodeIdempotencyKeyMismatch   = "idempotency_key_mismatch"
+	CodeBookingNotReserved       = "booking_not_reserved"
+	CodeBookingDeadlinePassed    = "booking_deadline_passed"
+	CodePaymentNotRequired       = "payment_not_required"
+	CodeEventExpired             = "event_expired"
+	CodeEventCancelled           = "event_cancelled"
+	CodeSalesNotOpen             = "sales_not_open"
+	CodeSalesClosed              = "sales_closed"
+	CodeResourceBusy             = "resource_busy"
+	CodeInternalError            = "internal_error"
+)
+
+// Problem is the RFC 7807 error document returned by the API. Every method of
+// Client returns it as the error when the server answers with an error status.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+
+	// RetryAfter is taken from the Retry-After header of 429 and 503 responses.
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return fmt.Sprintf("%d %s: %s", p.Status, p.Code, p.Detail)
+	}
+	return fmt.Sprintf("%d %s", p.Status, p.Code)
+}
+
+// HasCode reports whether err is a Problem with the given code.
+func HasCode(err error, code string) bool {
+	var p *Problem
+	return errors.As(err, &p) && p.Code == code
+}
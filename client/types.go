@@ -0,0 +1,235 @@
+package client
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// The types below mirror the schemas of api/openapi.json; the api package
+// tests fail when they drift apart.
+
+type Inventory string
+
+const (
+	InventoryCounter Inventory = "counter"
+	InventorySeats   Inventory = "seats"
+)
+
+type EventCategory string
+
+const (
+	EventCategoryConcert    EventCategory = "concert"
+	EventCategoryConference EventCategory = "conference"
+	EventCategoryMeetup     EventCategory = "meetup"
+	EventCategoryWorkshop   EventCategory = "workshop"
+	EventCategoryTheatre    EventCategory = "theatre"
+	EventCategorySport      EventCategory = "sport"
+	EventCategoryExhibition EventCategory = "exhibition"
+	EventCategoryOther      EventCategory = "other"
+)
+
+type SalesStatus string
+
+const (
+	SalesUpcoming SalesStatus = "upcoming"
+	SalesOpen     SalesStatus = "open"
+	SalesClosed   SalesStatus = "closed"
+)
+
+type BookingStatus string
+
+const (
+	BookingStatusReserved  BookingStatus = "reserved"
+	BookingStatusConfirmed BookingStatus = "confirmed"
+	BookingStatusCancelled BookingStatus = "cancelled"
+)
+
+type CreateEventRequest struct {
+	Name                   string    `json:"name"`
+	Date                   time.Time `json:"date"`
+	TotalSeats             int       `json:"total_seats"`
+	BookingLifetimeHours   int       `json:"booking_lifetime_hours"`
+	BookingLifetimeMinutes int       `json:"booking_lifetime_minutes"`
+	PaymentReq             bool      `json:"requires_payment_confirmation"`
+	Inventory              Inventory `json:"inventory,omitempty"`
+
+	Description      string        `json:"description,omitempty"`
+	Category         EventCategory `json:"category,omitempty"`
+	Tags             []string      `json:"tags,omitempty"`
+	OrganizerContact string        `json:"organizer_contact,omitempty"`
+	CoverImageURL    string        `json:"cover_image_url,omitempty"`
+
+	// EndDate defaults to two hours after Date. With VenueID set, a zero
+	// TotalSeats takes the default capacity of the venue.
+	EndDate  *time.Time `json:"end_date,omitempty"`
+	VenueID  *uuid.UUID `json:"venue_id,omitempty"`
+	TimeZone string     `json:"time_zone,omitempty"`
+
+	// Sales open on creation unless SalesOpenAt is set and close the
+	// server's configured time before Date unless SalesCloseAt is set.
+	SalesOpenAt  *time.Time `json:"sales_open_at,omitempty"`
+	SalesCloseAt *time.Time `json:"sales_close_at,omitempty"`
+}
+
+// EventFilter narrows down ListEvents; an event matches Tags if it has all
+// of them.
+type EventFilter struct {
+	Category EventCategory
+	Tags     []string
+}
+
+type CreateUserRequest struct {
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	TelegramID *int64 `json:"telegram_id,omitempty"`
+}
+
+type BookEventRequest struct {
+	Email string `json:"email"`
+}
+
+type SubscribeRequest struct {
+	Email string `json:"email"`
+}
+
+type ConfirmBookingRequest struct {
+	BookingID uuid.UUID `json:"booking_id"`
+}
+
+type Event struct {
+	ID               uuid.UUID     `json:"id"`
+	Name             string        `json:"name"`
+	Description      string        `json:"description"`
+	Category         EventCategory `json:"category"`
+	Tags             []string      `json:"tags"`
+	OrganizerContact string        `json:"organizer_contact,omitempty"`
+	CoverImageURL    string        `json:"cover_image_url,omitempty"`
+	VenueID          *uuid.UUID    `json:"venue_id,omitempty"`
+	Date             time.Time     `json:"date"`
+	EndDate          time.Time     `json:"end_date"`
+	TimeZone         string        `json:"time_zone"`
+	LocalDate        time.Time     `json:"local_date"`
+	LocalEndDate     time.Time     `json:"local_end_date"`
+	// Deprecated: BookingClosesAt is the former name of SalesCloseAt.
+	BookingClosesAt time.Time   `json:"booking_closes_at"`
+	SalesOpenAt     *time.Time  `json:"sales_open_at,omitempty"`
+	SalesCloseAt    time.Time   `json:"sales_close_at"`
+	SalesStatus     SalesStatus `json:"sales_status"`
+	// SalesCountdownSeconds counts down to the opening or closing of the
+	// sales as of the response; it is nil once they have closed.
+	SalesCountdownSeconds *int64     `json:"sales_countdown_seconds,omitempty"`
+	TotalSeats            int        `json:"total_seats"`
+	ReservedSeats         int        `json:"reserved_seats"`
+	BookedSeats           int        `json:"booked_seats"`
+	BookingLifetime       int        `json:"booking_lifetime"`
+	PaymentReq            bool       `json:"requires_payment_confirmation"`
+	Inventory             Inventory  `json:"inventory"`
+	CreatedAt             time.Time  `json:"created_at"`
+	CancelledAt           *time.Time `json:"cancelled_at,omitempty"`
+}
+
+type VenueRequest struct {
+	Name            string  `json:"name"`
+	Address         string  `json:"address"`
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+	TimeZone        string  `json:"time_zone"`
+	DefaultCapacity int     `json:"default_capacity"`
+}
+
+type Venue struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Address         string    `json:"address"`
+	Latitude        float64   `json:"latitude"`
+	Longitude       float64   `json:"longitude"`
+	TimeZone        string    `json:"time_zone"`
+	DefaultCapacity int       `json:"default_capacity"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type User struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	TelegramID *int64    `json:"telegram_id,omitempty"`
+	Role       string    `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Booking struct {
+	ID        uuid.UUID     `json:"id"`
+	EventID   uuid.UUID     `json:"event_id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Status    BookingStatus `json:"status"`
+	Deadline  time.Time     `json:"deadline"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type BookEventResponse struct {
+	BookingID uuid.UUID  `json:"booking_id"`
+	Deadline  *time.Time `json:"deadline,omitempty"`
+	Message   string     `json:"message"`
+}
+
+type Job struct {
+	Name    string     `json:"name"`
+	Spec    string     `json:"spec"`
+	Running bool       `json:"running"`
+	NextRun *time.Time `json:"next_run,omitempty"`
+	LastRun *JobRun    `json:"last_run,omitempty"`
+}
+
+type JobRun struct {
+	ID         uuid.UUID `json:"id"`
+	JobName    string    `json:"job_name"`
+	Trigger    string    `json:"trigger"`
+	Status     string    `json:"status"`
+	Error      *string   `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+type ListJobsResponse struct {
+	Jobs []Job    `json:"jobs"`
+	Runs []JobRun `json:"runs"`
+}
+
+type SeatDrift struct {
+	EventID          uuid.UUID `json:"event_id"`
+	EventName        string    `json:"event_name"`
+	TotalSeats       int       `json:"total_seats"`
+	ReservedSeats    int       `json:"reserved_seats"`
+	BookedSeats      int       `json:"booked_seats"`
+	ActualReserved   int       `json:"actual_reserved"`
+	ActualBooked     int       `json:"actual_booked"`
+	StaleSeats       int       `json:"stale_seats"`
+	UnseatedBookings int       `json:"unseated_bookings"`
+	Repaired         bool      `json:"repaired"`
+	Error            *string   `json:"error,omitempty"`
+}
+
+type SeatReconciliation struct {
+	CheckedAt time.Time   `json:"checked_at"`
+	Repair    bool        `json:"repair"`
+	Drifts    []SeatDrift `json:"drifts"`
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type Health struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func newAdminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Administrative operations performed directly through the service layer",
+	}
+
+	event := &cobra.Command{
+		Use:   "event",
+		Short: "Manage events",
+	}
+	event.AddCommand(&cobra.Command{
+		Use:   "cancel <event-id>",
+		Short: "Cancel an event and all of its active bookings",
+		Args:  cobra.ExactArgs(1),
+		RunE: withApp(func(ctx context.Context, a *app, args []string) error {
+			eventID, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid event id: %w", err)
+			}
+
+			cancelled, err := a.svc.CancelEvent(ctx, eventID)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("event %s cancelled, %d bookings cancelled\n", eventID, cancelled)
+			return nil
+		}),
+	})
+
+	booking := &cobra.Command{
+		Use:   "booking",
+		Short: "Manage bookings",
+	}
+	booking.AddCommand(&cobra.Command{
+		Use:   "expire-now <booking-id>",
+		Short: "Cancel a reserved booking immediately and release its seat",
+		Args:  cobra.ExactArgs(1),
+		RunE: withApp(func(ctx context.Context, a *app, args []string) error {
+			bookingID, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid booking id: %w", err)
+			}
+
+			if err := a.svc.ExpireBooking(ctx, bookingID); err != nil {
+				return err
+			}
+
+			fmt.Printf("booking %s expired\n", bookingID)
+			return nil
+		}),
+	})
+
+	user := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+	user.AddCommand(&cobra.Command{
+		Use:   "promote <email>",
+		Short: "Grant the admin role to a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: withApp(func(ctx context.Context, a *app, args []string) error {
+			promoted, err := a.svc.PromoteUser(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("user %s (%s) is now %s\n", promoted.ID, promoted.Email, promoted.Role)
+			return nil
+		}),
+	})
+
+	cmd.AddCommand(event, booking, user)
+
+	return cmd
+}
+
+func withApp(run func(ctx context.Context, a *app, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(nil)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		a := newApp(ctx, cfg, true)
+		defer a.Close()
+
+		return run(ctx, a, args)
+	}
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"github.com/gookit/slog"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kstsm/wb-event-booker/database"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/notifier"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"github.com/kstsm/wb-event-booker/internal/scheduler"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"github.com/kstsm/wb-event-booker/internal/worker"
+	"time"
+)
+
+type app struct {
+	cfg      config.Config
+	conn     *pgxpool.Pool
+	migrator *database.Migrator
+	repo     repository.RepositoryI
+	svc      service.ServiceI
+	notifier notifier.NotifierI
+
+	shutdownTracing func(context.Context) error
+}
+
+// newApp wires the dependencies shared by every subcommand. When checkSchema
+// is set, the process refuses to continue against an outdated database.
+func newApp(ctx context.Context, cfg config.Config, checkSchema bool) *app {
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing)
+	if err != nil {
+		slog.Fatal("Error initializing tracing", "error", err)
+	}
+
+	conn := database.InitPostgres(ctx, cfg.Postgres)
+
+	migrator, err := database.NewMigrator(conn)
+	if err != nil {
+		slog.Fatal("Error creating migrator", "error", err)
+	}
+
+	if checkSchema {
+		if cfg.Postgres.AutoMigrate {
+			slog.Info("Applying database migrations...")
+			if _, err := migrator.Up(ctx); err != nil {
+				slog.Fatal("Error applying migrations", "error", err)
+			}
+		}
+
+		if err := migrator.Check(ctx); err != nil {
+			slog.Fatal("Database schema is not up to date, run `migrate up` or set POSTGRES_AUTO_MIGRATE=true", "error", err)
+		}
+	}
+
+	repo := availability.WithPublishing(
+		repository.WithTracing(repository.NewRepository(conn, cfg.Postgres)),
+		availability.NewPostgresPublisher(conn),
+	)
+
+	var notifierInstance notifier.NotifierI
+	if cfg.Telegram.BotToken != "" {
+		notifierInstance = notifier.NewTelegramNotifier(cfg.Telegram)
+	}
+
+	return &app{
+		cfg:             cfg,
+		conn:            conn,
+		migrator:        migrator,
+		repo:            repo,
+		svc:             service.WithTracing(service.NewService(repo, cfg.Booking)),
+		notifier:        notifierInstance,
+		shutdownTracing: shutdownTracing,
+	}
+}
+
+func (a *app) Close() {
+	if err := a.migrator.Close(); err != nil {
+		slog.Error("Error while closing migrator", "error", err)
+	}
+
+	a.conn.Close()
+
+	if err := a.shutdownTracing(context.Background()); err != nil {
+		slog.Error("Error while shutting down tracing", "error", err)
+	}
+}
+
+func (a *app) newScheduler() scheduler.SchedulerI {
+	bookingWorker := worker.NewWorker(a.repo, a.notifier)
+	bookingScheduler := scheduler.NewScheduler(a.repo)
+
+	if err := registerJobs(bookingScheduler, bookingWorker, a.svc, a.cfg.Scheduler); err != nil {
+		slog.Fatal("Error registering scheduler jobs", "error", err)
+	}
+
+	return bookingScheduler
+}
+
+func registerJobs(s scheduler.SchedulerI, w *worker.Worker, svc service.ServiceI, cfg config.SchedulerConfig) error {
+	jitter := time.Duration(cfg.Jitter) * time.Second
+	timeout := time.Duration(cfg.JobTimeout) * time.Second
+
+	jobs := []scheduler.Job{
+		{
+			Name:       "expiry",
+			Spec:       cfg.ExpirySpec,
+			RunOnStart: true,
+			Task:       w.ProcessExpiredBookings,
+		},
+		{
+			Name: "reminders",
+			Spec: cfg.RemindersSpec,
+			Task: func(ctx context.Context) error {
+				return w.SendBookingReminders(ctx, time.Duration(cfg.ReminderBeforeMinutes)*time.Minute)
+			},
+		},
+		{
+			Name: "sales-open",
+			Spec: cfg.SalesOpenSpec,
+			Task: w.NotifySalesOpened,
+		},
+		{
+			Name: "cleanup",
+			Spec: cfg.CleanupSpec,
+			Task: func(ctx context.Context) error {
+				return errors.Join(
+					w.CleanupJobRuns(ctx, time.Duration(cfg.HistoryRetentionDays)*24*time.Hour),
+					w.CleanupIdempotencyKeys(ctx),
+				)
+			},
+		},
+		{
+			Name: "reports",
+			Spec: cfg.ReportsSpec,
+			Task: w.ReportStats,
+		},
+		{
+			Name: "reconcile",
+			Spec: cfg.ReconcileSpec,
+			Task: func(ctx context.Context) error {
+				_, err := svc.ReconcileSeatCounters(ctx, cfg.ReconcileRepair)
+				return err
+			},
+		},
+	}
+
+	for _, job := range jobs {
+		job.Jitter = jitter
+		job.Timeout = timeout
+		if err := s.Register(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
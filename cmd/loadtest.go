@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gookit/slog"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/handler"
+	"github.com/kstsm/wb-event-booker/internal/health"
+	"github.com/kstsm/wb-event-booker/internal/loadtest"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/scheduler"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"github.com/spf13/cobra"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func newLoadtestCmd() *cobra.Command {
+	var (
+		opts     loadtest.Options
+		inMemory bool
+		strict   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "loadtest",
+		Short: "Flood one event with concurrent bookings and report the outcome",
+		Long: "Create an event, register synthetic users and let all of them book the event at once " +
+			"through the HTTP API. Reports throughput, latency percentiles, conflicts, oversold seats " +
+			"and whether the event counters match its bookings.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Users < 1 || opts.Seats < 1 || opts.Concurrency < 1 {
+				return errors.New("--users, --seats and --concurrency must be positive")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if inMemory {
+				server := newInMemoryServer()
+				defer server.Close()
+				opts.BaseURL = server.URL
+			}
+			opts.Progress = cmd.ErrOrStderr()
+
+			report, err := loadtest.NewRunner(opts).Run(ctx)
+			if err != nil {
+				return err
+			}
+			report.Print(cmd.OutOrStdout())
+
+			if strict && (report.Oversold() > 0 || !report.Consistent()) {
+				return errors.New("booking invariants violated")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.BaseURL, "url", "http://localhost:8080", "base URL of a running API server")
+	cmd.Flags().IntVar(&opts.Users, "users", 2000, "number of synthetic users, each books once")
+	cmd.Flags().IntVar(&opts.Seats, "seats", 100, "seats of the event under test")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", 100, "number of parallel clients")
+	cmd.Flags().BoolVar(&opts.PaymentReq, "paid", true, "create an event that requires payment confirmation")
+	cmd.Flags().StringVar(&opts.Inventory, "inventory", "counter", "seat inventory of the event: counter or seats")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "timeout of a single request")
+	cmd.Flags().BoolVar(&inMemory, "in-memory", false, "run against an in-process server backed by the in-memory repository")
+	cmd.Flags().BoolVar(&strict, "strict", false, "exit with an error if seats were oversold or counters drifted")
+
+	return cmd
+}
+
+// newInMemoryServer serves the API without Postgres, which gives a baseline
+// for the HTTP and service layers without any database locking.
+func newInMemoryServer() *httptest.Server {
+	// Per-request logging would dominate the measurement.
+	slog.SetLogLevel(slog.WarnLevel)
+
+	// Rate limiting stays off: every synthetic user books from the same
+	// address, and contention is what is being measured.
+	hub := availability.NewHub()
+	repo := availability.WithPublishing(memory.NewRepository(), hub)
+	svc := service.NewService(repo, config.BookingConfig{})
+	router := handler.NewHandler(svc, scheduler.NewScheduler(repo), health.NewChecker(), availability.NewFeed(hub, svc.GetEventByID),
+		config.Server{IdempotencyTTLHours: 24}, config.RateLimitConfig{}).NewRouter()
+
+	server := httptest.NewServer(router)
+	fmt.Fprintf(os.Stderr, "in-memory server listening on %s\n", server.URL)
+
+	return server
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kstsm/wb-event-booker/database"
+	"github.com/pressly/goose/v3"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the database schema using the embedded migrations",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(ctx context.Context, m *database.Migrator) error {
+				results, err := m.Up(ctx)
+				printResults(results...)
+				if err == nil && len(results) == 0 {
+					fmt.Println("no migrations to apply")
+				}
+				return err
+			}),
+		},
+		&cobra.Command{
+			Use:   "down",
+			Short: "Roll back the most recent migration",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(ctx context.Context, m *database.Migrator) error {
+				result, err := m.Down(ctx)
+				printResults(result)
+				return err
+			}),
+		},
+		&cobra.Command{
+			Use:   "redo",
+			Short: "Roll back and re-apply the most recent migration",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(ctx context.Context, m *database.Migrator) error {
+				results, err := m.Redo(ctx)
+				printResults(results...)
+				return err
+			}),
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show the state of every migration",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(ctx context.Context, m *database.Migrator) error {
+				statuses, err := m.Status(ctx)
+				if err != nil {
+					return err
+				}
+				printStatus(statuses)
+				return nil
+			}),
+		},
+	)
+
+	return cmd
+}
+
+func withMigrator(run func(ctx context.Context, m *database.Migrator) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(nil)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		a := newApp(ctx, cfg, false)
+		defer a.Close()
+
+		return run(ctx, a.migrator)
+	}
+}
+
+func printResults(results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil || result.Source == nil {
+			continue
+		}
+		fmt.Printf("%-4s %s (%s)\n", result.Direction, result.Source.Path, result.Duration.Round(time.Millisecond))
+	}
+}
+
+func printStatus(statuses []*goose.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tMIGRATION\tSTATE\tAPPLIED AT")
+
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Source.Version, status.Source.Path, status.State, appliedAt)
+	}
+
+	w.Flush()
+}
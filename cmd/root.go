@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var (
+	configFile      string
+	configOverrides map[string]string
+)
+
+func Execute() {
+	root := &cobra.Command{
+		Use:          "event-booker",
+		Short:        "Event booking service",
+		SilenceUsage: true,
+	}
+
+	root.PersistentFlags().StringVar(&configFile, "config", "", "path to a .env or YAML config file (default: .env if present)")
+	root.PersistentFlags().StringToStringVar(&configOverrides, "set", nil, "override a setting, e.g. --set SRV_PORT=9090 (highest precedence)")
+
+	root.AddCommand(
+		newServeCmd(),
+		newWorkerCmd(),
+		newMigrateCmd(),
+		newSeedCmd(),
+		newAdminCmd(),
+		newConfigCmd(),
+		newLoadtestCmd(),
+	)
+
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// loadConfig resolves the configuration from every source. extra holds
+// command-specific flags and wins over --set.
+func loadConfig(extra map[string]string) (config.Config, error) {
+	overrides := make(map[string]string, len(configOverrides)+len(extra))
+	for key, value := range configOverrides {
+		overrides[key] = value
+	}
+	for key, value := range extra {
+		overrides[key] = value
+	}
+
+	return config.Load(config.LoadOptions{
+		File:      configFile,
+		Overrides: overrides,
+	})
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/spf13/cobra"
+	"strings"
+	"time"
+)
+
+var (
+	seedFirstNames = []string{"Иван", "Мария", "Алексей", "Анна", "Дмитрий", "Елена", "Сергей", "Ольга"}
+	seedLastNames  = []string{"Иванов", "Смирнова", "Кузнецов", "Попова", "Соколов", "Лебедева", "Козлов", "Новикова"}
+	seedTopics     = []struct {
+		name     string
+		category models.EventCategory
+		tags     []string
+	}{
+		{"Golang Meetup", models.EventCategoryMeetup, []string{"go", "backend"}},
+		{"Backend Conf", models.EventCategoryConference, []string{"backend", "architecture"}},
+		{"Highload Night", models.EventCategoryConference, []string{"highload", "databases"}},
+		{"DevOps Day", models.EventCategoryWorkshop, []string{"devops", "kubernetes"}},
+		{"Frontend Talks", models.EventCategoryMeetup, []string{"frontend", "javascript"}},
+	}
+)
+
+func newSeedCmd() *cobra.Command {
+	var (
+		events int
+		users  int
+		seats  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Generate demo events and users",
+		Args:  cobra.NoArgs,
+		RunE: withApp(func(ctx context.Context, a *app, args []string) error {
+			return seed(ctx, a, events, users, seats)
+		}),
+	}
+
+	cmd.Flags().IntVar(&events, "events", 10, "number of events to create")
+	cmd.Flags().IntVar(&users, "users", 20, "number of users to create")
+	cmd.Flags().IntVar(&seats, "seats", 50, "total seats of every event")
+
+	return cmd
+}
+
+func seed(ctx context.Context, a *app, events, users, seats int) error {
+	for i := range events {
+		topic := seedTopics[i%len(seedTopics)]
+		req := &dto.CreateEventRequest{
+			Name:                 fmt.Sprintf("%s #%d", topic.name, i+1),
+			Date:                 time.Now().UTC().AddDate(0, 0, i+1).Truncate(time.Hour).Format(time.RFC3339),
+			TotalSeats:           seats,
+			BookingLifetimeHours: 1,
+			PaymentReq:           i%2 == 0,
+			Description:          fmt.Sprintf("Демо-мероприятие **%s**.", topic.name),
+			Category:             string(topic.category),
+			Tags:                 topic.tags,
+			OrganizerContact:     "events@example.com",
+		}
+		if err := req.ValidateEvent(); err != nil {
+			return fmt.Errorf("invalid demo event: %w", err)
+		}
+
+		event, err := a.svc.CreateEvent(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+		fmt.Printf("event %s %q\n", event.ID, event.Name)
+	}
+
+	for i := range users {
+		req := &dto.CreateUserRequest{
+			Name:  seedFirstNames[i%len(seedFirstNames)] + " " + seedLastNames[(i/len(seedFirstNames))%len(seedLastNames)],
+			Email: "demo" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8] + "@example.com",
+		}
+		if err := req.ValidateUser(); err != nil {
+			return fmt.Errorf("invalid demo user: %w", err)
+		}
+
+		user, err := a.svc.CreateUser(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		fmt.Printf("user  %s %s\n", user.ID, user.Email)
+	}
+
+	return nil
+}
@@ -5,14 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gookit/slog"
-	"github.com/kstsm/wb-event-booker/database"
+	"github.com/kstsm/wb-event-booker/internal/availability"
 	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/grpcapi"
 	"github.com/kstsm/wb-event-booker/internal/handler"
-	"github.com/kstsm/wb-event-booker/internal/notifier"
-	"github.com/kstsm/wb-event-booker/internal/repository"
+	"github.com/kstsm/wb-event-booker/internal/health"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
 	"github.com/kstsm/wb-event-booker/internal/scheduler"
-	"github.com/kstsm/wb-event-booker/internal/service"
-	"github.com/kstsm/wb-event-booker/internal/worker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -20,43 +23,143 @@ import (
 	"time"
 )
 
-func Run() {
-	cfg := config.GetConfig()
+func newServeCmd() *cobra.Command {
+	var (
+		withWorker bool
+		host       string
+		port       string
+	)
 
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the HTTP and gRPC API servers",
+		Long: "Start the HTTP API server and, unless GRPC_ENABLED=false, the gRPC server next to it. Scheduled jobs are not executed unless --with-worker is set; " +
+			"they can still be triggered manually through the admin API.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			extra := map[string]string{}
+			if cmd.Flags().Changed("host") {
+				extra["SRV_HOST"] = host
+			}
+			if cmd.Flags().Changed("port") {
+				extra["SRV_PORT"] = port
+			}
+
+			cfg, err := loadConfig(extra)
+			if err != nil {
+				return err
+			}
+
+			return runServer(cfg, withWorker)
+		},
+	}
+
+	cmd.Flags().BoolVar(&withWorker, "with-worker", false, "also run the background scheduler in this process")
+	cmd.Flags().StringVar(&host, "host", "", "listen address, overrides SRV_HOST")
+	cmd.Flags().StringVar(&port, "port", "", "listen port, overrides SRV_PORT")
+
+	return cmd
+}
+
+func runServer(cfg config.Config, withWorker bool) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	conn := database.InitPostgres(ctx)
-	defer conn.Close()
+	a := newApp(ctx, cfg, true)
+	defer a.Close()
+
+	prometheus.MustRegister(metrics.NewPoolCollector(a.conn))
 
-	repo := repository.NewRepository(conn)
-	svc := service.NewService(repo)
-	router := handler.NewHandler(svc)
+	bookingScheduler := a.newScheduler()
+	checker := newChecker(a)
 
-	var notifierInstance notifier.NotifierI
-	if cfg.Telegram.BotToken != "" {
-		notifierInstance = notifier.NewTelegramNotifier(cfg.Telegram)
+	if withWorker {
+		bookingScheduler.Start(ctx)
+		defer bookingScheduler.Stop()
+		registerSchedulerCheck(checker, bookingScheduler)
 	}
 
-	bookingWorker := worker.NewWorker(repo, notifierInstance)
-	bookingScheduler := scheduler.NewScheduler()
+	// Changes made by any replica or by the worker reach the streams of this
+	// process through Postgres notifications. The streams keep being served
+	// while traffic drains, so the hub is closed only once the drain is over:
+	// closing it earlier sends EventSource clients into a reconnection loop.
+	hub := availability.NewHub()
+	listenCtx, stopListening := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopListening()
+	go availability.Listen(listenCtx, a.conn, hub)
+	closeStreams := func() {
+		stopListening()
+		hub.Close()
+	}
 
-	go func() {
-		slog.Infof("Starting handler scheduler with interval %d second:", cfg.Scheduler.CheckInterval)
-		bookingScheduler.Start(ctx, cfg, func() error {
-			return bookingWorker.ProcessExpiredBookings(ctx)
-		})
-	}()
+	feed := availability.NewFeed(hub, a.svc.GetEventByID)
+
+	router := handler.NewHandler(a.svc, bookingScheduler, checker, feed, a.cfg.Server, a.cfg.RateLimit)
+
+	var rpc *grpcEndpoint
+	if a.cfg.GRPC.Enabled {
+		rpc = &grpcEndpoint{
+			srv:  grpcapi.NewServer(a.svc, feed, a.cfg.GRPC, a.cfg.Server.AdminToken, a.cfg.RateLimit),
+			addr: fmt.Sprintf("%s:%d", a.cfg.GRPC.Host, a.cfg.GRPC.Port),
+		}
+	}
 
+	return listenAndServe(ctx, a.cfg.Server, router.NewRouter(), checker, rpc, closeStreams)
+}
+
+// grpcEndpoint is a gRPC server that listenAndServe runs next to the HTTP one
+// and shuts down together with it.
+type grpcEndpoint struct {
+	srv  *grpc.Server
+	addr string
+}
+
+func newChecker(a *app) health.CheckerI {
+	checker := health.NewChecker()
+	checker.Register("database", a.conn.Ping)
+	checker.Register("migrations", a.migrator.Check)
+	// Notifications are best effort, so an outage or rate limit of the
+	// Telegram API is reported without taking the API out of rotation.
+	if a.notifier != nil {
+		checker.RegisterOptional("notifier", health.Cached(a.notifier.Ping, time.Minute))
+	}
+
+	return checker
+}
+
+func registerSchedulerCheck(checker health.CheckerI, s scheduler.SchedulerI) {
+	checker.Register("scheduler", s.Check)
+}
+
+func listenAndServe(ctx context.Context,
+	cfg config.Server,
+	h http.Handler,
+	checker health.CheckerI,
+	rpc *grpcEndpoint,
+	closeStreams func(),
+) error {
 	srv := &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler: router.NewRouter(),
+		Addr:    fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Handler: h,
 	}
 
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 2)
+
+	if rpc != nil {
+		lis, err := net.Listen("tcp", rpc.addr)
+		if err != nil {
+			slog.Error("Error starting gRPC server", "error", err)
+			return err
+		}
+
+		go func() {
+			slog.Infof("Starting gRPC server on %s", rpc.addr)
+			errChan <- rpc.srv.Serve(lis)
+		}()
+	}
 
 	go func() {
-		slog.Infof("Starting server on %s:%d", cfg.Server.Host, cfg.Server.Port)
+		slog.Infof("Starting server on %s", srv.Addr)
 		errChan <- srv.ListenAndServe()
 	}()
 
@@ -65,13 +168,50 @@ func Run() {
 		slog.Info("Finishing the server...")
 	case err := <-errChan:
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			slog.Fatal("Error starting server", "error", err)
+			slog.Error("Error starting server", "error", err)
+			srv.Close()
+			if rpc != nil {
+				rpc.srv.Stop()
+			}
+			return err
 		}
 	}
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	checker.SetShuttingDown()
+	slog.Infof("Draining traffic for %d seconds...", cfg.ShutdownDrain)
+	time.Sleep(time.Duration(cfg.ShutdownDrain) * time.Second)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout)*time.Second)
 	defer cancel()
+
+	// Shutdown waits for active connections, which a stream never leaves on
+	// its own, so open streams are ended here, after the drain.
+	if closeStreams != nil {
+		closeStreams()
+	}
+
+	// GracefulStop waits for open streams such as WatchEvent, so whatever is
+	// still running when the shutdown timeout expires is cut off.
+	grpcStopped := make(chan struct{})
+	if rpc != nil {
+		go func() {
+			rpc.srv.GracefulStop()
+			close(grpcStopped)
+		}()
+	} else {
+		close(grpcStopped)
+	}
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		slog.Error("Error while shutting down the server", "error", err)
 	}
+
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		slog.Warn("gRPC server did not stop in time, closing remaining streams")
+		rpc.srv.Stop()
+	}
+
+	return nil
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/handler"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func newWorkerCmd() *cobra.Command {
+	var (
+		host string
+		port int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "worker",
+		Short: "Run scheduled jobs and notifications without the public API",
+		Long: "Run the background scheduler (booking expiry, reminders, sales notifications, cleanup, reports, reconciliation) " +
+			"and Telegram notifier. Only /healthz, /readyz and /metrics are served.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(nil)
+			if err != nil {
+				return err
+			}
+
+			return runWorker(cfg, host, port)
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "0.0.0.0", "address for health and metrics endpoints")
+	cmd.Flags().IntVar(&port, "port", 8081, "port for health and metrics endpoints")
+
+	return cmd
+}
+
+func runWorker(cfg config.Config, host string, port int) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	a := newApp(ctx, cfg, true)
+	defer a.Close()
+
+	prometheus.MustRegister(metrics.NewPoolCollector(a.conn))
+
+	bookingScheduler := a.newScheduler()
+	bookingScheduler.Start(ctx)
+	defer bookingScheduler.Stop()
+
+	checker := newChecker(a)
+	registerSchedulerCheck(checker, bookingScheduler)
+
+	router := handler.NewHandler(a.svc, bookingScheduler, checker, nil, a.cfg.Server, a.cfg.RateLimit)
+
+	srvCfg := a.cfg.Server
+	srvCfg.Host = host
+	srvCfg.Port = port
+
+	return listenAndServe(ctx, srvCfg, router.NewProbeRouter(), checker, nil, nil)
+}
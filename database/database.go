@@ -2,33 +2,48 @@ package database
 
 import (
 	"context"
-	"fmt"
 	"github.com/gookit/slog"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 )
 
-func InitPostgres(ctx context.Context) *pgxpool.Pool {
-	cfg := config.GetConfig()
-
-	dsn := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.Postgres.Username,
-		cfg.Postgres.Password,
-		cfg.Postgres.Host,
-		cfg.Postgres.Port,
-		cfg.Postgres.DBName,
-		cfg.Postgres.SslMode,
-	)
+func InitPostgres(ctx context.Context, cfg config.Postgres) *pgxpool.Pool {
+	dsn := (&url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:     cfg.DBName,
+		RawQuery: url.Values{"sslmode": {cfg.SslMode}}.Encode(),
+	}).String()
 
 	slog.Info(
 		"Connecting to the database... host=%s port=%s db=%s",
-		cfg.Postgres.Host,
-		cfg.Postgres.Port,
-		cfg.Postgres.DBName,
+		cfg.Host,
+		cfg.Port,
+		cfg.DBName,
 	)
 
-	pool, err := pgxpool.New(ctx, dsn)
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		slog.Error("Failed to parse database config", "error", err)
+		panic(err)
+	}
+	poolCfg.MaxConns = int32(cfg.MaxConns)
+	poolCfg.MinConns = int32(cfg.MinConns)
+	poolCfg.MaxConnLifetime = time.Duration(cfg.MaxConnLifetime) * time.Second
+	poolCfg.MaxConnIdleTime = time.Duration(cfg.MaxConnIdleTime) * time.Second
+	poolCfg.HealthCheckPeriod = time.Duration(cfg.HealthCheckPeriod) * time.Second
+	if cfg.StatementTimeoutMs > 0 {
+		poolCfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.Itoa(cfg.StatementTimeoutMs)
+	}
+	poolCfg.ConnConfig.Tracer = tracing.NewPgxTracer()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		slog.Error("Failed to connect to the database", "error", err)
 		panic(err)
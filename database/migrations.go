@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/kstsm/wb-event-booker/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+type Migrator struct {
+	db       *sql.DB
+	provider *goose.Provider
+}
+
+// NewMigrator builds a goose provider over the embedded migrations. Every
+// command that changes the schema holds a Postgres advisory lock, so several
+// replicas can run migrations on startup without racing each other.
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("NewPostgresSessionLocker-NewMigrator: %w", err)
+	}
+
+	db := stdlib.OpenDBFromPool(pool)
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS,
+		goose.WithSessionLocker(locker),
+		goose.WithDisableGlobalRegistry(true),
+	)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("NewProvider-NewMigrator: %w", err)
+	}
+
+	return &Migrator{
+		db:       db,
+		provider: provider,
+	}, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("Up-Migrator: %w", err)
+	}
+
+	return results, nil
+}
+
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return result, fmt.Errorf("Down-Migrator: %w", err)
+	}
+
+	return result, nil
+}
+
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := m.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := m.provider.UpByOne(ctx)
+	if err != nil {
+		return []*goose.MigrationResult{down, up}, fmt.Errorf("UpByOne-Migrator: %w", err)
+	}
+
+	return []*goose.MigrationResult{down, up}, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Status-Migrator: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// Check returns an error when the database schema is behind the embedded
+// migrations.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, target, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("GetVersions-Migrator: %w", err)
+	}
+
+	if current < target {
+		return fmt.Errorf("database schema version %d is behind %d", current, target)
+	}
+
+	return nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
@@ -7,11 +7,33 @@ var (
 	UserNotFound               = errors.New("user not found")
 	NoAvailableSeats           = errors.New("no available seats")
 	BookingNotFound            = errors.New("booking not found")
+	VenueNotFound              = errors.New("venue not found")
+	VenueUnavailable           = errors.New("venue is already taken by another event at that time")
+	VenueInUse                 = errors.New("venue is used by events")
 	BookingNotReserved         = errors.New("booking is not in reserved status")
 	BookingDeadlinePassed      = errors.New("booking deadline has passed")
+	BookingAlreadyCancelled    = errors.New("booking is already cancelled")
 	UserAlreadyBookedThisEvent = errors.New("user already has a booking for this event")
+	TooManyActiveReservations  = errors.New("user has too many unpaid reservations")
 	EventDoesNotRequirePayment = errors.New("event does not require payment confirmation")
 	EventExpired               = errors.New("event has expired")
+	EventCancelled             = errors.New("event has been cancelled")
+	SalesNotOpen               = errors.New("ticket sales have not opened yet")
+	SalesClosed                = errors.New("ticket sales are closed")
 	EmailAlreadyExists         = errors.New("email already exists")
 	TelegramIDAlreadyExists    = errors.New("telegram id already exists")
+	JobNotFound                = errors.New("job not found")
+	JobAlreadyRunning          = errors.New("job is already running")
+	ResourceBusy               = errors.New("resource is busy, try again later")
+	IdempotencyKeyMismatch     = errors.New("idempotency key was already used for a different request")
+	IdempotencyKeyInProgress   = errors.New("a request with this idempotency key is still being processed")
+	ValidationFailed           = errors.New("request validation failed")
+	InvalidRequestBody         = errors.New("invalid request body")
+	RequestBodyTooLarge        = errors.New("request body too large")
+	RateLimited                = errors.New("too many requests, try again later")
+	Unauthorized               = errors.New("a valid admin token is required")
+	AdminDisabled              = errors.New("admin API is disabled")
+	RouteNotFound              = errors.New("route not found")
+	MethodNotAllowed           = errors.New("method not allowed")
+	Internal                   = errors.New("internal server error")
 )
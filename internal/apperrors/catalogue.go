@@ -0,0 +1,82 @@
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// TypePrefix is prepended to a code to form the problem type URI of RFC 7807.
+const TypePrefix = "urn:event-booker:problem:"
+
+// Entry describes how an error is presented to API clients. Codes are part of
+// the API contract: they never change once published, while messages may.
+type Entry struct {
+	Err    error
+	Code   string
+	Status int
+	Title  string
+	// RetryAfter is the number of seconds a client should wait before
+	// retrying, zero when retrying does not help.
+	RetryAfter int
+}
+
+func (e Entry) Type() string {
+	return TypePrefix + e.Code
+}
+
+var catalogue = []Entry{
+	{ValidationFailed, "validation_failed", http.StatusBadRequest, "Request validation failed", 0},
+	{InvalidRequestBody, "invalid_request_body", http.StatusBadRequest, "Invalid request body", 0},
+	{RequestBodyTooLarge, "request_body_too_large", http.StatusRequestEntityTooLarge, "Request body too large", 0},
+	{RouteNotFound, "route_not_found", http.StatusNotFound, "Route not found", 0},
+	{MethodNotAllowed, "method_not_allowed", http.StatusMethodNotAllowed, "Method not allowed", 0},
+	{RateLimited, "rate_limited", http.StatusTooManyRequests, "Too many requests", 0},
+	{Unauthorized, "unauthorized", http.StatusUnauthorized, "Unauthorized", 0},
+	{AdminDisabled, "admin_disabled", http.StatusForbidden, "Admin API disabled", 0},
+
+	{EventNotFound, "event_not_found", http.StatusNotFound, "Event not found", 0},
+	{UserNotFound, "user_not_found", http.StatusNotFound, "User not found", 0},
+	{BookingNotFound, "booking_not_found", http.StatusNotFound, "Booking not found", 0},
+	{VenueNotFound, "venue_not_found", http.StatusNotFound, "Venue not found", 0},
+	{JobNotFound, "job_not_found", http.StatusNotFound, "Job not found", 0},
+
+	{NoAvailableSeats, "no_available_seats", http.StatusConflict, "No available seats", 0},
+	{UserAlreadyBookedThisEvent, "already_booked", http.StatusConflict, "Event already booked by the user", 0},
+	{BookingAlreadyCancelled, "booking_already_cancelled", http.StatusConflict, "Booking already cancelled", 0},
+	{TooManyActiveReservations, "too_many_reservations", http.StatusConflict, "Too many unpaid reservations", 0},
+	{EmailAlreadyExists, "email_already_exists", http.StatusConflict, "Email already registered", 0},
+	{TelegramIDAlreadyExists, "telegram_id_already_exists", http.StatusConflict, "Telegram ID already registered", 0},
+	{VenueUnavailable, "venue_unavailable", http.StatusConflict, "Venue unavailable", 0},
+	{VenueInUse, "venue_in_use", http.StatusConflict, "Venue in use", 0},
+	{JobAlreadyRunning, "job_already_running", http.StatusConflict, "Job already running", 0},
+	{IdempotencyKeyInProgress, "idempotency_key_in_progress", http.StatusConflict, "Request still in progress", 1},
+	{IdempotencyKeyMismatch, "idempotency_key_mismatch", http.StatusUnprocessableEntity, "Idempotency key reused", 0},
+
+	{BookingNotReserved, "booking_not_reserved", http.StatusBadRequest, "Booking is not reserved", 0},
+	{BookingDeadlinePassed, "booking_deadline_passed", http.StatusBadRequest, "Booking deadline passed", 0},
+	{EventDoesNotRequirePayment, "payment_not_required", http.StatusBadRequest, "Event does not require payment", 0},
+	{EventExpired, "event_expired", http.StatusBadRequest, "Event has expired", 0},
+	{EventCancelled, "event_cancelled", http.StatusBadRequest, "Event has been cancelled", 0},
+	{SalesNotOpen, "sales_not_open", http.StatusBadRequest, "Ticket sales not open yet", 0},
+	{SalesClosed, "sales_closed", http.StatusBadRequest, "Ticket sales closed", 0},
+
+	{ResourceBusy, "resource_busy", http.StatusServiceUnavailable, "Resource busy", 1},
+	{Internal, "internal_error", http.StatusInternalServerError, "Internal server error", 0},
+}
+
+// Describe returns the catalogue entry err matches. Errors outside the
+// catalogue are internal errors whose details must not reach the client.
+func Describe(err error) Entry {
+	for _, entry := range catalogue {
+		if errors.Is(err, entry.Err) {
+			return entry
+		}
+	}
+
+	return catalogue[len(catalogue)-1]
+}
+
+// Catalogue lists every error the API can return.
+func Catalogue() []Entry {
+	return append([]Entry(nil), catalogue...)
+}
@@ -0,0 +1,61 @@
+package apperrors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes why a single request field was rejected. Field is the
+// name the field has in the JSON request or the URL.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every invalid field of a request so that a client
+// can fix all of them at once. It matches ValidationFailed with errors.Is.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Add(field, format string, args ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Has reports whether field has already been rejected, which lets checks that
+// depend on an earlier one be skipped.
+func (e *ValidationError) Has(field string) bool {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns nil when no field was rejected.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ValidationFailed
+}
+
+// InvalidField is a shorthand for a validation error with a single field.
+func InvalidField(field, format string, args ...any) error {
+	e := &ValidationError{}
+	e.Add(field, format, args...)
+	return e
+}
@@ -0,0 +1,226 @@
+package availability_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"os"
+	"testing"
+	"time"
+)
+
+func received(ch <-chan struct{}) bool {
+	select {
+	case _, ok := <-ch:
+		return ok
+	default:
+		return false
+	}
+}
+
+func TestHub(t *testing.T) {
+	hub := availability.NewHub()
+	ctx := context.Background()
+	eventID, otherID := uuid.New(), uuid.New()
+
+	first, unsubscribeFirst := hub.Subscribe(eventID)
+	second, unsubscribeSecond := hub.Subscribe(eventID)
+	other, unsubscribeOther := hub.Subscribe(otherID)
+	defer unsubscribeOther()
+
+	hub.Publish(ctx, eventID)
+	hub.Publish(ctx, eventID)
+	if !received(first) || !received(second) {
+		t.Fatal("subscribers of the event were not notified")
+	}
+	if received(first) {
+		t.Error("announcements were not coalesced")
+	}
+	if received(other) {
+		t.Error("subscriber of another event was notified")
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, ok := <-first; ok {
+		t.Error("channel is open after unsubscribing")
+	}
+
+	hub.PublishAll()
+	if !received(second) || !received(other) {
+		t.Error("PublishAll did not reach every subscriber")
+	}
+
+	hub.Close()
+	if _, ok := <-second; ok {
+		t.Error("channel is open after Close")
+	}
+	unsubscribeSecond()
+
+	late, _ := hub.Subscribe(eventID)
+	if _, ok := <-late; ok {
+		t.Error("subscription after Close is open")
+	}
+}
+
+func TestWithPublishing(t *testing.T) {
+	hub := availability.NewHub()
+	repo := availability.WithPublishing(memory.NewRepository(), hub)
+	ctx := context.Background()
+
+	event := &models.Event{
+		ID:              uuid.New(),
+		Name:            "Go meetup",
+		Date:            time.Now().Add(24 * time.Hour),
+		SalesCloseAt:    time.Now().Add(24 * time.Hour),
+		TotalSeats:      2,
+		BookingLifetime: 30,
+		PaymentReq:      true,
+		Inventory:       models.InventoryCounter,
+	}
+	if err := repo.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	user := &models.User{ID: uuid.New(), Name: "Anna", Email: "anna@example.com", Role: models.UserRoleUser}
+	if err := repo.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	changes, unsubscribe := hub.Subscribe(event.ID)
+	defer unsubscribe()
+
+	booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	if !received(changes) {
+		t.Error("booking was not announced")
+	}
+
+	if _, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0); err == nil {
+		t.Fatal("second booking of the same user succeeded")
+	}
+	if received(changes) {
+		t.Error("failed booking was announced")
+	}
+
+	if err := repo.CancelExpiredBookingWithTransaction(ctx, booking.ID); err != nil {
+		t.Fatalf("CancelExpiredBookingWithTransaction: %v", err)
+	}
+	if !received(changes) {
+		t.Error("expiry was not announced")
+	}
+
+	if _, err := repo.CancelEventWithTransaction(ctx, event.ID); err != nil {
+		t.Fatalf("CancelEventWithTransaction: %v", err)
+	}
+	if !received(changes) {
+		t.Error("event cancellation was not announced")
+	}
+}
+
+// TestPostgresNotifications runs only when TEST_POSTGRES_DSN is set.
+func TestPostgresNotifications(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	hub := availability.NewHub()
+	eventID := uuid.New()
+	changes, unsubscribe := hub.Subscribe(eventID)
+	defer unsubscribe()
+
+	listenCtx, stopListening := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		availability.Listen(listenCtx, pool, hub)
+		close(done)
+	}()
+	defer func() {
+		stopListening()
+		<-done
+	}()
+
+	// Notifications sent before LISTEN has run are lost, so keep publishing
+	// until one arrives.
+	publisher := availability.NewPostgresPublisher(pool)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		publisher.Publish(ctx, eventID)
+		select {
+		case <-changes:
+			return
+		case <-ctx.Done():
+			t.Fatal("notification did not arrive")
+		case <-ticker.C:
+		}
+	}
+}
+
+func TestFeed(t *testing.T) {
+	hub := availability.NewHub()
+	eventID := uuid.New()
+
+	loads := make(chan struct{}, 10)
+	feed := availability.NewFeed(hub, func(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+		loads <- struct{}{}
+		return &models.Event{ID: id, ReservedSeats: len(loads)}, nil
+	})
+
+	const streams = 5
+	channels := make([]<-chan *models.Event, streams)
+	unsubscribes := make([]func(), streams)
+	for i := range streams {
+		channels[i], unsubscribes[i] = feed.Subscribe(eventID)
+	}
+
+	hub.Publish(context.Background(), eventID)
+	for i, ch := range channels {
+		select {
+		case event := <-ch:
+			if event.ID != eventID {
+				t.Errorf("stream %d received event %s, want %s", i, event.ID, eventID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("stream %d did not receive the event", i)
+		}
+	}
+	if len(loads) != 1 {
+		t.Errorf("event was read %d times for %d streams, want once", len(loads), streams)
+	}
+
+	unsubscribes[0]()
+	unsubscribes[0]()
+	if _, ok := <-channels[0]; ok {
+		t.Error("channel is open after unsubscribing")
+	}
+
+	hub.Close()
+	for i, ch := range channels[1:] {
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Errorf("stream %d received an event after Close", i+1)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("stream %d is open after Close", i+1)
+		}
+	}
+	for _, unsubscribe := range unsubscribes {
+		unsubscribe()
+	}
+}
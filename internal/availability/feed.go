@@ -0,0 +1,140 @@
+package availability
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"sync"
+)
+
+// Loader reads the current state of an event.
+type Loader func(ctx context.Context, eventID uuid.UUID) (*models.Event, error)
+
+// FeedI delivers the state of an event after each announcement of a change.
+// The channel has a buffer of one that always holds the newest state, and it
+// is closed when the underlying subscription ends. Every subscriber receives
+// the same *models.Event, which must not be modified.
+type FeedI interface {
+	Subscribe(eventID uuid.UUID) (<-chan *models.Event, func())
+}
+
+// Feed reads an event once per announcement, however many streams follow
+// it, and fans the result out to them.
+type Feed struct {
+	subscriber SubscriberI
+	load       Loader
+
+	mu     sync.Mutex
+	events map[uuid.UUID]*followed
+}
+
+// followed is an event with at least one subscriber. The feed is subscribed
+// to its announcements while it has any.
+type followed struct {
+	subs        map[chan *models.Event]struct{}
+	unsubscribe func()
+}
+
+func NewFeed(subscriber SubscriberI, load Loader) *Feed {
+	return &Feed{
+		subscriber: subscriber,
+		load:       load,
+		events:     make(map[uuid.UUID]*followed),
+	}
+}
+
+func (f *Feed) Subscribe(eventID uuid.UUID) (<-chan *models.Event, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	e := f.events[eventID]
+	if e == nil {
+		changes, unsubscribe := f.subscriber.Subscribe(eventID)
+		e = &followed{
+			subs:        make(map[chan *models.Event]struct{}),
+			unsubscribe: unsubscribe,
+		}
+		f.events[eventID] = e
+		go f.follow(eventID, e, changes)
+	}
+
+	ch := make(chan *models.Event, 1)
+	e.subs[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { f.unsubscribe(eventID, e, ch) })
+	}
+}
+
+func (f *Feed) unsubscribe(eventID uuid.UUID, e *followed, ch chan *models.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := e.subs[ch]; !ok {
+		return
+	}
+	delete(e.subs, ch)
+	close(ch)
+
+	// Ending the subscription to the announcements stops follow.
+	if len(e.subs) == 0 {
+		delete(f.events, eventID)
+		e.unsubscribe()
+	}
+}
+
+// follow reads the event on every announcement. Only the subscribers that
+// were there before the read started receive its result: those who came
+// later have read the event themselves after subscribing.
+func (f *Feed) follow(eventID uuid.UUID, e *followed, changes <-chan struct{}) {
+	ctx := context.Background()
+
+	for range changes {
+		f.mu.Lock()
+		subs := make([]chan *models.Event, 0, len(e.subs))
+		for ch := range e.subs {
+			subs = append(subs, ch)
+		}
+		f.mu.Unlock()
+
+		event, err := f.load(ctx, eventID)
+		if err != nil {
+			logging.FromContext(ctx).Warnf("Loader-follow: %v", err)
+			continue
+		}
+
+		f.mu.Lock()
+		for _, ch := range subs {
+			if _, ok := e.subs[ch]; ok {
+				offer(ch, event)
+			}
+		}
+		f.mu.Unlock()
+	}
+
+	// The hub has shut down, or the last subscriber has left.
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range e.subs {
+		delete(e.subs, ch)
+		close(ch)
+	}
+	if f.events[eventID] == e {
+		delete(f.events, eventID)
+	}
+}
+
+// offer replaces a state the subscriber has not taken yet with the newer one.
+func offer(ch chan *models.Event, event *models.Event) {
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- event:
+	default:
+	}
+}
@@ -0,0 +1,116 @@
+package availability
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"sync"
+)
+
+// PublisherI announces that the seat counters of an event may have changed.
+// The announcement carries no state: subscribers read the event again.
+type PublisherI interface {
+	Publish(ctx context.Context, eventID uuid.UUID)
+}
+
+// SubscriberI delivers the announcements for a single event. The channel has
+// a buffer of one, so announcements that arrive while the subscriber is busy
+// are coalesced, and it is closed when the hub shuts down.
+type SubscriberI interface {
+	Subscribe(eventID uuid.UUID) (<-chan struct{}, func())
+}
+
+// Hub fans announcements out to the subscribers of this process.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[uuid.UUID]map[chan struct{}]struct{}
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subs: make(map[uuid.UUID]map[chan struct{}]struct{}),
+	}
+}
+
+func (h *Hub) Publish(ctx context.Context, eventID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[eventID] {
+		notify(ch)
+	}
+}
+
+// PublishAll wakes every subscriber, e.g. after announcements may have been
+// lost while the connection to Postgres was down.
+func (h *Hub) PublishAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.subs {
+		for ch := range subs {
+			notify(ch)
+		}
+	}
+}
+
+func (h *Hub) Subscribe(eventID uuid.UUID) (<-chan struct{}, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if h.subs[eventID] == nil {
+		h.subs[eventID] = make(map[chan struct{}]struct{})
+	}
+	h.subs[eventID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { h.unsubscribe(eventID, ch) })
+	}
+}
+
+func (h *Hub) unsubscribe(eventID uuid.UUID, ch chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[eventID][ch]; !ok {
+		return
+	}
+	delete(h.subs[eventID], ch)
+	if len(h.subs[eventID]) == 0 {
+		delete(h.subs, eventID)
+	}
+	close(ch)
+}
+
+// Close ends every subscription so that long-lived streams finish and the
+// servers can shut down. Later subscriptions are closed right away.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+
+	for eventID, subs := range h.subs {
+		for ch := range subs {
+			close(ch)
+		}
+		delete(h.subs, eventID)
+	}
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,92 @@
+package availability
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"time"
+)
+
+// Channel is the Postgres notification channel that carries the ids of
+// events whose seat counters changed.
+const Channel = "event_availability"
+
+const listenRetryDelay = time.Second
+
+type postgresPublisher struct {
+	conn *pgxpool.Pool
+}
+
+// NewPostgresPublisher announces changes with NOTIFY, so that every replica
+// listening with Listen learns about them, including the one making them.
+func NewPostgresPublisher(conn *pgxpool.Pool) PublisherI {
+	return &postgresPublisher{conn: conn}
+}
+
+func (p *postgresPublisher) Publish(ctx context.Context, eventID uuid.UUID) {
+	// The change is already committed, so the announcement is sent even if
+	// the request that made it has been cancelled in the meantime.
+	ctx = context.WithoutCancel(ctx)
+	if _, err := p.conn.Exec(ctx, "SELECT pg_notify($1, $2)", Channel, eventID.String()); err != nil {
+		logging.FromContext(ctx).Warnf("Exec-Publish: %v", err)
+	}
+}
+
+// Listen forwards the notifications of Channel to hub until ctx is done. It
+// holds a connection of its own and reconnects after errors; subscribers are
+// woken up after a reconnection because notifications may have been missed.
+func Listen(ctx context.Context, conn *pgxpool.Pool, hub *Hub) {
+	log := logging.FromContext(ctx)
+	reconnect := false
+
+	for ctx.Err() == nil {
+		err := listen(ctx, conn, hub, func() {
+			if reconnect {
+				hub.PublishAll()
+			}
+			reconnect = true
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Warnf("Listening for availability changes failed: %v, reconnecting in %s", err, listenRetryDelay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, hub *Hub, onListen func()) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("Acquire-listen: %w", err)
+	}
+
+	// The connection stays in LISTEN mode, so it is taken out of the pool
+	// instead of being returned to it.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return fmt.Errorf("Exec-listen: %w", err)
+	}
+	onListen()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("WaitForNotification-listen: %w", err)
+		}
+
+		eventID, err := uuid.Parse(notification.Payload)
+		if err != nil {
+			continue
+		}
+		hub.Publish(ctx, eventID)
+	}
+}
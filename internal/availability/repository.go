@@ -0,0 +1,94 @@
+package availability
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+)
+
+// publishingRepository announces every committed change of the seat counters
+// of an event, whichever process makes it: the API, the worker expiring
+// reservations or an admin command.
+type publishingRepository struct {
+	repository.RepositoryI
+	publisher PublisherI
+}
+
+func WithPublishing(next repository.RepositoryI, publisher PublisherI) repository.RepositoryI {
+	return &publishingRepository{
+		RepositoryI: next,
+		publisher:   publisher,
+	}
+}
+
+func (r *publishingRepository) BookEventWithTransaction(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+	maxReservations int,
+) (*models.Booking, error) {
+	booking, err := r.RepositoryI.BookEventWithTransaction(ctx, eventID, userID, maxReservations)
+	if err == nil {
+		r.publisher.Publish(ctx, eventID)
+	}
+
+	return booking, err
+}
+
+func (r *publishingRepository) ConfirmBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	err := r.RepositoryI.ConfirmBookingWithTransaction(ctx, bookingID)
+	if err == nil {
+		r.publishBooking(ctx, bookingID)
+	}
+
+	return err
+}
+
+func (r *publishingRepository) CancelBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	err := r.RepositoryI.CancelBookingWithTransaction(ctx, bookingID)
+	if err == nil {
+		r.publishBooking(ctx, bookingID)
+	}
+
+	return err
+}
+
+func (r *publishingRepository) CancelExpiredBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	err := r.RepositoryI.CancelExpiredBookingWithTransaction(ctx, bookingID)
+	if err == nil {
+		r.publishBooking(ctx, bookingID)
+	}
+
+	return err
+}
+
+func (r *publishingRepository) CancelEventWithTransaction(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	cancelled, err := r.RepositoryI.CancelEventWithTransaction(ctx, eventID)
+	if err == nil {
+		r.publisher.Publish(ctx, eventID)
+	}
+
+	return cancelled, err
+}
+
+func (r *publishingRepository) RepairSeatCountersWithTransaction(ctx context.Context, eventID uuid.UUID) (*models.SeatDrift, error) {
+	drift, err := r.RepositoryI.RepairSeatCountersWithTransaction(ctx, eventID)
+	if err == nil && drift != nil {
+		r.publisher.Publish(ctx, eventID)
+	}
+
+	return drift, err
+}
+
+// publishBooking announces the event of a booking whose transaction only
+// knows the booking id.
+func (r *publishingRepository) publishBooking(ctx context.Context, bookingID uuid.UUID) {
+	booking, err := r.RepositoryI.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("GetBookingByID-publishBooking: %v", err)
+		return
+	}
+
+	r.publisher.Publish(ctx, booking.EventID)
+}
@@ -1,64 +1,400 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
+	"os"
+	"strings"
 )
 
+const defaultFile = ".env"
+
 type Config struct {
 	Server    Server
 	Postgres  Postgres
 	Scheduler SchedulerConfig
 	Telegram  TelegramConfig
+	Tracing   TracingConfig
+	Booking   BookingConfig
+	RateLimit RateLimitConfig
+	GRPC      GRPCConfig
 }
 
 type Server struct {
-	Host string
-	Port int
+	Host            string
+	Port            int
+	ShutdownDrain   int
+	ShutdownTimeout int
+	// IdempotencyTTLHours is how long responses to requests with an
+	// Idempotency-Key header are kept for replay.
+	IdempotencyTTLHours int
+	// LegacyAPIDeprecatedAt and LegacyAPISunsetAt are dates (YYYY-MM-DD)
+	// announced to clients of the unversioned /api alias.
+	LegacyAPIDeprecatedAt string
+	LegacyAPISunsetAt     string
+	// SSEHeartbeatSeconds is how often availability streams send a comment
+	// to keep idle connections open through proxies.
+	SSEHeartbeatSeconds int
+	// AdminToken is the bearer token required by the /admin endpoints. They
+	// are disabled while it is empty.
+	AdminToken string
 }
 
 type Postgres struct {
-	Username string
-	Password string
-	Host     string
-	Port     string
-	DBName   string
-	SslMode  string
+	Username    string
+	Password    string
+	Host        string
+	Port        string
+	DBName      string
+	SslMode     string
+	AutoMigrate bool
+
+	MaxConns           int
+	MinConns           int
+	MaxConnLifetime    int
+	MaxConnIdleTime    int
+	HealthCheckPeriod  int
+	StatementTimeoutMs int
+	LockTimeoutMs      int
+	TxMaxRetries       int
+	TxRetryBackoffMs   int
 }
 
 type SchedulerConfig struct {
-	CheckInterval int
+	CheckInterval         int
+	ExpirySpec            string
+	RemindersSpec         string
+	SalesOpenSpec         string
+	CleanupSpec           string
+	ReportsSpec           string
+	ReconcileSpec         string
+	ReconcileRepair       bool
+	Jitter                int
+	JobTimeout            int
+	ReminderBeforeMinutes int
+	HistoryRetentionDays  int
 }
 
 type TelegramConfig struct {
 	BotToken string
 }
 
-func GetConfig() Config {
-	viper.SetConfigFile(".env")
+type TracingConfig struct {
+	Exporter    string
+	Endpoint    string
+	ServiceName string
+	SampleRatio float64
+}
 
-	err := viper.ReadInConfig()
-	if err != nil {
-		panic("Failed to read .env file: " + err.Error())
+type BookingConfig struct {
+	// MaxActiveReservations caps the unpaid reservations a user may hold
+	// across all events. Zero disables the cap.
+	MaxActiveReservations int
+	// ClosesBeforeStartMinutes sets sales_close_at of new events this many
+	// minutes before their start, unless they set their own.
+	ClosesBeforeStartMinutes int
+	// DefaultTimeZone is the time zone of events created without one and
+	// outside of a venue.
+	DefaultTimeZone string
+}
+
+// GRPCConfig sets up the gRPC server that serve runs next to the HTTP one.
+type GRPCConfig struct {
+	Enabled bool
+	Host    string
+	Port    int
+	// WatchIntervalMs is how often WatchEvent streams read the event again
+	// in case an announcement of a change was lost.
+	WatchIntervalMs int
+}
+
+// RateLimitConfig sets up the token buckets in front of registration and
+// booking. A zero rate disables that bucket.
+type RateLimitConfig struct {
+	Enabled bool
+	// TrustProxy takes the client address from X-Forwarded-For and
+	// X-Real-IP, which is only safe behind a proxy that sets them.
+	TrustProxy     bool
+	IPPerMinute    int
+	IPBurst        int
+	UserPerMinute  int
+	UserBurst      int
+	EventPerMinute int
+	EventBurst     int
+}
+
+// LoadOptions describes the sources layered on top of the built-in defaults.
+// File is optional: when empty, .env is read if it exists in the working
+// directory. Overrides come from the command line and win over everything.
+type LoadOptions struct {
+	File      string
+	Overrides map[string]string
+}
+
+var defaults = map[string]any{
+	"SRV_HOST":                     "localhost",
+	"SRV_PORT":                     8080,
+	"SRV_SHUTDOWN_DRAIN":           5,
+	"SRV_SHUTDOWN_TIMEOUT":         5,
+	"SRV_IDEMPOTENCY_TTL_HOURS":    24,
+	"SRV_LEGACY_API_DEPRECATED_AT": "2026-10-19",
+	"SRV_LEGACY_API_SUNSET_AT":     "2027-04-30",
+	"SRV_SSE_HEARTBEAT_SECONDS":    15,
+	"SRV_ADMIN_TOKEN":              "",
+	"SRV_ADMIN_TOKEN_FILE":         "",
+
+	"POSTGRES_HOST":          "localhost",
+	"POSTGRES_PORT":          "5432",
+	"POSTGRES_USER":          "",
+	"POSTGRES_PASSWORD":      "",
+	"POSTGRES_PASSWORD_FILE": "",
+	"POSTGRES_DB":            "event_booker",
+	"POSTGRES_SSLMODE":       "disable",
+	"POSTGRES_AUTO_MIGRATE":  false,
+
+	"POSTGRES_MAX_CONNS":            10,
+	"POSTGRES_MIN_CONNS":            0,
+	"POSTGRES_MAX_CONN_LIFETIME":    3600,
+	"POSTGRES_MAX_CONN_IDLE_TIME":   1800,
+	"POSTGRES_HEALTH_CHECK_PERIOD":  60,
+	"POSTGRES_STATEMENT_TIMEOUT_MS": 5000,
+	"POSTGRES_LOCK_TIMEOUT_MS":      2000,
+	"POSTGRES_TX_MAX_RETRIES":       3,
+	"POSTGRES_TX_RETRY_BACKOFF_MS":  20,
+
+	"SCHEDULER_CHECK_INTERVAL":          10,
+	"SCHEDULER_EXPIRY_SPEC":             "",
+	"SCHEDULER_REMINDERS_SPEC":          "@every 1m",
+	"SCHEDULER_SALES_OPEN_SPEC":         "@every 1m",
+	"SCHEDULER_CLEANUP_SPEC":            "0 3 * * *",
+	"SCHEDULER_REPORTS_SPEC":            "0 9 * * *",
+	"SCHEDULER_RECONCILE_SPEC":          "*/15 * * * *",
+	"SCHEDULER_RECONCILE_REPAIR":        false,
+	"SCHEDULER_JITTER":                  0,
+	"SCHEDULER_JOB_TIMEOUT":             60,
+	"SCHEDULER_REMINDER_BEFORE_MINUTES": 15,
+	"SCHEDULER_HISTORY_RETENTION_DAYS":  30,
+
+	"TELEGRAM_BOT_TOKEN":      "",
+	"TELEGRAM_BOT_TOKEN_FILE": "",
+
+	"TRACING_EXPORTER":      "none",
+	"TRACING_OTLP_ENDPOINT": "",
+	"TRACING_SERVICE_NAME":  "event-booker",
+	"TRACING_SAMPLE_RATIO":  1.0,
+
+	"BOOKING_MAX_ACTIVE_RESERVATIONS":     5,
+	"BOOKING_CLOSES_BEFORE_START_MINUTES": 0,
+	"BOOKING_DEFAULT_TIME_ZONE":           "UTC",
+
+	"RATE_LIMIT_ENABLED":          true,
+	"RATE_LIMIT_TRUST_PROXY":      false,
+	"RATE_LIMIT_IP_PER_MINUTE":    60,
+	"RATE_LIMIT_IP_BURST":         20,
+	"RATE_LIMIT_USER_PER_MINUTE":  10,
+	"RATE_LIMIT_USER_BURST":       5,
+	"RATE_LIMIT_EVENT_PER_MINUTE": 6000,
+	"RATE_LIMIT_EVENT_BURST":      500,
+
+	"GRPC_ENABLED":           true,
+	"GRPC_HOST":              "localhost",
+	"GRPC_PORT":              9090,
+	"GRPC_WATCH_INTERVAL_MS": 30000,
+}
+
+// Load builds the configuration from defaults, the optional config file
+// (.env or YAML), environment variables and overrides, in that order of
+// precedence, and validates the result.
+func Load(opts LoadOptions) (Config, error) {
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
 	}
+	v.AutomaticEnv()
 
-	return Config{
+	if err := readFile(v, opts.File); err != nil {
+		return Config{}, err
+	}
+
+	for key, value := range opts.Overrides {
+		key = strings.ToUpper(key)
+		if _, ok := defaults[key]; !ok {
+			return Config{}, fmt.Errorf("unknown configuration key %q", key)
+		}
+		v.Set(key, value)
+	}
+
+	r := &reader{v: v}
+	cfg := Config{
 		Server: Server{
-			Host: viper.GetString("SRV_HOST"),
-			Port: viper.GetInt("SRV_PORT"),
+			Host:                  r.string("SRV_HOST"),
+			Port:                  r.int("SRV_PORT"),
+			ShutdownDrain:         r.int("SRV_SHUTDOWN_DRAIN"),
+			ShutdownTimeout:       r.int("SRV_SHUTDOWN_TIMEOUT"),
+			IdempotencyTTLHours:   r.int("SRV_IDEMPOTENCY_TTL_HOURS"),
+			LegacyAPIDeprecatedAt: r.string("SRV_LEGACY_API_DEPRECATED_AT"),
+			LegacyAPISunsetAt:     r.string("SRV_LEGACY_API_SUNSET_AT"),
+			SSEHeartbeatSeconds:   r.int("SRV_SSE_HEARTBEAT_SECONDS"),
+			AdminToken:            r.secret("SRV_ADMIN_TOKEN"),
 		},
 		Postgres: Postgres{
-			Username: viper.GetString("POSTGRES_USER"),
-			Password: viper.GetString("POSTGRES_PASSWORD"),
-			Host:     viper.GetString("POSTGRES_HOST"),
-			Port:     viper.GetString("POSTGRES_PORT"),
-			DBName:   viper.GetString("POSTGRES_DB"),
-			SslMode:  viper.GetString("POSTGRES_SSLMODE"),
+			Username:    r.string("POSTGRES_USER"),
+			Password:    r.secret("POSTGRES_PASSWORD"),
+			Host:        r.string("POSTGRES_HOST"),
+			Port:        r.string("POSTGRES_PORT"),
+			DBName:      r.string("POSTGRES_DB"),
+			SslMode:     r.string("POSTGRES_SSLMODE"),
+			AutoMigrate: r.bool("POSTGRES_AUTO_MIGRATE"),
+
+			MaxConns:           r.int("POSTGRES_MAX_CONNS"),
+			MinConns:           r.int("POSTGRES_MIN_CONNS"),
+			MaxConnLifetime:    r.int("POSTGRES_MAX_CONN_LIFETIME"),
+			MaxConnIdleTime:    r.int("POSTGRES_MAX_CONN_IDLE_TIME"),
+			HealthCheckPeriod:  r.int("POSTGRES_HEALTH_CHECK_PERIOD"),
+			StatementTimeoutMs: r.int("POSTGRES_STATEMENT_TIMEOUT_MS"),
+			LockTimeoutMs:      r.int("POSTGRES_LOCK_TIMEOUT_MS"),
+			TxMaxRetries:       r.int("POSTGRES_TX_MAX_RETRIES"),
+			TxRetryBackoffMs:   r.int("POSTGRES_TX_RETRY_BACKOFF_MS"),
 		},
 		Scheduler: SchedulerConfig{
-			CheckInterval: viper.GetInt("SCHEDULER_CHECK_INTERVAL"),
+			CheckInterval:         r.int("SCHEDULER_CHECK_INTERVAL"),
+			ExpirySpec:            r.string("SCHEDULER_EXPIRY_SPEC"),
+			RemindersSpec:         r.string("SCHEDULER_REMINDERS_SPEC"),
+			SalesOpenSpec:         r.string("SCHEDULER_SALES_OPEN_SPEC"),
+			CleanupSpec:           r.string("SCHEDULER_CLEANUP_SPEC"),
+			ReportsSpec:           r.string("SCHEDULER_REPORTS_SPEC"),
+			ReconcileSpec:         r.string("SCHEDULER_RECONCILE_SPEC"),
+			ReconcileRepair:       r.bool("SCHEDULER_RECONCILE_REPAIR"),
+			Jitter:                r.int("SCHEDULER_JITTER"),
+			JobTimeout:            r.int("SCHEDULER_JOB_TIMEOUT"),
+			ReminderBeforeMinutes: r.int("SCHEDULER_REMINDER_BEFORE_MINUTES"),
+			HistoryRetentionDays:  r.int("SCHEDULER_HISTORY_RETENTION_DAYS"),
 		},
 		Telegram: TelegramConfig{
-			BotToken: viper.GetString("TELEGRAM_BOT_TOKEN"),
+			BotToken: r.secret("TELEGRAM_BOT_TOKEN"),
 		},
+		Tracing: TracingConfig{
+			Exporter:    r.string("TRACING_EXPORTER"),
+			Endpoint:    r.string("TRACING_OTLP_ENDPOINT"),
+			ServiceName: r.string("TRACING_SERVICE_NAME"),
+			SampleRatio: r.float("TRACING_SAMPLE_RATIO"),
+		},
+		Booking: BookingConfig{
+			MaxActiveReservations:    r.int("BOOKING_MAX_ACTIVE_RESERVATIONS"),
+			ClosesBeforeStartMinutes: r.int("BOOKING_CLOSES_BEFORE_START_MINUTES"),
+			DefaultTimeZone:          r.string("BOOKING_DEFAULT_TIME_ZONE"),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        r.bool("RATE_LIMIT_ENABLED"),
+			TrustProxy:     r.bool("RATE_LIMIT_TRUST_PROXY"),
+			IPPerMinute:    r.int("RATE_LIMIT_IP_PER_MINUTE"),
+			IPBurst:        r.int("RATE_LIMIT_IP_BURST"),
+			UserPerMinute:  r.int("RATE_LIMIT_USER_PER_MINUTE"),
+			UserBurst:      r.int("RATE_LIMIT_USER_BURST"),
+			EventPerMinute: r.int("RATE_LIMIT_EVENT_PER_MINUTE"),
+			EventBurst:     r.int("RATE_LIMIT_EVENT_BURST"),
+		},
+		GRPC: GRPCConfig{
+			Enabled:         r.bool("GRPC_ENABLED"),
+			Host:            r.string("GRPC_HOST"),
+			Port:            r.int("GRPC_PORT"),
+			WatchIntervalMs: r.int("GRPC_WATCH_INTERVAL_MS"),
+		},
+	}
+
+	if cfg.Scheduler.ExpirySpec == "" && cfg.Scheduler.CheckInterval > 0 {
+		cfg.Scheduler.ExpirySpec = fmt.Sprintf("@every %ds", cfg.Scheduler.CheckInterval)
+	}
+
+	problems := r.problems
+	for _, problem := range cfg.validate() {
+		key, _, _ := strings.Cut(problem, ":")
+		if !r.failed[key] {
+			problems = append(problems, problem)
+		}
 	}
+	if len(problems) > 0 {
+		return Config{}, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+func readFile(v *viper.Viper, path string) error {
+	if path == "" {
+		if _, err := os.Stat(defaultFile); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		path = defaultFile
+	}
+
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("read config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// reader converts raw values and remembers every conversion failure so that
+// all of them are reported at once.
+type reader struct {
+	v        *viper.Viper
+	problems []string
+	failed   map[string]bool
+}
+
+func (r *reader) fail(key, format string, args ...any) {
+	if r.failed == nil {
+		r.failed = make(map[string]bool)
+	}
+	r.failed[key] = true
+	r.problems = append(r.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (r *reader) string(key string) string {
+	return strings.TrimSpace(r.v.GetString(key))
+}
+
+func (r *reader) int(key string) int {
+	value, err := cast.ToIntE(r.v.Get(key))
+	if err != nil {
+		r.fail(key, "%q is not an integer", r.v.GetString(key))
+	}
+	return value
+}
+
+func (r *reader) bool(key string) bool {
+	value, err := cast.ToBoolE(r.v.Get(key))
+	if err != nil {
+		r.fail(key, "%q is not a boolean", r.v.GetString(key))
+	}
+	return value
+}
+
+func (r *reader) float(key string) float64 {
+	value, err := cast.ToFloat64E(r.v.Get(key))
+	if err != nil {
+		r.fail(key, "%q is not a number", r.v.GetString(key))
+	}
+	return value
+}
+
+// secret reads key from the file named by key_FILE when it is set, which
+// lets the value be mounted as a Docker or Kubernetes secret.
+func (r *reader) secret(key string) string {
+	path := r.string(key + "_FILE")
+	if path == "" {
+		return r.string(key)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		r.fail(key+"_FILE", "%v", err)
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
 }
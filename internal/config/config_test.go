@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// load runs Load in an empty directory, so that a .env of the developer does
+// not leak into the test.
+func load(t *testing.T, opts LoadOptions) (Config, error) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	return Load(opts)
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dotenv := "SRV_HOST=file-host\nSRV_PORT=9000\nPOSTGRES_USER=file-user\nTRACING_SERVICE_NAME=file-service\n"
+	if err := os.WriteFile(defaultFile, []byte(dotenv), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	t.Setenv("SRV_HOST", "env-host")
+	t.Setenv("TRACING_SERVICE_NAME", "env-service")
+
+	cfg, err := Load(LoadOptions{Overrides: map[string]string{"tracing_service_name": "flag-service"}})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	checks := []struct {
+		source string
+		got    any
+		want   any
+	}{
+		{"default", cfg.Postgres.DBName, "event_booker"},
+		{"default", cfg.Scheduler.ExpirySpec, "@every 10s"},
+		{"file", cfg.Server.Port, 9000},
+		{"file", cfg.Postgres.Username, "file-user"},
+		{"environment over file", cfg.Server.Host, "env-host"},
+		{"override over environment", cfg.Tracing.ServiceName, "flag-service"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.source, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeFile(t, "config.yaml", "SRV_PORT: 9100\nPOSTGRES_USER: yaml-user\n")
+	cfg, err := load(t, LoadOptions{File: path})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Server.Port != 9100 || cfg.Postgres.Username != "yaml-user" {
+		t.Errorf("YAML file: port %d, user %q", cfg.Server.Port, cfg.Postgres.Username)
+	}
+
+	if _, err := load(t, LoadOptions{File: filepath.Join(t.TempDir(), "missing.env")}); err == nil {
+		t.Errorf("Load with a missing config file = nil, want an error")
+	}
+
+	_, err = load(t, LoadOptions{Overrides: map[string]string{"POSTGRES_USER": "booker", "SRV_PROT": "1"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown configuration key "SRV_PROT"`) {
+		t.Errorf("Load with an unknown override = %v", err)
+	}
+}
+
+func TestLoadSecretFile(t *testing.T) {
+	secret := writeFile(t, "password", "s3cret\n")
+	cfg, err := load(t, LoadOptions{Overrides: map[string]string{
+		"POSTGRES_USER":          "booker",
+		"POSTGRES_PASSWORD":      "ignored",
+		"POSTGRES_PASSWORD_FILE": secret,
+	}})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Postgres.Password != "s3cret" {
+		t.Errorf("password = %q, want the content of the secret file", cfg.Postgres.Password)
+	}
+
+	_, err = load(t, LoadOptions{Overrides: map[string]string{
+		"POSTGRES_USER":          "booker",
+		"POSTGRES_PASSWORD_FILE": filepath.Join(t.TempDir(), "missing"),
+	}})
+	if problems := problemsOf(t, err); len(problems) != 1 || !strings.HasPrefix(problems[0], "POSTGRES_PASSWORD_FILE: ") {
+		t.Errorf("missing secret file: %v", problems)
+	}
+}
+
+func problemsOf(t *testing.T, err error) []string {
+	t.Helper()
+
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error = %v, want a ValidationError", err)
+	}
+	return invalid.Problems
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		set  map[string]string
+		want string
+	}{
+		{map[string]string{"SRV_PORT": "x"}, `SRV_PORT: "x" is not an integer`},
+		{map[string]string{"POSTGRES_AUTO_MIGRATE": "maybe"}, `POSTGRES_AUTO_MIGRATE: "maybe" is not a boolean`},
+		{map[string]string{"TRACING_SAMPLE_RATIO": "half"}, `TRACING_SAMPLE_RATIO: "half" is not a number`},
+
+		{map[string]string{"SRV_PORT": "0"}, "SRV_PORT: must be between 1 and 65535, got 0"},
+		{map[string]string{"SRV_SHUTDOWN_DRAIN": "-1"}, "SRV_SHUTDOWN_DRAIN: must not be negative, got -1"},
+		{map[string]string{"SRV_SHUTDOWN_TIMEOUT": "0"}, "SRV_SHUTDOWN_TIMEOUT: must be positive, got 0"},
+		{map[string]string{"SRV_IDEMPOTENCY_TTL_HOURS": "0"}, "SRV_IDEMPOTENCY_TTL_HOURS: must be positive, got 0"},
+		{map[string]string{"SRV_SSE_HEARTBEAT_SECONDS": "0"}, "SRV_SSE_HEARTBEAT_SECONDS: must be positive, got 0"},
+		{map[string]string{"SRV_LEGACY_API_DEPRECATED_AT": "soon"}, `SRV_LEGACY_API_DEPRECATED_AT: must be a YYYY-MM-DD date, got "soon"`},
+		{map[string]string{"SRV_LEGACY_API_SUNSET_AT": "later"}, `SRV_LEGACY_API_SUNSET_AT: must be a YYYY-MM-DD date, got "later"`},
+		{map[string]string{"SRV_LEGACY_API_SUNSET_AT": "2026-01-01"}, "SRV_LEGACY_API_SUNSET_AT: must be after SRV_LEGACY_API_DEPRECATED_AT"},
+
+		{map[string]string{"POSTGRES_PORT": "pg"}, `POSTGRES_PORT: must be between 1 and 65535, got "pg"`},
+		{map[string]string{"POSTGRES_HOST": ""}, "POSTGRES_HOST: is required"},
+		{map[string]string{"POSTGRES_USER": ""}, "POSTGRES_USER: is required"},
+		{map[string]string{"POSTGRES_DB": ""}, "POSTGRES_DB: is required"},
+		{map[string]string{"POSTGRES_MAX_CONNS": "0"}, "POSTGRES_MAX_CONNS: must be positive, got 0"},
+		{map[string]string{"POSTGRES_MIN_CONNS": "11"}, "POSTGRES_MIN_CONNS: must be between 0 and POSTGRES_MAX_CONNS, got 11"},
+		{map[string]string{"POSTGRES_MAX_CONN_LIFETIME": "0"}, "POSTGRES_MAX_CONN_LIFETIME: must be positive, got 0"},
+		{map[string]string{"POSTGRES_MAX_CONN_IDLE_TIME": "0"}, "POSTGRES_MAX_CONN_IDLE_TIME: must be positive, got 0"},
+		{map[string]string{"POSTGRES_HEALTH_CHECK_PERIOD": "0"}, "POSTGRES_HEALTH_CHECK_PERIOD: must be positive, got 0"},
+		{map[string]string{"POSTGRES_STATEMENT_TIMEOUT_MS": "-1"}, "POSTGRES_STATEMENT_TIMEOUT_MS: must not be negative, got -1"},
+		{map[string]string{"POSTGRES_LOCK_TIMEOUT_MS": "-1"}, "POSTGRES_LOCK_TIMEOUT_MS: must not be negative, got -1"},
+		{map[string]string{"POSTGRES_TX_MAX_RETRIES": "-1"}, "POSTGRES_TX_MAX_RETRIES: must not be negative, got -1"},
+		{map[string]string{"POSTGRES_TX_RETRY_BACKOFF_MS": "-1"}, "POSTGRES_TX_RETRY_BACKOFF_MS: must not be negative, got -1"},
+		{map[string]string{"POSTGRES_SSLMODE": "on"}, `POSTGRES_SSLMODE: must be one of disable, allow, prefer, require, verify-ca, verify-full, got "on"`},
+
+		{map[string]string{"SCHEDULER_CHECK_INTERVAL": "0"}, "SCHEDULER_CHECK_INTERVAL: must be positive, got 0"},
+		{map[string]string{"SCHEDULER_JITTER": "-1"}, "SCHEDULER_JITTER: must not be negative, got -1"},
+		{map[string]string{"SCHEDULER_JOB_TIMEOUT": "0"}, "SCHEDULER_JOB_TIMEOUT: must be positive, got 0"},
+		{map[string]string{"SCHEDULER_REMINDER_BEFORE_MINUTES": "0"}, "SCHEDULER_REMINDER_BEFORE_MINUTES: must be positive, got 0"},
+		{map[string]string{"SCHEDULER_HISTORY_RETENTION_DAYS": "0"}, "SCHEDULER_HISTORY_RETENTION_DAYS: must be positive, got 0"},
+		{map[string]string{"SCHEDULER_EXPIRY_SPEC": "often"}, `SCHEDULER_EXPIRY_SPEC: invalid schedule "often": `},
+		{map[string]string{"SCHEDULER_REMINDERS_SPEC": "often"}, `SCHEDULER_REMINDERS_SPEC: invalid schedule "often": `},
+		{map[string]string{"SCHEDULER_SALES_OPEN_SPEC": "often"}, `SCHEDULER_SALES_OPEN_SPEC: invalid schedule "often": `},
+		{map[string]string{"SCHEDULER_CLEANUP_SPEC": "often"}, `SCHEDULER_CLEANUP_SPEC: invalid schedule "often": `},
+		{map[string]string{"SCHEDULER_REPORTS_SPEC": "often"}, `SCHEDULER_REPORTS_SPEC: invalid schedule "often": `},
+		{map[string]string{"SCHEDULER_RECONCILE_SPEC": "often"}, `SCHEDULER_RECONCILE_SPEC: invalid schedule "often": `},
+
+		{map[string]string{"TRACING_EXPORTER": "jaeger"}, `TRACING_EXPORTER: must be one of none, stdout, otlp, got "jaeger"`},
+		{map[string]string{"TRACING_SAMPLE_RATIO": "2"}, "TRACING_SAMPLE_RATIO: must be between 0 and 1, got 2"},
+		{map[string]string{"TRACING_EXPORTER": "stdout", "TRACING_SERVICE_NAME": ""}, "TRACING_SERVICE_NAME: is required when tracing is enabled"},
+
+		{map[string]string{"BOOKING_MAX_ACTIVE_RESERVATIONS": "-1"}, "BOOKING_MAX_ACTIVE_RESERVATIONS: must not be negative, got -1"},
+		{map[string]string{"BOOKING_CLOSES_BEFORE_START_MINUTES": "-1"}, "BOOKING_CLOSES_BEFORE_START_MINUTES: must not be negative, got -1"},
+		{map[string]string{"BOOKING_DEFAULT_TIME_ZONE": "Mars/Olympus"}, `BOOKING_DEFAULT_TIME_ZONE: unknown time zone "Mars/Olympus"`},
+		{map[string]string{"BOOKING_DEFAULT_TIME_ZONE": "Local"}, `BOOKING_DEFAULT_TIME_ZONE: unknown time zone "Local"`},
+
+		{map[string]string{"RATE_LIMIT_IP_PER_MINUTE": "-1"}, "RATE_LIMIT_IP_PER_MINUTE: must not be negative, got -1"},
+		{map[string]string{"RATE_LIMIT_IP_BURST": "0"}, "RATE_LIMIT_IP_BURST: must be positive when the rate is set, got 0"},
+		{map[string]string{"RATE_LIMIT_USER_PER_MINUTE": "-1"}, "RATE_LIMIT_USER_PER_MINUTE: must not be negative, got -1"},
+		{map[string]string{"RATE_LIMIT_USER_BURST": "0"}, "RATE_LIMIT_USER_BURST: must be positive when the rate is set, got 0"},
+		{map[string]string{"RATE_LIMIT_EVENT_PER_MINUTE": "-1"}, "RATE_LIMIT_EVENT_PER_MINUTE: must not be negative, got -1"},
+		{map[string]string{"RATE_LIMIT_EVENT_BURST": "0"}, "RATE_LIMIT_EVENT_BURST: must be positive when the rate is set, got 0"},
+
+		{map[string]string{"GRPC_PORT": "70000"}, "GRPC_PORT: must be between 1 and 65535, got 70000"},
+		{map[string]string{"GRPC_PORT": "8080"}, "GRPC_PORT: must differ from SRV_PORT"},
+		{map[string]string{"GRPC_WATCH_INTERVAL_MS": "0"}, "GRPC_WATCH_INTERVAL_MS: must be positive, got 0"},
+	}
+
+	for _, c := range cases {
+		overrides := map[string]string{"POSTGRES_USER": "booker"}
+		for key, value := range c.set {
+			overrides[key] = value
+		}
+
+		_, err := load(t, LoadOptions{Overrides: overrides})
+		problems := problemsOf(t, err)
+		if len(problems) != 1 || !strings.HasPrefix(problems[0], c.want) {
+			t.Errorf("%v: problems = %q, want %q", c.set, problems, c.want)
+		}
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	_, err := load(t, LoadOptions{Overrides: map[string]string{"SRV_PORT": "0", "POSTGRES_DB": ""}})
+
+	problems := problemsOf(t, err)
+	for _, want := range []string{"SRV_PORT: must be between 1 and 65535, got 0", "POSTGRES_USER: is required", "POSTGRES_DB: is required"} {
+		if !slices.Contains(problems, want) {
+			t.Errorf("problems %q do not include %q", problems, want)
+		}
+	}
+
+	// GRPC settings are not checked while the server is disabled.
+	_, err = load(t, LoadOptions{Overrides: map[string]string{"POSTGRES_USER": "booker", "GRPC_ENABLED": "false", "GRPC_PORT": "0"}})
+	if err != nil {
+		t.Errorf("Load with gRPC disabled: %v", err)
+	}
+}
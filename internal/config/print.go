@@ -0,0 +1,90 @@
+package config
+
+import (
+	"strconv"
+)
+
+const redacted = "******"
+
+type Entry struct {
+	Key   string
+	Value string
+}
+
+// Redacted lists the effective settings in the same KEY=value form the
+// config file uses, with secrets masked so the output can be shared.
+func (c Config) Redacted() []Entry {
+	return []Entry{
+		{"SRV_HOST", c.Server.Host},
+		{"SRV_PORT", strconv.Itoa(c.Server.Port)},
+		{"SRV_SHUTDOWN_DRAIN", strconv.Itoa(c.Server.ShutdownDrain)},
+		{"SRV_SHUTDOWN_TIMEOUT", strconv.Itoa(c.Server.ShutdownTimeout)},
+		{"SRV_IDEMPOTENCY_TTL_HOURS", strconv.Itoa(c.Server.IdempotencyTTLHours)},
+		{"SRV_LEGACY_API_DEPRECATED_AT", c.Server.LegacyAPIDeprecatedAt},
+		{"SRV_LEGACY_API_SUNSET_AT", c.Server.LegacyAPISunsetAt},
+		{"SRV_SSE_HEARTBEAT_SECONDS", strconv.Itoa(c.Server.SSEHeartbeatSeconds)},
+		{"SRV_ADMIN_TOKEN", mask(c.Server.AdminToken)},
+
+		{"POSTGRES_HOST", c.Postgres.Host},
+		{"POSTGRES_PORT", c.Postgres.Port},
+		{"POSTGRES_USER", c.Postgres.Username},
+		{"POSTGRES_PASSWORD", mask(c.Postgres.Password)},
+		{"POSTGRES_DB", c.Postgres.DBName},
+		{"POSTGRES_SSLMODE", c.Postgres.SslMode},
+		{"POSTGRES_AUTO_MIGRATE", strconv.FormatBool(c.Postgres.AutoMigrate)},
+		{"POSTGRES_MAX_CONNS", strconv.Itoa(c.Postgres.MaxConns)},
+		{"POSTGRES_MIN_CONNS", strconv.Itoa(c.Postgres.MinConns)},
+		{"POSTGRES_MAX_CONN_LIFETIME", strconv.Itoa(c.Postgres.MaxConnLifetime)},
+		{"POSTGRES_MAX_CONN_IDLE_TIME", strconv.Itoa(c.Postgres.MaxConnIdleTime)},
+		{"POSTGRES_HEALTH_CHECK_PERIOD", strconv.Itoa(c.Postgres.HealthCheckPeriod)},
+		{"POSTGRES_STATEMENT_TIMEOUT_MS", strconv.Itoa(c.Postgres.StatementTimeoutMs)},
+		{"POSTGRES_LOCK_TIMEOUT_MS", strconv.Itoa(c.Postgres.LockTimeoutMs)},
+		{"POSTGRES_TX_MAX_RETRIES", strconv.Itoa(c.Postgres.TxMaxRetries)},
+		{"POSTGRES_TX_RETRY_BACKOFF_MS", strconv.Itoa(c.Postgres.TxRetryBackoffMs)},
+
+		{"SCHEDULER_CHECK_INTERVAL", strconv.Itoa(c.Scheduler.CheckInterval)},
+		{"SCHEDULER_EXPIRY_SPEC", c.Scheduler.ExpirySpec},
+		{"SCHEDULER_REMINDERS_SPEC", c.Scheduler.RemindersSpec},
+		{"SCHEDULER_SALES_OPEN_SPEC", c.Scheduler.SalesOpenSpec},
+		{"SCHEDULER_CLEANUP_SPEC", c.Scheduler.CleanupSpec},
+		{"SCHEDULER_REPORTS_SPEC", c.Scheduler.ReportsSpec},
+		{"SCHEDULER_RECONCILE_SPEC", c.Scheduler.ReconcileSpec},
+		{"SCHEDULER_RECONCILE_REPAIR", strconv.FormatBool(c.Scheduler.ReconcileRepair)},
+		{"SCHEDULER_JITTER", strconv.Itoa(c.Scheduler.Jitter)},
+		{"SCHEDULER_JOB_TIMEOUT", strconv.Itoa(c.Scheduler.JobTimeout)},
+		{"SCHEDULER_REMINDER_BEFORE_MINUTES", strconv.Itoa(c.Scheduler.ReminderBeforeMinutes)},
+		{"SCHEDULER_HISTORY_RETENTION_DAYS", strconv.Itoa(c.Scheduler.HistoryRetentionDays)},
+
+		{"TELEGRAM_BOT_TOKEN", mask(c.Telegram.BotToken)},
+
+		{"TRACING_EXPORTER", c.Tracing.Exporter},
+		{"TRACING_OTLP_ENDPOINT", c.Tracing.Endpoint},
+		{"TRACING_SERVICE_NAME", c.Tracing.ServiceName},
+		{"TRACING_SAMPLE_RATIO", strconv.FormatFloat(c.Tracing.SampleRatio, 'g', -1, 64)},
+
+		{"BOOKING_MAX_ACTIVE_RESERVATIONS", strconv.Itoa(c.Booking.MaxActiveReservations)},
+		{"BOOKING_CLOSES_BEFORE_START_MINUTES", strconv.Itoa(c.Booking.ClosesBeforeStartMinutes)},
+		{"BOOKING_DEFAULT_TIME_ZONE", c.Booking.DefaultTimeZone},
+
+		{"RATE_LIMIT_ENABLED", strconv.FormatBool(c.RateLimit.Enabled)},
+		{"RATE_LIMIT_TRUST_PROXY", strconv.FormatBool(c.RateLimit.TrustProxy)},
+		{"RATE_LIMIT_IP_PER_MINUTE", strconv.Itoa(c.RateLimit.IPPerMinute)},
+		{"RATE_LIMIT_IP_BURST", strconv.Itoa(c.RateLimit.IPBurst)},
+		{"RATE_LIMIT_USER_PER_MINUTE", strconv.Itoa(c.RateLimit.UserPerMinute)},
+		{"RATE_LIMIT_USER_BURST", strconv.Itoa(c.RateLimit.UserBurst)},
+		{"RATE_LIMIT_EVENT_PER_MINUTE", strconv.Itoa(c.RateLimit.EventPerMinute)},
+		{"RATE_LIMIT_EVENT_BURST", strconv.Itoa(c.RateLimit.EventBurst)},
+
+		{"GRPC_ENABLED", strconv.FormatBool(c.GRPC.Enabled)},
+		{"GRPC_HOST", c.GRPC.Host},
+		{"GRPC_PORT", strconv.Itoa(c.GRPC.Port)},
+		{"GRPC_WATCH_INTERVAL_MS", strconv.Itoa(c.GRPC.WatchIntervalMs)},
+	}
+}
+
+func mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+var (
+	sslModes  = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	exporters = []string{"none", "stdout", "otlp"}
+)
+
+func (c Config) validate() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "SRV_PORT: must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ShutdownDrain >= 0, "SRV_SHUTDOWN_DRAIN: must not be negative, got %d", c.Server.ShutdownDrain)
+	check(c.Server.ShutdownTimeout > 0, "SRV_SHUTDOWN_TIMEOUT: must be positive, got %d", c.Server.ShutdownTimeout)
+	check(c.Server.IdempotencyTTLHours > 0, "SRV_IDEMPOTENCY_TTL_HOURS: must be positive, got %d", c.Server.IdempotencyTTLHours)
+	check(c.Server.SSEHeartbeatSeconds > 0, "SRV_SSE_HEARTBEAT_SECONDS: must be positive, got %d", c.Server.SSEHeartbeatSeconds)
+	deprecatedAt, errDeprecated := time.Parse(time.DateOnly, c.Server.LegacyAPIDeprecatedAt)
+	check(errDeprecated == nil, "SRV_LEGACY_API_DEPRECATED_AT: must be a YYYY-MM-DD date, got %q", c.Server.LegacyAPIDeprecatedAt)
+	sunsetAt, errSunset := time.Parse(time.DateOnly, c.Server.LegacyAPISunsetAt)
+	check(errSunset == nil, "SRV_LEGACY_API_SUNSET_AT: must be a YYYY-MM-DD date, got %q", c.Server.LegacyAPISunsetAt)
+	if errDeprecated == nil && errSunset == nil {
+		check(sunsetAt.After(deprecatedAt), "SRV_LEGACY_API_SUNSET_AT: must be after SRV_LEGACY_API_DEPRECATED_AT")
+	}
+
+	port, err := strconv.Atoi(c.Postgres.Port)
+	check(err == nil && port > 0 && port <= 65535, "POSTGRES_PORT: must be between 1 and 65535, got %q", c.Postgres.Port)
+	check(c.Postgres.Host != "", "POSTGRES_HOST: is required")
+	check(c.Postgres.Username != "", "POSTGRES_USER: is required")
+	check(c.Postgres.DBName != "", "POSTGRES_DB: is required")
+	check(c.Postgres.MaxConns > 0, "POSTGRES_MAX_CONNS: must be positive, got %d", c.Postgres.MaxConns)
+	check(c.Postgres.MinConns >= 0 && c.Postgres.MinConns <= c.Postgres.MaxConns, "POSTGRES_MIN_CONNS: must be between 0 and POSTGRES_MAX_CONNS, got %d", c.Postgres.MinConns)
+	check(c.Postgres.MaxConnLifetime > 0, "POSTGRES_MAX_CONN_LIFETIME: must be positive, got %d", c.Postgres.MaxConnLifetime)
+	check(c.Postgres.MaxConnIdleTime > 0, "POSTGRES_MAX_CONN_IDLE_TIME: must be positive, got %d", c.Postgres.MaxConnIdleTime)
+	check(c.Postgres.HealthCheckPeriod > 0, "POSTGRES_HEALTH_CHECK_PERIOD: must be positive, got %d", c.Postgres.HealthCheckPeriod)
+	check(c.Postgres.StatementTimeoutMs >= 0, "POSTGRES_STATEMENT_TIMEOUT_MS: must not be negative, got %d", c.Postgres.StatementTimeoutMs)
+	check(c.Postgres.LockTimeoutMs >= 0, "POSTGRES_LOCK_TIMEOUT_MS: must not be negative, got %d", c.Postgres.LockTimeoutMs)
+	check(c.Postgres.TxMaxRetries >= 0, "POSTGRES_TX_MAX_RETRIES: must not be negative, got %d", c.Postgres.TxMaxRetries)
+	check(c.Postgres.TxRetryBackoffMs >= 0, "POSTGRES_TX_RETRY_BACKOFF_MS: must not be negative, got %d", c.Postgres.TxRetryBackoffMs)
+	check(slices.Contains(sslModes, c.Postgres.SslMode), "POSTGRES_SSLMODE: must be one of %s, got %q", strings.Join(sslModes, ", "), c.Postgres.SslMode)
+
+	check(c.Scheduler.CheckInterval > 0, "SCHEDULER_CHECK_INTERVAL: must be positive, got %d", c.Scheduler.CheckInterval)
+	check(c.Scheduler.Jitter >= 0, "SCHEDULER_JITTER: must not be negative, got %d", c.Scheduler.Jitter)
+	check(c.Scheduler.JobTimeout > 0, "SCHEDULER_JOB_TIMEOUT: must be positive, got %d", c.Scheduler.JobTimeout)
+	check(c.Scheduler.ReminderBeforeMinutes > 0, "SCHEDULER_REMINDER_BEFORE_MINUTES: must be positive, got %d", c.Scheduler.ReminderBeforeMinutes)
+	check(c.Scheduler.HistoryRetentionDays > 0, "SCHEDULER_HISTORY_RETENTION_DAYS: must be positive, got %d", c.Scheduler.HistoryRetentionDays)
+
+	specs := []struct {
+		key  string
+		spec string
+	}{
+		{"SCHEDULER_EXPIRY_SPEC", c.Scheduler.ExpirySpec},
+		{"SCHEDULER_REMINDERS_SPEC", c.Scheduler.RemindersSpec},
+		{"SCHEDULER_SALES_OPEN_SPEC", c.Scheduler.SalesOpenSpec},
+		{"SCHEDULER_CLEANUP_SPEC", c.Scheduler.CleanupSpec},
+		{"SCHEDULER_REPORTS_SPEC", c.Scheduler.ReportsSpec},
+		{"SCHEDULER_RECONCILE_SPEC", c.Scheduler.ReconcileSpec},
+	}
+	for _, s := range specs {
+		if s.spec == "" && s.key == "SCHEDULER_EXPIRY_SPEC" {
+			continue
+		}
+		_, err := cron.ParseStandard(s.spec)
+		check(err == nil, "%s: invalid schedule %q: %v", s.key, s.spec, err)
+	}
+
+	check(slices.Contains(exporters, c.Tracing.Exporter), "TRACING_EXPORTER: must be one of %s, got %q", strings.Join(exporters, ", "), c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO: must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	check(c.Tracing.Exporter == "none" || c.Tracing.ServiceName != "", "TRACING_SERVICE_NAME: is required when tracing is enabled")
+
+	check(c.Booking.MaxActiveReservations >= 0, "BOOKING_MAX_ACTIVE_RESERVATIONS: must not be negative, got %d", c.Booking.MaxActiveReservations)
+	check(c.Booking.ClosesBeforeStartMinutes >= 0, "BOOKING_CLOSES_BEFORE_START_MINUTES: must not be negative, got %d", c.Booking.ClosesBeforeStartMinutes)
+	_, err = time.LoadLocation(c.Booking.DefaultTimeZone)
+	check(c.Booking.DefaultTimeZone != "" && c.Booking.DefaultTimeZone != "Local" && err == nil,
+		"BOOKING_DEFAULT_TIME_ZONE: unknown time zone %q", c.Booking.DefaultTimeZone)
+
+	buckets := []struct {
+		scope     string
+		perMinute int
+		burst     int
+	}{
+		{"IP", c.RateLimit.IPPerMinute, c.RateLimit.IPBurst},
+		{"USER", c.RateLimit.UserPerMinute, c.RateLimit.UserBurst},
+		{"EVENT", c.RateLimit.EventPerMinute, c.RateLimit.EventBurst},
+	}
+	for _, b := range buckets {
+		check(b.perMinute >= 0, "RATE_LIMIT_%s_PER_MINUTE: must not be negative, got %d", b.scope, b.perMinute)
+		check(b.perMinute == 0 || b.burst > 0, "RATE_LIMIT_%s_BURST: must be positive when the rate is set, got %d", b.scope, b.burst)
+	}
+
+	if c.GRPC.Enabled {
+		check(c.GRPC.Port > 0 && c.GRPC.Port <= 65535, "GRPC_PORT: must be between 1 and 65535, got %d", c.GRPC.Port)
+		check(c.GRPC.Port != c.Server.Port || c.GRPC.Host != c.Server.Host, "GRPC_PORT: must differ from SRV_PORT")
+		check(c.GRPC.WatchIntervalMs > 0, "GRPC_WATCH_INTERVAL_MS: must be positive, got %d", c.GRPC.WatchIntervalMs)
+	}
+
+	return problems
+}
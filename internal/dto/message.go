@@ -1,6 +1,19 @@
 package dto
 
+// TelegramTimeLayout formats times in notifications. They are shown in the
+// time zone of the event, whose abbreviation or offset ends the line.
+const TelegramTimeLayout = "2006-01-02 15:04 MST"
+
 const TelegramBookingCancel = "Ваша бронь на мероприятие \"%s\" была отменена из-за истечения срока оплаты.\n\n" +
 	"Бронь ID: %s\n" +
 	"Мероприятие: %s\n" +
 	"Дата мероприятия: %s"
+
+const TelegramSalesOpened = "Открылась продажа билетов на мероприятие \"%s\".\n\n" +
+	"Дата мероприятия: %s\n" +
+	"Продажа закроется: %s\n" +
+	"Мероприятие ID: %s"
+
+const TelegramBookingReminder = "Напоминаем: бронь на мероприятие \"%s\" необходимо оплатить до %s, " +
+	"иначе она будет отменена.\n\n" +
+	"Бронь ID: %s"
@@ -8,6 +8,12 @@ type BookEventRequest struct {
 	Email string `json:"email"`
 }
 
+// SubscribeRequest subscribes the user with Email to the opening of the
+// ticket sales of an event.
+type SubscribeRequest struct {
+	Email string `json:"email"`
+}
+
 type ConfirmBookingRequest struct {
 	BookingID uuid.UUID `json:"booking_id"`
 }
@@ -25,4 +31,38 @@ type CreateEventRequest struct {
 	BookingLifetimeHours   int    `json:"booking_lifetime_hours"`
 	BookingLifetimeMinutes int    `json:"booking_lifetime_minutes"`
 	PaymentReq             bool   `json:"requires_payment_confirmation"`
+	Inventory              string `json:"inventory,omitempty"`
+	// EndDate defaults to two hours after Date. TotalSeats may be left out
+	// for an event at a venue, which then gets the venue's default capacity.
+	// TimeZone defaults to the one of the venue or of the server config.
+	EndDate  string     `json:"end_date,omitempty"`
+	VenueID  *uuid.UUID `json:"venue_id,omitempty"`
+	TimeZone string     `json:"time_zone,omitempty"`
+	// Ticket sales open when the event is created unless SalesOpenAt is set,
+	// and close the configured time before Date unless SalesCloseAt is set.
+	SalesOpenAt  string `json:"sales_open_at,omitempty"`
+	SalesCloseAt string `json:"sales_close_at,omitempty"`
+
+	Description      string   `json:"description,omitempty"`
+	Category         string   `json:"category,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	OrganizerContact string   `json:"organizer_contact,omitempty"`
+	CoverImageURL    string   `json:"cover_image_url,omitempty"`
+}
+
+// ListEventsRequest holds the filters of the event list: the category query
+// parameter and any number of tag parameters.
+type ListEventsRequest struct {
+	Category string
+	Tags     []string
+}
+
+// VenueRequest creates a venue or, with PUT, replaces all of its fields.
+type VenueRequest struct {
+	Name            string   `json:"name"`
+	Address         string   `json:"address"`
+	Latitude        *float64 `json:"latitude"`
+	Longitude       *float64 `json:"longitude"`
+	TimeZone        string   `json:"time_zone"`
+	DefaultCapacity int      `json:"default_capacity"`
 }
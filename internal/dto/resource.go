@@ -0,0 +1,297 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+// The types below are the resources of API v1. They are copied field by field
+// from models so that a change to a model does not silently change the API;
+// a new version gets its own types instead of editing these.
+
+type Event struct {
+	ID               uuid.UUID  `json:"id"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Category         string     `json:"category"`
+	Tags             []string   `json:"tags"`
+	OrganizerContact string     `json:"organizer_contact,omitempty"`
+	CoverImageURL    string     `json:"cover_image_url,omitempty"`
+	VenueID          *uuid.UUID `json:"venue_id,omitempty"`
+	Date             time.Time  `json:"date"`
+	EndDate          time.Time  `json:"end_date"`
+	TimeZone         string     `json:"time_zone"`
+	LocalDate        string     `json:"local_date"`
+	LocalEndDate     string     `json:"local_end_date"`
+	// BookingClosesAt is the name sales_close_at had before sales windows;
+	// it is kept for the clients of v1.
+	BookingClosesAt time.Time  `json:"booking_closes_at"`
+	SalesOpenAt     *time.Time `json:"sales_open_at,omitempty"`
+	SalesCloseAt    time.Time  `json:"sales_close_at"`
+	SalesStatus     string     `json:"sales_status"`
+	// SalesCountdownSeconds counts down to the opening of upcoming sales or
+	// to the closing of open ones, as of the response. It is not set once
+	// sales have closed.
+	SalesCountdownSeconds *int64     `json:"sales_countdown_seconds,omitempty"`
+	TotalSeats            int        `json:"total_seats"`
+	ReservedSeats         int        `json:"reserved_seats"`
+	BookedSeats           int        `json:"booked_seats"`
+	BookingLifetime       int        `json:"booking_lifetime"`
+	PaymentReq            bool       `json:"requires_payment_confirmation"`
+	Inventory             string     `json:"inventory"`
+	CreatedAt             time.Time  `json:"created_at"`
+	CancelledAt           *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// EventAvailability is the payload of the availability stream of an event.
+type EventAvailability struct {
+	EventID   uuid.UUID `json:"event_id"`
+	Total     int       `json:"total"`
+	Reserved  int       `json:"reserved"`
+	Booked    int       `json:"booked"`
+	Available int       `json:"available"`
+	Cancelled bool      `json:"cancelled"`
+}
+
+type Venue struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Address         string    `json:"address"`
+	Latitude        float64   `json:"latitude"`
+	Longitude       float64   `json:"longitude"`
+	TimeZone        string    `json:"time_zone"`
+	DefaultCapacity int       `json:"default_capacity"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type User struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	TelegramID *int64    `json:"telegram_id,omitempty"`
+	Role       string    `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Booking struct {
+	ID        uuid.UUID `json:"id"`
+	EventID   uuid.UUID `json:"event_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Status    string    `json:"status"`
+	Deadline  time.Time `json:"deadline"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type JobRun struct {
+	ID         uuid.UUID `json:"id"`
+	JobName    string    `json:"job_name"`
+	Trigger    string    `json:"trigger"`
+	Status     string    `json:"status"`
+	Error      *string   `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+type SeatDrift struct {
+	EventID          uuid.UUID `json:"event_id"`
+	EventName        string    `json:"event_name"`
+	TotalSeats       int       `json:"total_seats"`
+	ReservedSeats    int       `json:"reserved_seats"`
+	BookedSeats      int       `json:"booked_seats"`
+	ActualReserved   int       `json:"actual_reserved"`
+	ActualBooked     int       `json:"actual_booked"`
+	StaleSeats       int       `json:"stale_seats"`
+	UnseatedBookings int       `json:"unseated_bookings"`
+	Repaired         bool      `json:"repaired"`
+	Error            *string   `json:"error,omitempty"`
+}
+
+type SeatReconciliation struct {
+	CheckedAt time.Time    `json:"checked_at"`
+	Repair    bool         `json:"repair"`
+	Drifts    []*SeatDrift `json:"drifts"`
+}
+
+// NewEvent also renders the start and end of the event in its time zone, as
+// RFC 3339 timestamps with the local offset, and the state of its sales at
+// the time of the call.
+func NewEvent(e *models.Event) *Event {
+	loc := e.Location()
+	now := time.Now()
+	status := e.SalesStatus(now)
+
+	var countdown *int64
+	switch status {
+	case models.SalesUpcoming:
+		countdown = secondsUntil(now, *e.SalesOpenAt)
+	case models.SalesOpen:
+		countdown = secondsUntil(now, e.SalesCloseAt)
+	}
+
+	return &Event{
+		ID:                    e.ID,
+		Name:                  e.Name,
+		Description:           e.Description,
+		Category:              string(e.Category),
+		Tags:                  e.Tags,
+		OrganizerContact:      e.OrganizerContact,
+		CoverImageURL:         e.CoverImageURL,
+		VenueID:               e.VenueID,
+		Date:                  e.Date,
+		EndDate:               e.EndDate,
+		TimeZone:              e.TimeZone,
+		LocalDate:             e.Date.In(loc).Format(time.RFC3339),
+		LocalEndDate:          e.EndDate.In(loc).Format(time.RFC3339),
+		BookingClosesAt:       e.SalesCloseAt,
+		SalesOpenAt:           e.SalesOpenAt,
+		SalesCloseAt:          e.SalesCloseAt,
+		SalesStatus:           string(status),
+		SalesCountdownSeconds: countdown,
+		TotalSeats:            e.TotalSeats,
+		ReservedSeats:         e.ReservedSeats,
+		BookedSeats:           e.BookedSeats,
+		BookingLifetime:       e.BookingLifetime,
+		PaymentReq:            e.PaymentReq,
+		Inventory:             string(e.Inventory),
+		CreatedAt:             e.CreatedAt,
+		CancelledAt:           e.CancelledAt,
+	}
+}
+
+// secondsUntil rounds up, so a countdown only reaches zero once t has come.
+func secondsUntil(now, t time.Time) *int64 {
+	seconds := int64((t.Sub(now) + time.Second - 1) / time.Second)
+	return &seconds
+}
+
+func NewEvents(events []*models.Event) []*Event {
+	if events == nil {
+		return nil
+	}
+	out := make([]*Event, 0, len(events))
+	for _, e := range events {
+		out = append(out, NewEvent(e))
+	}
+	return out
+}
+
+func NewEventAvailability(e *models.Event) *EventAvailability {
+	return &EventAvailability{
+		EventID:   e.ID,
+		Total:     e.TotalSeats,
+		Reserved:  e.ReservedSeats,
+		Booked:    e.BookedSeats,
+		Available: e.AvailableSeats(),
+		Cancelled: e.IsCancelled(),
+	}
+}
+
+func NewVenue(v *models.Venue) *Venue {
+	return &Venue{
+		ID:              v.ID,
+		Name:            v.Name,
+		Address:         v.Address,
+		Latitude:        v.Latitude,
+		Longitude:       v.Longitude,
+		TimeZone:        v.TimeZone,
+		DefaultCapacity: v.DefaultCapacity,
+		CreatedAt:       v.CreatedAt,
+		UpdatedAt:       v.UpdatedAt,
+	}
+}
+
+func NewVenues(venues []*models.Venue) []*Venue {
+	if venues == nil {
+		return nil
+	}
+	out := make([]*Venue, 0, len(venues))
+	for _, v := range venues {
+		out = append(out, NewVenue(v))
+	}
+	return out
+}
+
+func NewUser(u *models.User) *User {
+	return &User{
+		ID:         u.ID,
+		Name:       u.Name,
+		Email:      u.Email,
+		TelegramID: u.TelegramID,
+		Role:       string(u.Role),
+		CreatedAt:  u.CreatedAt,
+	}
+}
+
+func NewBookings(bookings []*models.Booking) []*Booking {
+	if bookings == nil {
+		return nil
+	}
+	out := make([]*Booking, 0, len(bookings))
+	for _, b := range bookings {
+		out = append(out, &Booking{
+			ID:        b.ID,
+			EventID:   b.EventID,
+			UserID:    b.UserID,
+			Status:    string(b.Status),
+			Deadline:  b.Deadline,
+			CreatedAt: b.CreatedAt,
+			UpdatedAt: b.UpdatedAt,
+		})
+	}
+	return out
+}
+
+func NewJobRun(r *models.JobRun) *JobRun {
+	if r == nil {
+		return nil
+	}
+	return &JobRun{
+		ID:         r.ID,
+		JobName:    r.JobName,
+		Trigger:    string(r.Trigger),
+		Status:     string(r.Status),
+		Error:      r.Error,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+		DurationMs: r.DurationMs,
+	}
+}
+
+func NewJobRuns(runs []*models.JobRun) []*JobRun {
+	if runs == nil {
+		return nil
+	}
+	out := make([]*JobRun, 0, len(runs))
+	for _, r := range runs {
+		out = append(out, NewJobRun(r))
+	}
+	return out
+}
+
+func NewSeatReconciliation(r *models.SeatReconciliation) *SeatReconciliation {
+	drifts := make([]*SeatDrift, 0, len(r.Drifts))
+	for _, d := range r.Drifts {
+		drifts = append(drifts, &SeatDrift{
+			EventID:          d.EventID,
+			EventName:        d.EventName,
+			TotalSeats:       d.TotalSeats,
+			ReservedSeats:    d.ReservedSeats,
+			BookedSeats:      d.BookedSeats,
+			ActualReserved:   d.ActualReserved,
+			ActualBooked:     d.ActualBooked,
+			StaleSeats:       d.StaleSeats,
+			UnseatedBookings: d.UnseatedBookings,
+			Repaired:         d.Repaired,
+			Error:            d.Error,
+		})
+	}
+	return &SeatReconciliation{
+		CheckedAt: r.CheckedAt,
+		Repair:    r.Repair,
+		Drifts:    drifts,
+	}
+}
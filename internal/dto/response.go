@@ -3,11 +3,12 @@ package dto
 import (
 	"github.com/google/uuid"
 	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
 )
 
 type CreateEventResponse struct {
-	Event   *models.Event `json:"event"`
-	Message string        `json:"message"`
+	Event   *Event `json:"event"`
+	Message string `json:"message"`
 }
 
 type BookEventResponse struct {
@@ -20,24 +21,63 @@ type ConfirmBookingResponse struct {
 	Message string `json:"message"`
 }
 
+type SubscribeResponse struct {
+	Message string `json:"message"`
+}
+
 type GetEventResponse struct {
-	Event *models.Event `json:"event"`
+	Event *Event `json:"event"`
 }
 
 type ListEventsResponse struct {
-	Events []*models.Event `json:"events"`
+	Events []*Event `json:"events"`
+}
+
+type VenueResponse struct {
+	Venue   *Venue `json:"venue"`
+	Message string `json:"message,omitempty"`
+}
+
+type ListVenuesResponse struct {
+	Venues []*Venue `json:"venues"`
 }
 
 type ListBookingsResponse struct {
-	Bookings []*models.Booking `json:"bookings"`
+	Bookings []*Booking `json:"bookings"`
 }
 
 type CreateUserResponse struct {
-	User    *models.User `json:"user"`
-	Message string       `json:"message"`
+	User    *User  `json:"user"`
+	Message string `json:"message"`
 }
 
 type TelegramResponse struct {
 	OK          bool   `json:"ok"`
 	Description string `json:"description,omitempty"`
 }
+
+type JobResponse struct {
+	Name    string     `json:"name"`
+	Spec    string     `json:"spec"`
+	Running bool       `json:"running"`
+	NextRun *time.Time `json:"next_run,omitempty"`
+	LastRun *JobRun    `json:"last_run,omitempty"`
+}
+
+type ListJobsResponse struct {
+	Jobs []JobResponse `json:"jobs"`
+	Runs []*JobRun     `json:"runs"`
+}
+
+type RunJobResponse struct {
+	Message string `json:"message"`
+}
+
+type ReconcileSeatsResponse struct {
+	Reconciliation *SeatReconciliation `json:"reconciliation"`
+}
+
+type HealthResponse struct {
+	Status models.CheckStatus            `json:"status"`
+	Checks map[string]models.CheckResult `json:"checks,omitempty"`
+}
@@ -3,89 +3,310 @@ package dto
 import (
 	"errors"
 	"fmt"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"net/url"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 const (
 	MinBookingLifetime = 1
 	MinTotalSeats      = 1
+	MaxSeatInventory   = 100000
 	MinTelegramID      = 1000000
 	MaxTelegramID      = 9999999999
+
+	MaxEventNameLength        = 64
+	MaxDescriptionLength      = 10000
+	MaxTags                   = 10
+	MaxTagLength              = 32
+	MaxOrganizerContactLength = 255
+	MaxCoverImageURLLength    = 2048
+
+	MaxVenueNameLength    = 128
+	MaxVenueAddressLength = 255
 )
 
 var (
 	nameRegex  = regexp.MustCompile(`^[A-Za-zА-Яа-яЁё\s]+$`)
 	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	phoneRegex = regexp.MustCompile(`^\+?[0-9][0-9 ()\-]{5,19}$`)
+	tagRegex   = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}+#.\-]*$`)
 )
 
-func (r *CreateUserRequest) ValidateUser() error {
-	if r.Name == "" {
-		return errors.New("user name is required")
+// NormalizeTags trims and lowercases tags and drops empty and repeated ones,
+// so that "Go" and " go" are the same tag when stored and when filtering.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
 	}
+	return normalized
+}
 
-	if !nameRegex.MatchString(r.Name) {
-		return errors.New("name must contain only letters")
+func validateTags(errs *apperrors.ValidationError, field string, tags []string) {
+	for _, tag := range tags {
+		switch {
+		case utf8.RuneCountInString(tag) > MaxTagLength:
+			errs.Add(field, "tag %q must be at most %d characters", tag, MaxTagLength)
+			return
+		case !tagRegex.MatchString(tag):
+			errs.Add(field, "tag %q must start with a letter or digit and contain only letters, digits and +#.-", tag)
+			return
+		}
 	}
+}
 
-	if r.Email == "" {
-		return errors.New("user email is required")
+func validateCategory(errs *apperrors.ValidationError, category string) {
+	if category != "" && !models.EventCategory(category).Valid() {
+		errs.Add("category", "category must be one of %s", categoryList())
 	}
+}
 
-	if !emailRegex.MatchString(r.Email) {
-		return errors.New("invalid email format")
+func categoryList() string {
+	names := make([]string, len(models.EventCategories))
+	for i, category := range models.EventCategories {
+		names[i] = string(category)
 	}
+	return strings.Join(names, ", ")
+}
 
-	if r.TelegramID == nil {
-		return nil
-	}
+func (r *CreateUserRequest) ValidateUser() error {
+	errs := &apperrors.ValidationError{}
 
-	id := *r.TelegramID
+	switch {
+	case r.Name == "":
+		errs.Add("name", "user name is required")
+	case !nameRegex.MatchString(r.Name):
+		errs.Add("name", "name must contain only letters")
+	}
 
-	if id < MinTelegramID {
-		return fmt.Errorf("telegram id must be >= %d", MinTelegramID)
+	switch {
+	case r.Email == "":
+		errs.Add("email", "user email is required")
+	case !emailRegex.MatchString(r.Email):
+		errs.Add("email", "invalid email format")
 	}
 
-	if id > MaxTelegramID {
-		return fmt.Errorf("telegram id must be <= %d", MaxTelegramID)
+	if r.TelegramID != nil {
+		switch id := *r.TelegramID; {
+		case id < MinTelegramID:
+			errs.Add("telegram_id", "telegram id must be >= %d", MinTelegramID)
+		case id > MaxTelegramID:
+			errs.Add("telegram_id", "telegram id must be <= %d", MaxTelegramID)
+		}
 	}
 
-	return nil
+	return errs.Err()
 }
 
 func (r *CreateEventRequest) ValidateEvent() error {
-	if r.Name == "" {
-		return errors.New("event name is required")
+	errs := &apperrors.ValidationError{}
+
+	switch {
+	case r.Name == "":
+		errs.Add("name", "event name is required")
+	case utf8.RuneCountInString(r.Name) > MaxEventNameLength:
+		errs.Add("name", "event name must be at most %d characters", MaxEventNameLength)
+	}
+
+	if utf8.RuneCountInString(r.Description) > MaxDescriptionLength {
+		errs.Add("description", "description must be at most %d characters", MaxDescriptionLength)
+	}
+
+	validateCategory(errs, r.Category)
+
+	tags := NormalizeTags(r.Tags)
+	if len(tags) > MaxTags {
+		errs.Add("tags", "an event can have at most %d tags", MaxTags)
+	} else {
+		validateTags(errs, "tags", tags)
+	}
+
+	switch contact := r.OrganizerContact; {
+	case contact == "":
+	case len(contact) > MaxOrganizerContactLength:
+		errs.Add("organizer_contact", "organizer contact must be at most %d characters", MaxOrganizerContactLength)
+	case !emailRegex.MatchString(contact) && !phoneRegex.MatchString(contact):
+		errs.Add("organizer_contact", "organizer contact must be an email address or a phone number")
 	}
 
-	if r.TotalSeats < MinTotalSeats {
-		return fmt.Errorf("total number of seats must be greater than or equal to %d", MinTotalSeats)
+	if r.CoverImageURL != "" {
+		if len(r.CoverImageURL) > MaxCoverImageURLLength {
+			errs.Add("cover_image_url", "cover image url must be at most %d characters", MaxCoverImageURLLength)
+		} else if u, err := url.Parse(r.CoverImageURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Add("cover_image_url", "cover image url must be an absolute http or https url")
+		}
+	}
+
+	// Zero seats at a venue means the default capacity of the venue.
+	if r.TotalSeats < MinTotalSeats && (r.VenueID == nil || r.TotalSeats != 0) {
+		errs.Add("total_seats", "total number of seats must be greater than or equal to %d", MinTotalSeats)
 	}
 
 	if r.BookingLifetimeHours < 0 {
-		return errors.New("booking lifetime hours cannot be negative")
+		errs.Add("booking_lifetime_hours", "booking lifetime hours cannot be negative")
 	}
 
 	if r.BookingLifetimeMinutes < 0 || r.BookingLifetimeMinutes > 59 {
-		return errors.New("booking lifetime minutes must be between 0 and 59")
+		errs.Add("booking_lifetime_minutes", "booking lifetime minutes must be between 0 and 59")
+	}
+
+	if !errs.Has("booking_lifetime_hours") && !errs.Has("booking_lifetime_minutes") {
+		bookingLifetime := r.BookingLifetimeHours*60 + r.BookingLifetimeMinutes
+		if r.PaymentReq && bookingLifetime < MinBookingLifetime {
+			errs.Add("booking_lifetime_minutes", "minimum booking lifetime is %d minutes", MinBookingLifetime)
+		}
+	}
+
+	switch models.Inventory(r.Inventory) {
+	case "", models.InventoryCounter:
+	case models.InventorySeats:
+		if r.TotalSeats > MaxSeatInventory {
+			errs.Add("total_seats", "events with seat inventory can have at most %d seats", MaxSeatInventory)
+		}
+	default:
+		errs.Add("inventory", "inventory must be either counter or seats")
+	}
+
+	date, err := time.Parse(time.RFC3339, r.Date)
+	switch {
+	case err != nil:
+		errs.Add("date", "invalid date format")
+	case date.Before(time.Now().UTC()):
+		errs.Add("date", "event date cannot be in the past")
+	}
+
+	if r.TimeZone != "" {
+		if err := ValidateTimeZone(r.TimeZone); err != nil {
+			errs.Add("time_zone", "%s", err)
+		}
+	}
+
+	if r.EndDate != "" {
+		endDate, err := time.Parse(time.RFC3339, r.EndDate)
+		switch {
+		case err != nil:
+			errs.Add("end_date", "invalid end date format")
+		case !errs.Has("date") && !endDate.After(date):
+			errs.Add("end_date", "event end date must be after its start date")
+		}
+	}
+
+	// Whether sales open before they close is checked by the service, which
+	// knows the default close time.
+	if r.SalesOpenAt != "" {
+		if _, err := time.Parse(time.RFC3339, r.SalesOpenAt); err != nil {
+			errs.Add("sales_open_at", "invalid sales open time format")
+		}
+	}
+
+	if r.SalesCloseAt != "" {
+		salesCloseAt, err := time.Parse(time.RFC3339, r.SalesCloseAt)
+		switch {
+		case err != nil:
+			errs.Add("sales_close_at", "invalid sales close time format")
+		case !salesCloseAt.After(time.Now()):
+			errs.Add("sales_close_at", "sales close time cannot be in the past")
+		case !errs.Has("date") && salesCloseAt.After(date):
+			errs.Add("sales_close_at", "sales cannot close after the event starts")
+		}
+	}
+
+	return errs.Err()
+}
+
+func (r *SubscribeRequest) ValidateSubscription() error {
+	errs := &apperrors.ValidationError{}
+
+	switch {
+	case r.Email == "":
+		errs.Add("email", "email is required")
+	case !emailRegex.MatchString(r.Email):
+		errs.Add("email", "invalid email format")
+	}
+
+	return errs.Err()
+}
+
+func (r *VenueRequest) ValidateVenue() error {
+	errs := &apperrors.ValidationError{}
+
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		errs.Add("name", "venue name is required")
+	case utf8.RuneCountInString(r.Name) > MaxVenueNameLength:
+		errs.Add("name", "venue name must be at most %d characters", MaxVenueNameLength)
 	}
 
-	bookingLifetime := r.BookingLifetimeHours*60 + r.BookingLifetimeMinutes
-	if r.PaymentReq && bookingLifetime < MinBookingLifetime {
-		return fmt.Errorf("minimum booking lifetime is %d minutes", MinBookingLifetime)
+	switch {
+	case strings.TrimSpace(r.Address) == "":
+		errs.Add("address", "venue address is required")
+	case utf8.RuneCountInString(r.Address) > MaxVenueAddressLength:
+		errs.Add("address", "venue address must be at most %d characters", MaxVenueAddressLength)
 	}
-	if !r.PaymentReq && bookingLifetime < 0 {
-		return errors.New("booking lifetime cannot be negative")
+
+	switch {
+	case r.Latitude == nil:
+		errs.Add("latitude", "latitude is required")
+	case *r.Latitude < -90 || *r.Latitude > 90:
+		errs.Add("latitude", "latitude must be between -90 and 90")
+	}
+
+	switch {
+	case r.Longitude == nil:
+		errs.Add("longitude", "longitude is required")
+	case *r.Longitude < -180 || *r.Longitude > 180:
+		errs.Add("longitude", "longitude must be between -180 and 180")
 	}
 
-	data, err := time.Parse(time.RFC3339, r.Date)
-	if err != nil {
-		return errors.New("invalid date format")
+	if err := ValidateTimeZone(r.TimeZone); err != nil {
+		errs.Add("time_zone", "%s", err)
 	}
 
-	if data.Before(time.Now().UTC()) {
-		return errors.New("event date cannot be in the past")
+	if r.DefaultCapacity < MinTotalSeats {
+		errs.Add("default_capacity", "default capacity must be greater than or equal to %d", MinTotalSeats)
 	}
 
+	return errs.Err()
+}
+
+// ValidateTimeZone accepts IANA time zone names such as Europe/Moscow. The
+// empty name and "Local", which time.LoadLocation also accepts, depend on
+// the server and are rejected.
+func ValidateTimeZone(name string) error {
+	if name == "" {
+		return errors.New("time zone is required")
+	}
+	if name == "Local" {
+		return fmt.Errorf("unknown time zone %q", name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("unknown time zone %q", name)
+	}
 	return nil
 }
+
+func (r *ListEventsRequest) ValidateFilter() error {
+	errs := &apperrors.ValidationError{}
+
+	validateCategory(errs, r.Category)
+	validateTags(errs, "tag", NormalizeTags(r.Tags))
+
+	return errs.Err()
+}
+
+func (r *ListEventsRequest) EventFilter() models.EventFilter {
+	return models.EventFilter{
+		Category: models.EventCategory(r.Category),
+		Tags:     NormalizeTags(r.Tags),
+	}
+}
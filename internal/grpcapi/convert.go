@@ -0,0 +1,215 @@
+package grpcapi
+
+import (
+	"encoding/base64"
+	"github.com/google/uuid"
+	eventbookerv1 "github.com/kstsm/wb-event-booker/api/proto/eventbooker/v1"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+func newEvent(e *models.Event) *eventbookerv1.Event {
+	return &eventbookerv1.Event{
+		Id:                          e.ID.String(),
+		Name:                        e.Name,
+		Date:                        timestamppb.New(e.Date),
+		TotalSeats:                  int32(e.TotalSeats),
+		ReservedSeats:               int32(e.ReservedSeats),
+		BookedSeats:                 int32(e.BookedSeats),
+		AvailableSeats:              int32(e.AvailableSeats()),
+		BookingLifetimeMinutes:      int32(e.BookingLifetime),
+		RequiresPaymentConfirmation: e.PaymentReq,
+		Inventory:                   newInventory(e.Inventory),
+		CreatedAt:                   timestamppb.New(e.CreatedAt),
+		CancelledAt:                 optionalTimestamp(e.CancelledAt),
+		Description:                 e.Description,
+		Category:                    string(e.Category),
+		Tags:                        e.Tags,
+		OrganizerContact:            e.OrganizerContact,
+		CoverImageUrl:               e.CoverImageURL,
+		EndDate:                     timestamppb.New(e.EndDate),
+		VenueId:                     optionalID(e.VenueID),
+		TimeZone:                    e.TimeZone,
+		SalesCloseAt:                timestamppb.New(e.SalesCloseAt),
+		SalesOpenAt:                 optionalTimestamp(e.SalesOpenAt),
+		SalesStatus:                 string(e.SalesStatus(time.Now())),
+	}
+}
+
+func newAvailability(e *models.Event) *eventbookerv1.EventAvailability {
+	return &eventbookerv1.EventAvailability{
+		EventId:        e.ID.String(),
+		TotalSeats:     int32(e.TotalSeats),
+		ReservedSeats:  int32(e.ReservedSeats),
+		BookedSeats:    int32(e.BookedSeats),
+		AvailableSeats: int32(e.AvailableSeats()),
+		Cancelled:      e.IsCancelled(),
+	}
+}
+
+func newUser(u *models.User) *eventbookerv1.User {
+	user := &eventbookerv1.User{
+		Id:        u.ID.String(),
+		Name:      u.Name,
+		Email:     u.Email,
+		Role:      newUserRole(u.Role),
+		CreatedAt: timestamppb.New(u.CreatedAt),
+	}
+	if u.TelegramID != nil {
+		user.TelegramId = *u.TelegramID
+	}
+
+	return user
+}
+
+func newBooking(b *models.Booking) *eventbookerv1.Booking {
+	booking := &eventbookerv1.Booking{
+		Id:        b.ID.String(),
+		EventId:   b.EventID.String(),
+		UserId:    b.UserID.String(),
+		Status:    newBookingStatus(b.Status),
+		CreatedAt: timestamppb.New(b.CreatedAt),
+		UpdatedAt: timestamppb.New(b.UpdatedAt),
+	}
+	if !b.Deadline.IsZero() {
+		booking.Deadline = timestamppb.New(b.Deadline)
+	}
+
+	return booking
+}
+
+func newInventory(inventory models.Inventory) eventbookerv1.Inventory {
+	switch inventory {
+	case models.InventoryCounter:
+		return eventbookerv1.Inventory_INVENTORY_COUNTER
+	case models.InventorySeats:
+		return eventbookerv1.Inventory_INVENTORY_SEATS
+	default:
+		return eventbookerv1.Inventory_INVENTORY_UNSPECIFIED
+	}
+}
+
+// inventoryFromProto returns the inventory name the HTTP API accepts, so that
+// the request goes through the same validation. Unknown values are passed on
+// as their number and rejected there.
+func inventoryFromProto(inventory eventbookerv1.Inventory) string {
+	switch inventory {
+	case eventbookerv1.Inventory_INVENTORY_UNSPECIFIED:
+		return ""
+	case eventbookerv1.Inventory_INVENTORY_COUNTER:
+		return string(models.InventoryCounter)
+	case eventbookerv1.Inventory_INVENTORY_SEATS:
+		return string(models.InventorySeats)
+	default:
+		return strconv.Itoa(int(inventory))
+	}
+}
+
+func newBookingStatus(s models.BookingStatus) eventbookerv1.BookingStatus {
+	switch s {
+	case models.BookingStatusReserved:
+		return eventbookerv1.BookingStatus_BOOKING_STATUS_RESERVED
+	case models.BookingStatusConfirmed:
+		return eventbookerv1.BookingStatus_BOOKING_STATUS_CONFIRMED
+	case models.BookingStatusCancelled:
+		return eventbookerv1.BookingStatus_BOOKING_STATUS_CANCELLED
+	default:
+		return eventbookerv1.BookingStatus_BOOKING_STATUS_UNSPECIFIED
+	}
+}
+
+func newUserRole(role models.UserRole) eventbookerv1.UserRole {
+	switch role {
+	case models.UserRoleUser:
+		return eventbookerv1.UserRole_USER_ROLE_USER
+	case models.UserRoleAdmin:
+		return eventbookerv1.UserRole_USER_ROLE_ADMIN
+	default:
+		return eventbookerv1.UserRole_USER_ROLE_UNSPECIFIED
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, apperrors.InvalidField(field, "%s is required", field)
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, apperrors.InvalidField(field, "invalid %s", field)
+	}
+
+	return id, nil
+}
+
+// page is a window over a list the service returns in full. Page tokens are
+// opaque to clients and encode the offset of the next page.
+type page struct {
+	offset int
+	size   int
+}
+
+func parsePage(size int32, token string) (page, error) {
+	errs := &apperrors.ValidationError{}
+
+	p := page{size: int(size)}
+	switch {
+	case size < 0:
+		errs.Add("page_size", "page size cannot be negative")
+	case size == 0:
+		p.size = defaultPageSize
+	case size > maxPageSize:
+		p.size = maxPageSize
+	}
+
+	if token != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err == nil {
+			p.offset, err = strconv.Atoi(string(raw))
+		}
+		if err != nil || p.offset < 0 {
+			errs.Add("page_token", "invalid page token")
+		}
+	}
+
+	return p, errs.Err()
+}
+
+// paginate returns the items of page p and the token of the next page, empty
+// when p is the last one.
+func paginate[T any](items []T, p page) ([]T, string) {
+	if p.offset >= len(items) {
+		return nil, ""
+	}
+
+	end := min(p.offset+p.size, len(items))
+	if end == len(items) {
+		return items[p.offset:end], ""
+	}
+
+	return items[p.offset:end], base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+}
@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net/http"
+	"time"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo attached to every
+// error; its reason is the catalogue code of the HTTP API.
+const ErrorDomain = "event-booker"
+
+// toStatus converts an error of the service layer into a gRPC status. Like
+// respondProblem it relies on the apperrors catalogue, so errors outside it
+// are reported as internal errors without their details.
+func toStatus(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	}
+
+	entry := apperrors.Describe(err)
+	message := entry.Err.Error()
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{Reason: entry.Code, Domain: ErrorDomain},
+	}
+
+	var validation *apperrors.ValidationError
+	if errors.As(err, &validation) {
+		message = validation.Error()
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(validation.Fields))
+		for i, f := range validation.Fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message}
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	if entry.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(time.Duration(entry.RetryAfter) * time.Second),
+		})
+	}
+
+	st, detailErr := status.New(codeOf(entry), message).WithDetails(details...)
+	if detailErr != nil {
+		return status.New(codeOf(entry), message)
+	}
+
+	return st
+}
+
+func codeOf(entry apperrors.Entry) codes.Code {
+	switch entry.Err {
+	case apperrors.UserAlreadyBookedThisEvent,
+		apperrors.EmailAlreadyExists,
+		apperrors.TelegramIDAlreadyExists:
+		return codes.AlreadyExists
+	case apperrors.NoAvailableSeats,
+		apperrors.BookingAlreadyCancelled,
+		apperrors.BookingNotReserved,
+		apperrors.BookingDeadlinePassed,
+		apperrors.EventDoesNotRequirePayment,
+		apperrors.EventExpired,
+		apperrors.EventCancelled,
+		apperrors.SalesNotOpen,
+		apperrors.SalesClosed,
+		apperrors.VenueUnavailable,
+		apperrors.VenueInUse,
+		apperrors.JobAlreadyRunning:
+		return codes.FailedPrecondition
+	case apperrors.TooManyActiveReservations:
+		return codes.ResourceExhausted
+	}
+
+	switch entry.Status {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusMethodNotAllowed:
+		return codes.Unimplemented
+	default:
+		return codes.Internal
+	}
+}
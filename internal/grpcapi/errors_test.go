@@ -0,0 +1,21 @@
+package grpcapi
+
+import (
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"google.golang.org/grpc/codes"
+	"testing"
+)
+
+func TestCatalogueCodes(t *testing.T) {
+	for _, entry := range apperrors.Catalogue() {
+		code := codeOf(entry)
+		if (code == codes.Internal) != (entry.Err == apperrors.Internal) {
+			t.Errorf("%s is mapped to %v", entry.Code, code)
+		}
+	}
+
+	st := toStatus(apperrors.ResourceBusy)
+	if st.Code() != codes.Unavailable || len(st.Details()) != 2 {
+		t.Errorf("resource_busy = %v with %d details, want Unavailable with ErrorInfo and RetryInfo", st.Code(), len(st.Details()))
+	}
+}
@@ -0,0 +1,143 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/gookit/slog"
+	eventbookerv1 "github.com/kstsm/wb-event-booker/api/proto/eventbooker/v1"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/kstsm/wb-event-booker/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"math"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// adminMethods cancel what other clients have booked, so they take the admin
+// token. The HTTP API has no counterpart for them. Creating an event is open
+// to every client, as POST /api/v1/events is.
+var adminMethods = []string{
+	eventbookerv1.EventBooker_CancelEvent_FullMethodName,
+	eventbookerv1.EventBooker_CancelBooking_FullMethodName,
+}
+
+// requireAdmin lets admin methods through only with the admin token as a
+// bearer token in the authorization metadata. Without a configured token
+// they are disabled.
+func requireAdmin(token string) interceptor {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		if !slices.Contains(adminMethods, method) {
+			return next(ctx)
+		}
+		if token == "" {
+			return apperrors.AdminDisabled
+		}
+
+		var got string
+		if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+			got = values[0]
+		}
+		bearer, ok := strings.CutPrefix(got, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			return apperrors.Unauthorized
+		}
+
+		return next(ctx)
+	}
+}
+
+// rateLimiter throttles registration and booking the way the HTTP API does,
+// with buckets of its own: by peer address, and bookings also by user and
+// by event.
+type rateLimiter struct {
+	ip    ratelimit.LimiterI
+	user  ratelimit.LimiterI
+	event ratelimit.LimiterI
+}
+
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	if !cfg.Enabled {
+		return &rateLimiter{}
+	}
+
+	limiter := func(perMinute, burst int) ratelimit.LimiterI {
+		if perMinute <= 0 {
+			return nil
+		}
+		return ratelimit.NewLimiter(perMinute, burst)
+	}
+
+	return &rateLimiter{
+		ip:    limiter(cfg.IPPerMinute, cfg.IPBurst),
+		user:  limiter(cfg.UserPerMinute, cfg.UserBurst),
+		event: limiter(cfg.EventPerMinute, cfg.EventBurst),
+	}
+}
+
+// unary runs inside the interceptor chain, where the request is known, so
+// its errors are logged and mapped like those of the handlers.
+func (l *rateLimiter) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	method := info.FullMethod
+
+	var err error
+	switch r := req.(type) {
+	case *eventbookerv1.CreateUserRequest:
+		err = l.allow(ctx, method, l.ip, metrics.RateLimitIP, peerIP(ctx))
+	case *eventbookerv1.BookEventRequest:
+		err = l.allow(ctx, method, l.ip, metrics.RateLimitIP, peerIP(ctx))
+		// Requests without an email are rejected by the handler anyway.
+		if err == nil && r.Email != "" {
+			err = l.allow(ctx, method, l.user, metrics.RateLimitUser, r.Email)
+		}
+		if err == nil {
+			err = l.allow(ctx, method, l.event, metrics.RateLimitEvent, r.EventId)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// allow takes a token for key and reports RateLimited with a retry-after
+// header, in seconds, when there is none.
+func (l *rateLimiter) allow(ctx context.Context, method string, limiter ratelimit.LimiterI, scope, key string) error {
+	if limiter == nil {
+		return nil
+	}
+
+	ok, wait := limiter.Allow(key)
+	if ok {
+		return nil
+	}
+
+	metrics.RateLimited.WithLabelValues(scope, method).Inc()
+	logging.AddFields(ctx, slog.M{"rate_limited": scope})
+
+	seconds := max(1, int(math.Ceil(wait.Seconds())))
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+
+	return apperrors.RateLimited
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
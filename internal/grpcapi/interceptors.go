@@ -0,0 +1,196 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gookit/slog"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// interceptor wraps a unary call or a whole stream. next runs the rest of the
+// chain with the context it is given.
+type interceptor func(ctx context.Context, method string, next func(context.Context) error) error
+
+// interceptors run in order, the first one outermost. Errors of the service
+// layer become gRPC statuses in withStatus, so the access log still sees the
+// original error.
+var interceptors = []interceptor{withMetrics, withTracing, withStatus, withLogging, withRecovery}
+
+func chain(ctx context.Context, list []interceptor, method string, call func(context.Context) error) error {
+	if len(list) == 0 {
+		return call(ctx)
+	}
+
+	return list[0](ctx, method, func(ctx context.Context) error {
+		return chain(ctx, list[1:], method, call)
+	})
+}
+
+func unaryInterceptor(list ...interceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var resp any
+		err := chain(ctx, list, info.FullMethod, func(ctx context.Context) error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		})
+
+		return resp, err
+	}
+}
+
+func streamInterceptor(list ...interceptor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return chain(ss.Context(), list, info.FullMethod, func(ctx context.Context) error {
+			return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		})
+	}
+}
+
+// serverStream hands the context built by the interceptors to the handler.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func withMetrics(ctx context.Context, method string, next func(context.Context) error) error {
+	start := time.Now()
+	err := next(ctx)
+
+	metrics.GRPCRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	metrics.GRPCDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+func withTracing(ctx context.Context, method string, next func(context.Context) error) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := tracing.Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	err := next(ctx)
+
+	code := status.Code(err)
+	span.SetAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.method", method),
+		attribute.Int("rpc.grpc.status_code", int(code)),
+	)
+	if isServerError(code) {
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+
+	return err
+}
+
+func withStatus(ctx context.Context, method string, next func(context.Context) error) error {
+	err := next(ctx)
+	if err == nil {
+		return nil
+	}
+
+	return toStatus(err).Err()
+}
+
+const requestIDKey = "x-request-id"
+
+func withLogging(ctx context.Context, method string, next func(context.Context) error) error {
+	start := time.Now()
+
+	var requestID string
+	if values := metadata.ValueFromIncomingContext(ctx, requestIDKey); len(values) > 0 {
+		requestID = values[0]
+	}
+	if !logging.ValidRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	fields := slog.M{logging.FieldRequestID: requestID}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		fields["trace_id"] = spanCtx.TraceID().String()
+	}
+	ctx = logging.WithFields(ctx, fields)
+
+	err := next(ctx)
+
+	code := toStatus(err).Code()
+	log := logging.FromContext(ctx).WithFields(slog.M{
+		"method":     method,
+		"code":       code.String(),
+		"latency_ms": time.Since(start).Milliseconds(),
+	})
+	if p, ok := peer.FromContext(ctx); ok {
+		log = log.WithField("remote_addr", p.Addr.String())
+	}
+
+	if isServerError(code) {
+		log.WithError(err).Error("gRPC request failed")
+		return err
+	}
+
+	log.Info("gRPC request")
+	return err
+}
+
+func withRecovery(ctx context.Context, method string, next func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in %s: %v: %w", method, r, apperrors.Internal)
+		}
+	}()
+
+	return next(ctx)
+}
+
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// metadataCarrier lets the OpenTelemetry propagator read trace context from
+// gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,371 @@
+package grpcapi
+
+import (
+	"context"
+	eventbookerv1 "github.com/kstsm/wb-event-booker/api/proto/eventbooker/v1"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"slices"
+	"time"
+)
+
+// Server implements the EventBooker gRPC service on top of the same service
+// layer as the HTTP handlers.
+type Server struct {
+	eventbookerv1.UnimplementedEventBookerServer
+
+	service       service.ServiceI
+	availability  availability.FeedI
+	watchInterval time.Duration
+}
+
+// NewServer returns a gRPC server with the EventBooker service registered and
+// the logging, tracing, metrics and error mapping interceptors installed.
+// Admin methods take adminToken, and registration and booking are rate
+// limited like in the HTTP API.
+func NewServer(
+	svc service.ServiceI,
+	availability availability.FeedI,
+	cfg config.GRPCConfig,
+	adminToken string,
+	limits config.RateLimitConfig,
+) *grpc.Server {
+	guarded := append(slices.Clone(interceptors), requireAdmin(adminToken))
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryInterceptor(guarded...), newRateLimiter(limits).unary),
+		grpc.ChainStreamInterceptor(streamInterceptor(guarded...)),
+	)
+	eventbookerv1.RegisterEventBookerServer(srv, &Server{
+		service:       svc,
+		availability:  availability,
+		watchInterval: time.Duration(cfg.WatchIntervalMs) * time.Millisecond,
+	})
+
+	return srv
+}
+
+func (s *Server) CreateEvent(ctx context.Context, req *eventbookerv1.CreateEventRequest) (*eventbookerv1.Event, error) {
+	if req.BookingLifetimeMinutes < 0 {
+		return nil, apperrors.InvalidField("booking_lifetime_minutes", "booking lifetime cannot be negative")
+	}
+
+	in := dto.CreateEventRequest{
+		Name:                   req.Name,
+		TotalSeats:             int(req.TotalSeats),
+		BookingLifetimeHours:   int(req.BookingLifetimeMinutes / 60),
+		BookingLifetimeMinutes: int(req.BookingLifetimeMinutes % 60),
+		PaymentReq:             req.RequiresPaymentConfirmation,
+		Inventory:              inventoryFromProto(req.Inventory),
+		Description:            req.Description,
+		Category:               req.Category,
+		Tags:                   req.Tags,
+		OrganizerContact:       req.OrganizerContact,
+		CoverImageURL:          req.CoverImageUrl,
+		TimeZone:               req.TimeZone,
+	}
+	if req.Date != nil {
+		in.Date = req.Date.AsTime().Format(time.RFC3339)
+	}
+	if req.EndDate != nil {
+		in.EndDate = req.EndDate.AsTime().Format(time.RFC3339)
+	}
+	if req.SalesOpenAt != nil {
+		in.SalesOpenAt = req.SalesOpenAt.AsTime().Format(time.RFC3339)
+	}
+	if req.SalesCloseAt != nil {
+		in.SalesCloseAt = req.SalesCloseAt.AsTime().Format(time.RFC3339)
+	}
+	if req.VenueId != "" {
+		venueID, err := parseID("venue_id", req.VenueId)
+		if err != nil {
+			return nil, err
+		}
+		in.VenueID = &venueID
+	}
+
+	if err := in.ValidateEvent(); err != nil {
+		return nil, err
+	}
+
+	event, err := s.service.CreateEvent(ctx, &in)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEvent(event), nil
+}
+
+func (s *Server) GetEvent(ctx context.Context, req *eventbookerv1.GetEventRequest) (*eventbookerv1.Event, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.service.GetEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEvent(event), nil
+}
+
+func (s *Server) ListEvents(ctx context.Context, req *eventbookerv1.ListEventsRequest) (*eventbookerv1.ListEventsResponse, error) {
+	p, err := parsePage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := dto.ListEventsRequest{Category: req.Category, Tags: req.Tags}
+	if err := filter.ValidateFilter(); err != nil {
+		return nil, err
+	}
+
+	events, err := s.service.ListEvents(ctx, filter.EventFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	events, next := paginate(events, p)
+	resp := &eventbookerv1.ListEventsResponse{
+		Events:        make([]*eventbookerv1.Event, len(events)),
+		NextPageToken: next,
+	}
+	for i, event := range events {
+		resp.Events[i] = newEvent(event)
+	}
+
+	return resp, nil
+}
+
+func (s *Server) CancelEvent(ctx context.Context, req *eventbookerv1.CancelEventRequest) (*eventbookerv1.CancelEventResponse, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.service.CancelEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventbookerv1.CancelEventResponse{CancelledBookings: cancelled}, nil
+}
+
+// WatchEvent sends the availability of the event right away and then every
+// time it changes. Changes come from the availability feed and, in case an
+// announcement was lost, the event is read again every watchInterval.
+func (s *Server) WatchEvent(req *eventbookerv1.WatchEventRequest, stream grpc.ServerStreamingServer[eventbookerv1.EventAvailability]) error {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	changes, unsubscribe := s.availability.Subscribe(id)
+	defer unsubscribe()
+
+	event, err := s.service.GetEventByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	var last *eventbookerv1.EventAvailability
+	for {
+		current := newAvailability(event)
+		if last == nil || !sameAvailability(last, current) {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		if event.IsCancelled() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if event, err = s.service.GetEventByID(ctx, id); err != nil {
+				return err
+			}
+		case changed, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			event = changed
+		}
+	}
+}
+
+func sameAvailability(a, b *eventbookerv1.EventAvailability) bool {
+	return a.TotalSeats == b.TotalSeats &&
+		a.ReservedSeats == b.ReservedSeats &&
+		a.BookedSeats == b.BookedSeats &&
+		a.Cancelled == b.Cancelled
+}
+
+func (s *Server) CreateUser(ctx context.Context, req *eventbookerv1.CreateUserRequest) (*eventbookerv1.User, error) {
+	in := dto.CreateUserRequest{
+		Name:  req.Name,
+		Email: req.Email,
+	}
+	if req.TelegramId != 0 {
+		in.TelegramID = &req.TelegramId
+	}
+
+	if err := in.ValidateUser(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.service.CreateUser(ctx, &in)
+	if err != nil {
+		return nil, err
+	}
+
+	return newUser(user), nil
+}
+
+func (s *Server) GetUser(ctx context.Context, req *eventbookerv1.GetUserRequest) (*eventbookerv1.User, error) {
+	var (
+		user *models.User
+		err  error
+	)
+
+	switch {
+	case req.Id != "":
+		id, parseErr := parseID("id", req.Id)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		user, err = s.service.GetUserByID(ctx, id)
+	case req.Email != "":
+		user, err = s.service.GetUserByEmail(ctx, req.Email)
+	default:
+		return nil, apperrors.InvalidField("id", "id or email is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newUser(user), nil
+}
+
+func (s *Server) BookEvent(ctx context.Context, req *eventbookerv1.BookEventRequest) (*eventbookerv1.BookEventResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email == "" {
+		return nil, apperrors.InvalidField("email", "email is required")
+	}
+
+	booking, err := s.service.BookEvent(ctx, eventID, &dto.BookEventRequest{Email: req.Email})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &eventbookerv1.BookEventResponse{BookingId: booking.BookingID.String()}
+	if booking.Deadline != nil {
+		deadline, err := time.Parse(time.RFC3339, *booking.Deadline)
+		if err != nil {
+			return nil, err
+		}
+		resp.Deadline = timestamppb.New(deadline)
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ConfirmBooking(ctx context.Context, req *eventbookerv1.ConfirmBookingRequest) (*eventbookerv1.ConfirmBookingResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	bookingID, err := parseID("booking_id", req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.ConfirmBooking(ctx, eventID, &dto.ConfirmBookingRequest{BookingID: bookingID}); err != nil {
+		return nil, err
+	}
+
+	return &eventbookerv1.ConfirmBookingResponse{}, nil
+}
+
+func (s *Server) CancelBooking(ctx context.Context, req *eventbookerv1.CancelBookingRequest) (*eventbookerv1.CancelBookingResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	bookingID, err := parseID("booking_id", req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.CancelBooking(ctx, eventID, bookingID); err != nil {
+		return nil, err
+	}
+
+	return &eventbookerv1.CancelBookingResponse{}, nil
+}
+
+func (s *Server) SubscribeToSales(ctx context.Context, req *eventbookerv1.SubscribeToSalesRequest) (*eventbookerv1.SubscribeToSalesResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.SubscribeRequest{Email: req.Email}
+	if err := in.ValidateSubscription(); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.SubscribeToSales(ctx, eventID, &in); err != nil {
+		return nil, err
+	}
+
+	return &eventbookerv1.SubscribeToSalesResponse{}, nil
+}
+
+func (s *Server) ListBookings(ctx context.Context, req *eventbookerv1.ListBookingsRequest) (*eventbookerv1.ListBookingsResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := parsePage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := s.service.ListBookingsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, next := paginate(bookings, p)
+	resp := &eventbookerv1.ListBookingsResponse{
+		Bookings:      make([]*eventbookerv1.Booking, len(bookings)),
+		NextPageToken: next,
+	}
+	for i, booking := range bookings {
+		resp.Bookings[i] = newBooking(booking)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,637 @@
+package grpcapi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	eventbookerv1 "github.com/kstsm/wb-event-booker/api/proto/eventbooker/v1"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/grpcapi"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+)
+
+const adminToken = "secret"
+
+// newClient connects as an admin to a server without rate limits.
+func newClient(t *testing.T) eventbookerv1.EventBookerClient {
+	t.Helper()
+	return newClientWith(t, adminToken, config.RateLimitConfig{}, adminToken)
+}
+
+// newClientWith connects with token, which may be empty, to a server
+// configured with serverToken and limits.
+func newClientWith(t *testing.T, serverToken string, limits config.RateLimitConfig, token string) eventbookerv1.EventBookerClient {
+	t.Helper()
+
+	hub := availability.NewHub()
+	svc := service.NewService(availability.WithPublishing(memory.NewRepository(), hub), config.BookingConfig{})
+	// A long resync interval leaves the updates to the hub.
+	srv := grpcapi.NewServer(svc, availability.NewFeed(hub, svc.GetEventByID), config.GRPCConfig{WatchIntervalMs: 60000}, serverToken, limits)
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	authorize := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(authorize),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return eventbookerv1.NewEventBookerClient(conn)
+}
+
+func createEvent(t *testing.T, c eventbookerv1.EventBookerClient, seats int32, paid bool) *eventbookerv1.Event {
+	t.Helper()
+
+	event, err := c.CreateEvent(context.Background(), &eventbookerv1.CreateEventRequest{
+		Name:                        "Concert",
+		Date:                        timestamppb.New(time.Now().Add(48 * time.Hour)),
+		TotalSeats:                  seats,
+		BookingLifetimeMinutes:      90,
+		RequiresPaymentConfirmation: paid,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	return event
+}
+
+func createUser(t *testing.T, c eventbookerv1.EventBookerClient, email string) *eventbookerv1.User {
+	t.Helper()
+
+	user, err := c.CreateUser(context.Background(), &eventbookerv1.CreateUserRequest{Name: "Anna", Email: email})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	return user
+}
+
+// errorDetails returns the gRPC code of err, the reason of its ErrorInfo and
+// the fields of its BadRequest detail.
+func errorDetails(t *testing.T, err error) (codes.Code, string, []string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("error %v is not a gRPC status", err)
+	}
+
+	var (
+		reason string
+		fields []string
+	)
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.Domain != grpcapi.ErrorDomain {
+				t.Errorf("ErrorInfo domain = %q", d.Domain)
+			}
+			reason = d.Reason
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+
+	return st.Code(), reason, fields
+}
+
+func TestBookingFlow(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	user := createUser(t, c, "anna@example.com")
+	if user.Role != eventbookerv1.UserRole_USER_ROLE_USER {
+		t.Errorf("role = %v", user.Role)
+	}
+
+	byEmail, err := c.GetUser(ctx, &eventbookerv1.GetUserRequest{Email: user.Email})
+	if err != nil {
+		t.Fatalf("GetUser by email: %v", err)
+	}
+	byID, err := c.GetUser(ctx, &eventbookerv1.GetUserRequest{Id: user.Id})
+	if err != nil {
+		t.Fatalf("GetUser by id: %v", err)
+	}
+	if byEmail.Id != user.Id || byID.Email != user.Email {
+		t.Errorf("GetUser = %v and %v, want %v", byEmail, byID, user)
+	}
+
+	event := createEvent(t, c, 2, true)
+	if event.BookingLifetimeMinutes != 90 || event.Inventory != eventbookerv1.Inventory_INVENTORY_COUNTER {
+		t.Errorf("CreateEvent = %v", event)
+	}
+
+	var header metadata.MD
+	booked, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: event.Id, Email: user.Email}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+	if !booked.Deadline.IsValid() || booked.Deadline.AsTime().Before(time.Now()) {
+		t.Errorf("deadline = %v", booked.Deadline)
+	}
+	if len(header.Get("x-request-id")) != 1 {
+		t.Errorf("x-request-id header = %v", header.Get("x-request-id"))
+	}
+
+	_, err = c.ConfirmBooking(ctx, &eventbookerv1.ConfirmBookingRequest{EventId: event.Id, BookingId: booked.BookingId})
+	if err != nil {
+		t.Fatalf("ConfirmBooking: %v", err)
+	}
+
+	event, err = c.GetEvent(ctx, &eventbookerv1.GetEventRequest{Id: event.Id})
+	if err != nil {
+		t.Fatalf("GetEvent: %v", err)
+	}
+	if event.BookedSeats != 1 || event.AvailableSeats != 1 {
+		t.Errorf("seats = booked %d, available %d", event.BookedSeats, event.AvailableSeats)
+	}
+
+	_, err = c.CancelBooking(ctx, &eventbookerv1.CancelBookingRequest{EventId: event.Id, BookingId: booked.BookingId})
+	if err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+	_, err = c.CancelBooking(ctx, &eventbookerv1.CancelBookingRequest{EventId: event.Id, BookingId: booked.BookingId})
+	if code, reason, _ := errorDetails(t, err); code != codes.FailedPrecondition || reason != "booking_already_cancelled" {
+		t.Errorf("second CancelBooking = %v %q", code, reason)
+	}
+
+	bookings, err := c.ListBookings(ctx, &eventbookerv1.ListBookingsRequest{EventId: event.Id})
+	if err != nil {
+		t.Fatalf("ListBookings: %v", err)
+	}
+	if len(bookings.Bookings) != 1 || bookings.Bookings[0].Status != eventbookerv1.BookingStatus_BOOKING_STATUS_CANCELLED {
+		t.Errorf("ListBookings = %v", bookings)
+	}
+
+	event, err = c.GetEvent(ctx, &eventbookerv1.GetEventRequest{Id: event.Id})
+	if err != nil {
+		t.Fatalf("GetEvent: %v", err)
+	}
+	if event.BookedSeats != 0 || event.AvailableSeats != 2 {
+		t.Errorf("seats after cancellation = booked %d, available %d", event.BookedSeats, event.AvailableSeats)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	c := newClient(t)
+
+	tests := []struct {
+		id   string
+		keep bool
+	}{
+		{"checkout-7f3a", true},
+		{"checkout 7f3a", false},
+		{"<script>", false},
+	}
+	for _, tt := range tests {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", tt.id)
+		var header metadata.MD
+		if _, err := c.ListEvents(ctx, &eventbookerv1.ListEventsRequest{}, grpc.Header(&header)); err != nil {
+			t.Fatalf("ListEvents: %v", err)
+		}
+		got := header.Get("x-request-id")
+		if len(got) != 1 || (got[0] == tt.id) != tt.keep {
+			t.Errorf("request ID %q: x-request-id header = %v, kept = %v", tt.id, got, tt.keep)
+		}
+	}
+}
+
+func TestListEventsPaging(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	for range 5 {
+		createEvent(t, c, 1, false)
+	}
+
+	var (
+		sizes []int
+		seen  = map[string]bool{}
+		token string
+	)
+	for {
+		resp, err := c.ListEvents(ctx, &eventbookerv1.ListEventsRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("ListEvents: %v", err)
+		}
+		sizes = append(sizes, len(resp.Events))
+		for _, event := range resp.Events {
+			seen[event.Id] = true
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+	}
+
+	if !slices.Equal(sizes, []int{2, 2, 1}) || len(seen) != 5 {
+		t.Errorf("page sizes = %v, distinct events = %d", sizes, len(seen))
+	}
+
+	_, err := c.ListEvents(ctx, &eventbookerv1.ListEventsRequest{PageToken: "not a token"})
+	if code, reason, fields := errorDetails(t, err); code != codes.InvalidArgument ||
+		reason != "validation_failed" || !slices.Equal(fields, []string{"page_token"}) {
+		t.Errorf("invalid token = %v %q %v", code, reason, fields)
+	}
+}
+
+func TestListEventsFilter(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	createEvent(t, c, 1, false)
+	tagged, err := c.CreateEvent(ctx, &eventbookerv1.CreateEventRequest{
+		Name:        "Go meetup",
+		Date:        timestamppb.New(time.Now().Add(48 * time.Hour)),
+		TotalSeats:  10,
+		Description: "# Agenda",
+		Category:    "meetup",
+		Tags:        []string{"Go", "backend"},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if tagged.Category != "meetup" || !slices.Equal(tagged.Tags, []string{"go", "backend"}) || tagged.Description != "# Agenda" {
+		t.Fatalf("CreateEvent = %+v", tagged)
+	}
+
+	resp, err := c.ListEvents(ctx, &eventbookerv1.ListEventsRequest{Category: "meetup", Tags: []string{"go"}})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(resp.Events) != 1 || resp.Events[0].Id != tagged.Id {
+		t.Errorf("filtered events = %v", resp.Events)
+	}
+
+	_, err = c.ListEvents(ctx, &eventbookerv1.ListEventsRequest{Category: "party"})
+	if code, reason, fields := errorDetails(t, err); code != codes.InvalidArgument ||
+		reason != "validation_failed" || !slices.Equal(fields, []string{"category"}) {
+		t.Errorf("unknown category = %v %q %v", code, reason, fields)
+	}
+}
+
+func TestWatchEvent(t *testing.T) {
+	c := newClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event := createEvent(t, c, 2, false)
+	user := createUser(t, c, "anna@example.com")
+
+	stream, err := c.WatchEvent(ctx, &eventbookerv1.WatchEventRequest{Id: event.Id})
+	if err != nil {
+		t.Fatalf("WatchEvent: %v", err)
+	}
+
+	recv := func() *eventbookerv1.EventAvailability {
+		t.Helper()
+		msg, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		return msg
+	}
+
+	if got := recv(); got.AvailableSeats != 2 || got.EventId != event.Id {
+		t.Fatalf("initial availability = %v", got)
+	}
+
+	if _, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: event.Id, Email: user.Email}); err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+	if got := recv(); got.AvailableSeats != 1 || got.BookedSeats != 1 {
+		t.Fatalf("availability after booking = %v", got)
+	}
+
+	if _, err := c.CancelEvent(ctx, &eventbookerv1.CancelEventRequest{Id: event.Id}); err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+	if got := recv(); !got.Cancelled {
+		t.Fatalf("availability after cancellation = %v", got)
+	}
+
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("stream after cancellation: error = %v, want EOF", err)
+	}
+
+	stream, err = c.WatchEvent(ctx, &eventbookerv1.WatchEventRequest{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("WatchEvent: %v", err)
+	}
+	_, err = stream.Recv()
+	if code, reason, _ := errorDetails(t, err); code != codes.NotFound || reason != "event_not_found" {
+		t.Errorf("watching an unknown event = %v %q", code, reason)
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	free := createEvent(t, c, 2, false)
+	soldOut := createEvent(t, c, 1, false)
+	anna := createUser(t, c, "anna@example.com")
+	ivan := createUser(t, c, "ivan@example.com")
+
+	upcoming, err := c.CreateEvent(ctx, &eventbookerv1.CreateEventRequest{
+		Name:        "Premiere",
+		Date:        timestamppb.New(time.Now().Add(48 * time.Hour)),
+		TotalSeats:  5,
+		SalesOpenAt: timestamppb.New(time.Now().Add(time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if upcoming.SalesStatus != "upcoming" || upcoming.SalesOpenAt == nil {
+		t.Fatalf("sales status %q, open at %v", upcoming.SalesStatus, upcoming.SalesOpenAt)
+	}
+	if _, err := c.SubscribeToSales(ctx, &eventbookerv1.SubscribeToSalesRequest{EventId: upcoming.Id, Email: ivan.Email}); err != nil {
+		t.Fatalf("SubscribeToSales: %v", err)
+	}
+
+	booked, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: free.Id, Email: anna.Email})
+	if err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+	if _, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: soldOut.Id, Email: ivan.Email}); err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		call   func() error
+		code   codes.Code
+		reason string
+		fields []string
+	}{
+		{
+			name: "unknown event",
+			call: func() error {
+				_, err := c.GetEvent(ctx, &eventbookerv1.GetEventRequest{Id: uuid.NewString()})
+				return err
+			},
+			code: codes.NotFound, reason: "event_not_found",
+		},
+		{
+			name: "malformed id",
+			call: func() error {
+				_, err := c.GetEvent(ctx, &eventbookerv1.GetEventRequest{Id: "42"})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "validation_failed", fields: []string{"id"},
+		},
+		{
+			name: "invalid user",
+			call: func() error {
+				_, err := c.CreateUser(ctx, &eventbookerv1.CreateUserRequest{Name: "1", Email: "bad"})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "validation_failed", fields: []string{"name", "email"},
+		},
+		{
+			name: "invalid event",
+			call: func() error {
+				_, err := c.CreateEvent(ctx, &eventbookerv1.CreateEventRequest{Name: "Past", TotalSeats: 1})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "validation_failed", fields: []string{"date"},
+		},
+		{
+			name: "unknown venue",
+			call: func() error {
+				_, err := c.CreateEvent(ctx, &eventbookerv1.CreateEventRequest{
+					Name:    "Lecture",
+					Date:    timestamppb.New(time.Now().Add(48 * time.Hour)),
+					VenueId: uuid.NewString(),
+				})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "validation_failed", fields: []string{"venue_id"},
+		},
+		{
+			name: "malformed venue id",
+			call: func() error {
+				_, err := c.CreateEvent(ctx, &eventbookerv1.CreateEventRequest{
+					Name:       "Lecture",
+					Date:       timestamppb.New(time.Now().Add(48 * time.Hour)),
+					TotalSeats: 1,
+					VenueId:    "hall",
+				})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "validation_failed", fields: []string{"venue_id"},
+		},
+		{
+			name: "sales not open",
+			call: func() error {
+				_, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: upcoming.Id, Email: anna.Email})
+				return err
+			},
+			code: codes.FailedPrecondition, reason: "sales_not_open",
+		},
+		{
+			name: "invalid subscription",
+			call: func() error {
+				_, err := c.SubscribeToSales(ctx, &eventbookerv1.SubscribeToSalesRequest{EventId: upcoming.Id, Email: "anna@"})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "validation_failed", fields: []string{"email"},
+		},
+		{
+			name: "duplicate email",
+			call: func() error {
+				_, err := c.CreateUser(ctx, &eventbookerv1.CreateUserRequest{Name: "Anna", Email: anna.Email})
+				return err
+			},
+			code: codes.AlreadyExists, reason: "email_already_exists",
+		},
+		{
+			name: "already booked",
+			call: func() error {
+				_, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: free.Id, Email: anna.Email})
+				return err
+			},
+			code: codes.AlreadyExists, reason: "already_booked",
+		},
+		{
+			name: "sold out",
+			call: func() error {
+				_, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: soldOut.Id, Email: anna.Email})
+				return err
+			},
+			code: codes.FailedPrecondition, reason: "no_available_seats",
+		},
+		{
+			name: "payment not required",
+			call: func() error {
+				_, err := c.ConfirmBooking(ctx, &eventbookerv1.ConfirmBookingRequest{EventId: free.Id, BookingId: booked.BookingId})
+				return err
+			},
+			code: codes.FailedPrecondition, reason: "payment_not_required",
+		},
+		{
+			name: "user without id or email",
+			call: func() error {
+				_, err := c.GetUser(ctx, &eventbookerv1.GetUserRequest{})
+				return err
+			},
+			code: codes.InvalidArgument, reason: "validation_failed", fields: []string{"id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, reason, fields := errorDetails(t, tt.call())
+			if code != tt.code || reason != tt.reason || !slices.Equal(fields, tt.fields) {
+				t.Errorf("error = %v %q %v, want %v %q %v", code, reason, fields, tt.code, tt.reason, tt.fields)
+			}
+		})
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	ctx := context.Background()
+	request := &eventbookerv1.CreateEventRequest{
+		Name:       "Concert",
+		Date:       timestamppb.New(time.Now().Add(48 * time.Hour)),
+		TotalSeats: 5,
+	}
+
+	tests := []struct {
+		name        string
+		serverToken string
+		token       string
+		code        codes.Code
+		reason      string
+	}{
+		{"no token", adminToken, "", codes.Unauthenticated, "unauthorized"},
+		{"wrong token", adminToken, "guess", codes.Unauthenticated, "unauthorized"},
+		{"admin disabled", "", adminToken, codes.PermissionDenied, "admin_disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClientWith(t, tt.serverToken, config.RateLimitConfig{}, tt.token)
+
+			calls := map[string]func() error{
+				"CancelEvent": func() error {
+					_, err := c.CancelEvent(ctx, &eventbookerv1.CancelEventRequest{Id: uuid.NewString()})
+					return err
+				},
+				"CancelBooking": func() error {
+					_, err := c.CancelBooking(ctx, &eventbookerv1.CancelBookingRequest{BookingId: uuid.NewString()})
+					return err
+				},
+			}
+			for method, call := range calls {
+				code, reason, _ := errorDetails(t, call())
+				if code != tt.code || reason != tt.reason {
+					t.Errorf("%s: error = %v %q, want %v %q", method, code, reason, tt.code, tt.reason)
+				}
+			}
+
+			// Creating events, reading and booking stay open to every client,
+			// as in the HTTP API.
+			if _, err := c.CreateEvent(ctx, request); err != nil {
+				t.Errorf("CreateEvent: %v", err)
+			}
+			if _, err := c.ListEvents(ctx, &eventbookerv1.ListEventsRequest{}); err != nil {
+				t.Errorf("ListEvents: %v", err)
+			}
+			createUser(t, c, "anna@example.com")
+		})
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	ctx := context.Background()
+
+	expectLimited := func(t *testing.T, err error, header metadata.MD) {
+		t.Helper()
+		if code, reason, _ := errorDetails(t, err); code != codes.ResourceExhausted || reason != "rate_limited" {
+			t.Fatalf("error = %v %q, want ResourceExhausted rate_limited", code, reason)
+		}
+		if values := header.Get("retry-after"); len(values) != 1 || values[0] == "0" {
+			t.Fatalf("retry-after = %v, want a positive number of seconds", values)
+		}
+	}
+
+	t.Run("PerPeer", func(t *testing.T) {
+		c := newClientWith(t, adminToken, config.RateLimitConfig{Enabled: true, IPPerMinute: 1, IPBurst: 1}, adminToken)
+
+		createUser(t, c, "anna@example.com")
+		var header metadata.MD
+		_, err := c.CreateUser(ctx, &eventbookerv1.CreateUserRequest{Name: "Boris", Email: "boris@example.com"}, grpc.Header(&header))
+		expectLimited(t, err, header)
+
+		// Other methods are not limited.
+		createEvent(t, c, 5, true)
+	})
+
+	t.Run("PerUser", func(t *testing.T) {
+		c := newClientWith(t, adminToken, config.RateLimitConfig{Enabled: true, UserPerMinute: 1, UserBurst: 1}, adminToken)
+		first, second := createEvent(t, c, 5, true), createEvent(t, c, 5, true)
+		anna := createUser(t, c, "anna@example.com")
+		boris := createUser(t, c, "boris@example.com")
+
+		if _, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: first.Id, Email: anna.Email}); err != nil {
+			t.Fatalf("BookEvent: %v", err)
+		}
+		var header metadata.MD
+		_, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: second.Id, Email: anna.Email}, grpc.Header(&header))
+		expectLimited(t, err, header)
+
+		if _, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: second.Id, Email: boris.Email}); err != nil {
+			t.Fatalf("BookEvent of another user: %v", err)
+		}
+	})
+
+	t.Run("PerEvent", func(t *testing.T) {
+		c := newClientWith(t, adminToken, config.RateLimitConfig{Enabled: true, EventPerMinute: 1, EventBurst: 1}, adminToken)
+		hot, other := createEvent(t, c, 5, true), createEvent(t, c, 5, true)
+		anna := createUser(t, c, "anna@example.com")
+		boris := createUser(t, c, "boris@example.com")
+
+		if _, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: hot.Id, Email: anna.Email}); err != nil {
+			t.Fatalf("BookEvent: %v", err)
+		}
+		var header metadata.MD
+		_, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: hot.Id, Email: boris.Email}, grpc.Header(&header))
+		expectLimited(t, err, header)
+
+		if _, err := c.BookEvent(ctx, &eventbookerv1.BookEventRequest{EventId: other.Id, Email: boris.Email}); err != nil {
+			t.Fatalf("BookEvent of another event: %v", err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"net/http"
+	"strings"
+)
+
+// requireAdmin lets through only requests carrying the admin token as a
+// bearer token. Without a configured token the admin API is disabled.
+func (h *Handler) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.adminToken == "" {
+			respondProblem(w, r, apperrors.AdminDisabled)
+			return
+		}
+
+		if !h.isAdmin(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			respondProblem(w, r, apperrors.Unauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *Handler) isAdmin(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && h.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1
+}
@@ -2,47 +2,36 @@ package handler
 
 import (
 	"encoding/json"
-	"errors"
+	"github.com/gookit/slog"
 	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/logging"
 	"net/http"
 )
 
 func (h *Handler) bookEventHandler(w http.ResponseWriter, r *http.Request) {
 	eventID, err := parseUUIDParam(r, "id")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	var req dto.BookEventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, apperrors.InvalidRequestBody)
 		return
 	}
 
 	if req.Email == "" {
-		respondError(w, http.StatusBadRequest, "email is required")
+		respondProblem(w, r, apperrors.InvalidField("email", "email is required"))
 		return
 	}
 
+	logging.AddFields(r.Context(), slog.M{"event_id": eventID})
+
 	booking, err := h.service.BookEvent(r.Context(), eventID, &req)
 	if err != nil {
-		switch {
-		case errors.Is(err, apperrors.EventNotFound):
-			respondError(w, http.StatusNotFound, "event not found")
-		case errors.Is(err, apperrors.UserNotFound):
-			respondError(w, http.StatusNotFound, "user not found")
-		case errors.Is(err, apperrors.NoAvailableSeats):
-			respondError(w, http.StatusConflict, "no available seats")
-		case errors.Is(err, apperrors.UserAlreadyBookedThisEvent):
-			respondError(w, http.StatusConflict, "user already has a booking for this event")
-		case errors.Is(err, apperrors.EventExpired):
-			respondError(w, http.StatusBadRequest, "event has expired")
-		default:
-			respondError(w, http.StatusInternalServerError, "internal server error")
-		}
-
+		respondProblem(w, r, err)
 		return
 	}
 
@@ -56,33 +45,21 @@ func (h *Handler) bookEventHandler(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ConfirmBookingHandler(w http.ResponseWriter, r *http.Request) {
 	eventID, err := parseUUIDParam(r, "id")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	var req dto.ConfirmBookingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, apperrors.InvalidRequestBody)
 		return
 	}
 
+	logging.AddFields(r.Context(), slog.M{"event_id": eventID, "booking_id": req.BookingID})
+
 	err = h.service.ConfirmBooking(r.Context(), eventID, &req)
 	if err != nil {
-		switch {
-		case errors.Is(err, apperrors.BookingNotFound):
-			respondError(w, http.StatusNotFound, "booking not found")
-		case errors.Is(err, apperrors.BookingNotReserved):
-			respondError(w, http.StatusBadRequest, "booking is not in reserved status")
-		case errors.Is(err, apperrors.BookingDeadlinePassed):
-			respondError(w, http.StatusBadRequest, "booking deadline has passed")
-		case errors.Is(err, apperrors.EventDoesNotRequirePayment):
-			respondError(w, http.StatusBadRequest, "event does not require payment confirmation")
-		case errors.Is(err, apperrors.EventExpired):
-			respondError(w, http.StatusBadRequest, "event has expired")
-		default:
-			respondError(w, http.StatusInternalServerError, "internal server error")
-		}
-
+		respondProblem(w, r, err)
 		return
 	}
 
@@ -91,20 +68,50 @@ func (h *Handler) ConfirmBookingHandler(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+func (h *Handler) subscribeToSalesHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	var req dto.SubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, apperrors.InvalidRequestBody)
+		return
+	}
+
+	if err := req.ValidateSubscription(); err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	logging.AddFields(r.Context(), slog.M{"event_id": eventID})
+
+	if err := h.service.SubscribeToSales(r.Context(), eventID, &req); err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.SubscribeResponse{
+		Message: "you will be notified when ticket sales open",
+	})
+}
+
 func (h *Handler) listBookingsByEventHandler(w http.ResponseWriter, r *http.Request) {
 	eventID, err := parseUUIDParam(r, "id")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	bookings, err := h.service.ListBookingsByEventID(r.Context(), eventID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, dto.ListBookingsResponse{
-		Bookings: bookings,
+		Bookings: dto.NewBookings(bookings),
 	})
 }
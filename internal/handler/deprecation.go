@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// deprecation announces that a route prefix is going away: Deprecation
+// (RFC 9745) carries the date it was deprecated, Sunset (RFC 8594) the date
+// after which it may stop responding, and Link points to the same route in
+// the version that replaces it.
+type deprecation struct {
+	deprecatedAt time.Time
+	sunsetAt     time.Time
+}
+
+// newDeprecation takes YYYY-MM-DD dates; a date that is empty or invalid
+// leaves its header out.
+func newDeprecation(deprecatedAt, sunsetAt string) deprecation {
+	var d deprecation
+	d.deprecatedAt, _ = time.Parse(time.DateOnly, deprecatedAt)
+	d.sunsetAt, _ = time.Parse(time.DateOnly, sunsetAt)
+	return d
+}
+
+func (d deprecation) middleware(prefix, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			if !d.deprecatedAt.IsZero() {
+				header.Set("Deprecation", "@"+strconv.FormatInt(d.deprecatedAt.Unix(), 10))
+			}
+			if !d.sunsetAt.IsZero() {
+				header.Set("Sunset", d.sunsetAt.UTC().Format(http.TimeFormat))
+			}
+			header.Add("Link", "<"+successor+strings.TrimPrefix(r.URL.Path, prefix)+`>; rel="successor-version"`)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
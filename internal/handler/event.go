@@ -2,7 +2,6 @@ package handler
 
 import (
 	"encoding/json"
-	"errors"
 	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/dto"
 	"net/http"
@@ -12,23 +11,23 @@ func (h *Handler) createEventHandler(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateEventRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
+		respondProblem(w, r, apperrors.InvalidRequestBody)
 		return
 	}
 
 	if err := req.ValidateEvent(); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	event, err := h.service.CreateEvent(r.Context(), &req)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusCreated, dto.CreateEventResponse{
-		Event:   event,
+		Event:   dto.NewEvent(event),
 		Message: "event created successfully",
 	})
 }
@@ -36,34 +35,38 @@ func (h *Handler) createEventHandler(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getEventByIDHandler(w http.ResponseWriter, r *http.Request) {
 	eventID, err := parseUUIDParam(r, "id")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	event, err := h.service.GetEventByID(r.Context(), eventID)
 	if err != nil {
-		switch {
-		case errors.Is(err, apperrors.EventNotFound):
-			respondError(w, http.StatusNotFound, "event not found")
-		default:
-			respondError(w, http.StatusInternalServerError, err.Error())
-		}
+		respondProblem(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, dto.GetEventResponse{
-		Event: event,
+		Event: dto.NewEvent(event),
 	})
 }
 
 func (h *Handler) listEventsHandler(w http.ResponseWriter, r *http.Request) {
-	events, err := h.service.ListEvents(r.Context())
+	req := dto.ListEventsRequest{
+		Category: r.URL.Query().Get("category"),
+		Tags:     r.URL.Query()["tag"],
+	}
+	if err := req.ValidateFilter(); err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	events, err := h.service.ListEvents(r.Context(), req.EventFilter())
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, dto.ListEventsResponse{
-		Events: events,
+		Events: dto.NewEvents(events),
 	})
 }
@@ -2,46 +2,110 @@ package handler
 
 import (
 	"github.com/go-chi/chi/v5"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/health"
+	"github.com/kstsm/wb-event-booker/internal/scheduler"
 	"github.com/kstsm/wb-event-booker/internal/service"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	swaggerFiles "github.com/swaggo/files/v2"
 	"net/http"
+	"time"
 )
 
 type HandlerI interface {
 	NewRouter() http.Handler
+	NewProbeRouter() http.Handler
 }
 
 type Handler struct {
-	service service.ServiceI
+	service      service.ServiceI
+	scheduler    scheduler.SchedulerI
+	health       health.CheckerI
+	availability availability.FeedI
+
+	idempotencyTTL time.Duration
+	limits         rateLimits
+	legacyAPI      deprecation
+	sseHeartbeat   time.Duration
+	adminToken     string
 }
 
-func NewHandler(service service.ServiceI) HandlerI {
-	return &Handler{
-		service: service,
+func NewHandler(
+	service service.ServiceI,
+	scheduler scheduler.SchedulerI,
+	health health.CheckerI,
+	availability availability.FeedI,
+	cfg config.Server,
+	limits config.RateLimitConfig,
+) HandlerI {
+	handler := &Handler{
+		service:        service,
+		scheduler:      scheduler,
+		health:         health,
+		availability:   availability,
+		idempotencyTTL: time.Duration(cfg.IdempotencyTTLHours) * time.Hour,
+		limits:         newRateLimits(limits),
+		legacyAPI:      newDeprecation(cfg.LegacyAPIDeprecatedAt, cfg.LegacyAPISunsetAt),
+		sseHeartbeat:   time.Duration(cfg.SSEHeartbeatSeconds) * time.Second,
+		adminToken:     cfg.AdminToken,
+	}
+	if handler.sseHeartbeat <= 0 {
+		handler.sseHeartbeat = defaultSSEHeartbeat
 	}
+
+	return handler
 }
 
 func (h *Handler) NewRouter() http.Handler {
 	r := chi.NewRouter()
+	r.Use(metricsMiddleware)
+	r.Use(tracingMiddleware)
+	r.Use(loggingMiddleware)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		respondProblem(w, r, apperrors.RouteNotFound)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		respondProblem(w, r, apperrors.MethodNotAllowed)
+	})
+
+	r.Handle("/metrics", promhttp.Handler())
+	r.Get("/healthz", h.healthzHandler)
+	r.Get("/readyz", h.readyzHandler(false))
 
 	r.Get("/", h.serveHTML("index.html"))
 	r.Get("/register", h.serveHTML("register.html"))
 	r.Get("/admin", h.serveHTML("admin.html"))
 	r.Get("/event", h.serveHTML("event.html"))
+	r.Get("/docs", h.serveHTML("docs.html"))
+	// Swagger UI is served from the binary rather than a CDN, so the docs
+	// page runs exactly the pinned version.
+	r.Method(http.MethodGet, "/docs/assets/*", http.StripPrefix("/docs/assets/", http.FileServerFS(swaggerFiles.FS)))
+
+	r.Route("/api/v1", h.routesV1)
 
+	// The unversioned prefix predates /api/v1 and serves the same routes
+	// during the deprecation window.
 	r.Route("/api", func(r chi.Router) {
-		r.Post("/events", h.createEventHandler)
-		r.Post("/events/{id}/book", h.bookEventHandler)
-		r.Post("/events/{id}/confirm", h.ConfirmBookingHandler)
-		r.Get("/events/{id}", h.getEventByIDHandler)
-
-		r.Get("/events", h.listEventsHandler)
-		r.Get("/events/{id}/bookings", h.listBookingsByEventHandler)
-		r.Post("/users", h.createUserHandler)
+		r.Use(h.legacyAPI.middleware("/api", "/api/v1"))
+		h.routesV1(r)
 	})
 
 	return r
 }
 
+func (h *Handler) NewProbeRouter() http.Handler {
+	r := chi.NewRouter()
+	r.Use(metricsMiddleware)
+
+	r.Handle("/metrics", promhttp.Handler())
+	r.Get("/healthz", h.healthzHandler)
+	r.Get("/readyz", h.readyzHandler(true))
+
+	return r
+}
+
 func (h *Handler) serveHTML(filename string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "./web/"+filename)
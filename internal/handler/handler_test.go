@@ -0,0 +1,1354 @@
+package handler_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/availability"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/handler"
+	"github.com/kstsm/wb-event-booker/internal/health"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/scheduler"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// stubRepo is the in-memory repository with the ability to make the booking
+// transactions fail or stall, for situations that cannot be produced through
+// the API.
+type stubRepo struct {
+	repository.RepositoryI
+
+	mu          sync.Mutex
+	bookErr     error
+	confirmErr  error
+	completeErr error
+	entered     chan struct{}
+	gate        chan struct{}
+}
+
+func (r *stubRepo) fail(bookErr, confirmErr error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bookErr, r.confirmErr = bookErr, confirmErr
+}
+
+func (r *stubRepo) failCompletion(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completeErr = err
+}
+
+// hold stalls the next booking until release is called. The returned channel
+// is closed once the booking has started.
+func (r *stubRepo) hold() (entered <-chan struct{}, release func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entered, r.gate = make(chan struct{}), make(chan struct{})
+
+	gate := r.gate
+	return r.entered, func() { close(gate) }
+}
+
+func (r *stubRepo) BookEventWithTransaction(ctx context.Context, eventID, userID uuid.UUID, maxReservations int) (*models.Booking, error) {
+	r.mu.Lock()
+	err := r.bookErr
+	entered, gate := r.entered, r.gate
+	r.entered, r.gate = nil, nil
+	r.mu.Unlock()
+	if gate != nil {
+		close(entered)
+		<-gate
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.RepositoryI.BookEventWithTransaction(ctx, eventID, userID, maxReservations)
+}
+
+func (r *stubRepo) CompleteIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) error {
+	r.mu.Lock()
+	err := r.completeErr
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return r.RepositoryI.CompleteIdempotencyKey(ctx, key)
+}
+
+func (r *stubRepo) ConfirmBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	r.mu.Lock()
+	err := r.confirmErr
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return r.RepositoryI.ConfirmBookingWithTransaction(ctx, bookingID)
+}
+
+const adminToken = "secret"
+
+var serverConfig = config.Server{
+	IdempotencyTTLHours:   24,
+	LegacyAPIDeprecatedAt: "2026-10-19",
+	LegacyAPISunsetAt:     "2027-04-30",
+	AdminToken:            adminToken,
+}
+
+type testEnv struct {
+	t       *testing.T
+	repo    *stubRepo
+	svc     service.ServiceI
+	router  http.Handler
+	release chan struct{}
+}
+
+func newEnv(t *testing.T) *testEnv {
+	t.Helper()
+	return newEnvWithLimits(t, config.BookingConfig{}, config.RateLimitConfig{})
+}
+
+func newEnvWithLimits(t *testing.T, booking config.BookingConfig, limits config.RateLimitConfig) *testEnv {
+	t.Helper()
+
+	repo := &stubRepo{RepositoryI: memory.NewRepository()}
+	hub := availability.NewHub()
+	svc := service.NewService(availability.WithPublishing(repo, hub), booking)
+
+	release := make(chan struct{})
+	sched := scheduler.NewScheduler(repo)
+	jobs := []scheduler.Job{
+		{Name: "noop", Spec: "@every 1h", Task: func(ctx context.Context) error { return nil }},
+		{Name: "blocking", Spec: "0 3 * * *", Task: func(ctx context.Context) error {
+			<-release
+			return nil
+		}},
+	}
+	for _, job := range jobs {
+		if err := sched.Register(job); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		close(release)
+		sched.Stop()
+	})
+
+	checker := health.NewChecker()
+	checker.Register("repository", func(ctx context.Context) error {
+		_, err := repo.GetBookingStats(ctx)
+		return err
+	})
+
+	return &testEnv{
+		t:       t,
+		repo:    repo,
+		svc:     svc,
+		router:  handler.NewHandler(svc, sched, checker, availability.NewFeed(hub, svc.GetEventByID), serverConfig, limits).NewRouter(),
+		release: release,
+	}
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (e *testEnv) do(method, path string, body any) response {
+	e.t.Helper()
+	return e.doWithKey(method, path, body, "")
+}
+
+// doWithKey sends the request with an Idempotency-Key header unless key is
+// empty.
+func (e *testEnv) doWithKey(method, path string, body any, key string) response {
+	e.t.Helper()
+
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			e.t.Fatalf("marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	e.router.ServeHTTP(rec, req)
+
+	return response{status: rec.Code, header: rec.Header(), body: rec.Body.Bytes()}
+}
+
+func (e *testEnv) expect(method, path string, body any, status int) response {
+	e.t.Helper()
+
+	resp := e.do(method, path, body)
+	if resp.status != status {
+		e.t.Fatalf("%s %s: status = %d, want %d, body: %s", method, path, resp.status, status, resp.body)
+	}
+	return resp
+}
+
+// expectError checks that the response is a problem document with the given
+// status and detail and returns it for further checks.
+func (e *testEnv) expectError(method, path string, body any, status int, message string) models.Problem {
+	e.t.Helper()
+
+	resp := e.expect(method, path, body, status)
+	if ct := resp.header.Get("Content-Type"); ct != "application/problem+json" {
+		e.t.Fatalf("%s %s: Content-Type = %q, want application/problem+json", method, path, ct)
+	}
+
+	var got models.Problem
+	if err := json.Unmarshal(resp.body, &got); err != nil {
+		e.t.Fatalf("%s %s: decode problem %q: %v", method, path, resp.body, err)
+	}
+	if got.Detail != message {
+		e.t.Fatalf("%s %s: detail = %q, want %q", method, path, got.Detail, message)
+	}
+	if got.Status != status || got.Code == "" || got.Type != "urn:event-booker:problem:"+got.Code {
+		e.t.Fatalf("%s %s: malformed problem %s", method, path, resp.body)
+	}
+
+	return got
+}
+
+func (e *testEnv) decode(resp response, v any) {
+	e.t.Helper()
+
+	if err := json.Unmarshal(resp.body, v); err != nil {
+		e.t.Fatalf("decode %s: %v", resp.body, err)
+	}
+}
+
+func (e *testEnv) createEvent(seats int, paid bool) uuid.UUID {
+	e.t.Helper()
+
+	resp := e.expect(http.MethodPost, "/api/v1/events", eventRequest(seats, paid), http.StatusCreated)
+
+	var created struct {
+		Event models.Event `json:"event"`
+	}
+	e.decode(resp, &created)
+
+	return created.Event.ID
+}
+
+func (e *testEnv) createUser(email string) {
+	e.t.Helper()
+
+	e.expect(http.MethodPost, "/api/v1/users", map[string]any{"name": "Anna", "email": email}, http.StatusCreated)
+}
+
+func (e *testEnv) book(eventID uuid.UUID, email string) uuid.UUID {
+	e.t.Helper()
+
+	resp := e.expect(http.MethodPost, bookPath(eventID), map[string]any{"email": email}, http.StatusCreated)
+
+	var booked struct {
+		BookingID uuid.UUID `json:"booking_id"`
+	}
+	e.decode(resp, &booked)
+
+	return booked.BookingID
+}
+
+func eventRequest(seats int, paid bool) map[string]any {
+	return map[string]any{
+		"name":                          "Go meetup",
+		"date":                          time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339),
+		"total_seats":                   seats,
+		"booking_lifetime_minutes":      30,
+		"requires_payment_confirmation": paid,
+	}
+}
+
+func bookPath(eventID uuid.UUID) string {
+	return fmt.Sprintf("/api/v1/events/%s/book", eventID)
+}
+
+func confirmPath(eventID uuid.UUID) string {
+	return fmt.Sprintf("/api/v1/events/%s/confirm", eventID)
+}
+
+func TestCreateEventValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(req map[string]any)
+		message string
+	}{
+		{"missing name", func(req map[string]any) { req["name"] = "" }, "event name is required"},
+		{"no seats", func(req map[string]any) { req["total_seats"] = 0 }, "total number of seats must be greater than or equal to 1"},
+		{"negative hours", func(req map[string]any) { req["booking_lifetime_hours"] = -1 }, "booking lifetime hours cannot be negative"},
+		{"minutes out of range", func(req map[string]any) { req["booking_lifetime_minutes"] = 60 }, "booking lifetime minutes must be between 0 and 59"},
+		{"paid without lifetime", func(req map[string]any) { req["booking_lifetime_minutes"] = 0 }, "minimum booking lifetime is 1 minutes"},
+		{"bad date", func(req map[string]any) { req["date"] = "tomorrow" }, "invalid date format"},
+		{"past date", func(req map[string]any) { req["date"] = "2020-01-01T10:00:00Z" }, "event date cannot be in the past"},
+		{"unknown inventory", func(req map[string]any) { req["inventory"] = "tickets" }, "inventory must be either counter or seats"},
+		{"bad end date", func(req map[string]any) { req["end_date"] = "later" }, "invalid end date format"},
+		{"end before start", func(req map[string]any) { req["end_date"] = req["date"] }, "event end date must be after its start date"},
+		{"unknown time zone", func(req map[string]any) { req["time_zone"] = "MSK" }, `unknown time zone "MSK"`},
+		{"bad sales open", func(req map[string]any) { req["sales_open_at"] = "soon" }, "invalid sales open time format"},
+		{"bad sales close", func(req map[string]any) { req["sales_close_at"] = "soon" }, "invalid sales close time format"},
+		{"past sales close", func(req map[string]any) { req["sales_close_at"] = "2020-01-01T10:00:00Z" },
+			"sales close time cannot be in the past"},
+		{"sales close after start", func(req map[string]any) {
+			req["sales_close_at"] = time.Now().Add(72 * time.Hour).UTC().Format(time.RFC3339)
+		}, "sales cannot close after the event starts"},
+		{"too many seat rows", func(req map[string]any) {
+			req["inventory"] = "seats"
+			req["total_seats"] = 100001
+		}, "events with seat inventory can have at most 100000 seats"},
+		{"long name", func(req map[string]any) { req["name"] = strings.Repeat("я", 65) }, "event name must be at most 64 characters"},
+		{"long description", func(req map[string]any) { req["description"] = strings.Repeat("a", 10001) }, "description must be at most 10000 characters"},
+		{"unknown category", func(req map[string]any) { req["category"] = "party" },
+			"category must be one of concert, conference, meetup, workshop, theatre, sport, exhibition, other"},
+		{"too many tags", func(req map[string]any) {
+			req["tags"] = []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}
+		}, "an event can have at most 10 tags"},
+		{"bad tag", func(req map[string]any) { req["tags"] = []string{"go", "open source"} },
+			`tag "open source" must start with a letter or digit and contain only letters, digits and +#.-`},
+		{"long tag", func(req map[string]any) { req["tags"] = []string{strings.Repeat("t", 33)} },
+			fmt.Sprintf("tag %q must be at most 32 characters", strings.Repeat("t", 33))},
+		{"bad contact", func(req map[string]any) { req["organizer_contact"] = "call me" },
+			"organizer contact must be an email address or a phone number"},
+		{"relative cover url", func(req map[string]any) { req["cover_image_url"] = "/cover.png" },
+			"cover image url must be an absolute http or https url"},
+		{"cover url scheme", func(req map[string]any) { req["cover_image_url"] = "javascript:alert(1)" },
+			"cover image url must be an absolute http or https url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := eventRequest(10, true)
+			tt.modify(req)
+			newEnv(t).expectError(http.MethodPost, "/api/v1/events", req, http.StatusBadRequest, tt.message)
+		})
+	}
+
+	newEnv(t).expectError(http.MethodPost, "/api/v1/events", "{", http.StatusBadRequest, "invalid request body")
+}
+
+func TestListEventsFilter(t *testing.T) {
+	env := newEnv(t)
+
+	create := func(category string, tags ...string) uuid.UUID {
+		req := eventRequest(10, false)
+		req["category"] = category
+		req["tags"] = tags
+
+		var created dto.CreateEventResponse
+		env.decode(env.expect(http.MethodPost, "/api/v1/events", req, http.StatusCreated), &created)
+		return created.Event.ID
+	}
+	concert := create("concert", "jazz", "live")
+	meetup := create("meetup", "Go")
+	workshop := create("workshop", "go", "live")
+
+	tests := []struct {
+		query string
+		want  []uuid.UUID
+	}{
+		{"", []uuid.UUID{concert, meetup, workshop}},
+		{"?category=meetup", []uuid.UUID{meetup}},
+		{"?tag=GO", []uuid.UUID{meetup, workshop}},
+		{"?tag=go&tag=live", []uuid.UUID{workshop}},
+		{"?category=concert&tag=go", nil},
+	}
+	for _, tt := range tests {
+		var list dto.ListEventsResponse
+		env.decode(env.expect(http.MethodGet, "/api/v1/events"+tt.query, nil, http.StatusOK), &list)
+
+		got := make(map[uuid.UUID]bool)
+		for _, event := range list.Events {
+			got[event.ID] = true
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("GET /api/v1/events%s returned %d events, want %d", tt.query, len(got), len(tt.want))
+		}
+		for _, id := range tt.want {
+			if !got[id] {
+				t.Errorf("GET /api/v1/events%s is missing event %s", tt.query, id)
+			}
+		}
+	}
+
+	env.expectError(http.MethodGet, "/api/v1/events?category=party", nil, http.StatusBadRequest,
+		"category must be one of concert, conference, meetup, workshop, theatre, sport, exhibition, other")
+	env.expectError(http.MethodGet, "/api/v1/events?tag=a%20b", nil, http.StatusBadRequest,
+		`tag "a b" must start with a letter or digit and contain only letters, digits and +#.-`)
+}
+
+func venueRequest() map[string]any {
+	return map[string]any{
+		"name":             "Main hall",
+		"address":          "Moscow, Tverskaya 1",
+		"latitude":         55.757,
+		"longitude":        37.615,
+		"time_zone":        "Europe/Moscow",
+		"default_capacity": 200,
+	}
+}
+
+func TestVenues(t *testing.T) {
+	env := newEnv(t)
+
+	var created dto.VenueResponse
+	env.decode(env.expect(http.MethodPost, "/api/v1/venues", venueRequest(), http.StatusCreated), &created)
+	venuePath := "/api/v1/venues/" + created.Venue.ID.String()
+
+	update := venueRequest()
+	update["name"] = "Small hall"
+	update["default_capacity"] = 40
+	var updated dto.VenueResponse
+	env.decode(env.expect(http.MethodPut, venuePath, update, http.StatusOK), &updated)
+	if updated.Venue.Name != "Small hall" || updated.Venue.DefaultCapacity != 40 || !updated.Venue.CreatedAt.Equal(created.Venue.CreatedAt) {
+		t.Fatalf("updated venue = %+v", updated.Venue)
+	}
+
+	var list dto.ListVenuesResponse
+	env.decode(env.expect(http.MethodGet, "/api/v1/venues", nil, http.StatusOK), &list)
+	if len(list.Venues) != 1 || list.Venues[0].ID != created.Venue.ID {
+		t.Fatalf("venues = %+v", list.Venues)
+	}
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	event := eventRequest(0, false)
+	event["venue_id"] = created.Venue.ID
+	event["date"] = start.Format(time.RFC3339)
+	var createdEvent dto.CreateEventResponse
+	env.decode(env.expect(http.MethodPost, "/api/v1/events", event, http.StatusCreated), &createdEvent)
+	if createdEvent.Event.TotalSeats != 40 || !createdEvent.Event.EndDate.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("event at venue = %+v", createdEvent.Event)
+	}
+
+	overlapping := eventRequest(10, false)
+	overlapping["venue_id"] = created.Venue.ID
+	overlapping["date"] = start.Add(time.Hour).Format(time.RFC3339)
+	env.expectError(http.MethodPost, "/api/v1/events", overlapping, http.StatusConflict,
+		"venue is already taken by another event at that time")
+
+	following := eventRequest(10, false)
+	following["venue_id"] = created.Venue.ID
+	following["date"] = start.Add(2 * time.Hour).Format(time.RFC3339)
+	env.expect(http.MethodPost, "/api/v1/events", following, http.StatusCreated)
+
+	env.expectError(http.MethodDelete, venuePath, nil, http.StatusConflict, "venue is used by events")
+
+	unused := env.expect(http.MethodPost, "/api/v1/venues", venueRequest(), http.StatusCreated)
+	env.decode(unused, &created)
+	env.expect(http.MethodDelete, "/api/v1/venues/"+created.Venue.ID.String(), nil, http.StatusNoContent)
+	env.expectError(http.MethodGet, "/api/v1/venues/"+created.Venue.ID.String(), nil, http.StatusNotFound, "venue not found")
+	env.expectError(http.MethodPut, "/api/v1/venues/"+uuid.NewString(), venueRequest(), http.StatusNotFound, "venue not found")
+
+	missing := eventRequest(10, false)
+	missing["venue_id"] = uuid.New()
+	problem := env.expectError(http.MethodPost, "/api/v1/events", missing, http.StatusBadRequest, "venue does not exist")
+	if problem.Code != "validation_failed" || len(problem.Errors) != 1 || problem.Errors[0].Field != "venue_id" {
+		t.Fatalf("unknown venue: code = %q, errors = %v, want validation_failed on venue_id", problem.Code, problem.Errors)
+	}
+}
+
+func TestVenueValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(req map[string]any)
+		message string
+	}{
+		{"missing name", func(req map[string]any) { req["name"] = " " }, "venue name is required"},
+		{"missing address", func(req map[string]any) { delete(req, "address") }, "venue address is required"},
+		{"missing latitude", func(req map[string]any) { delete(req, "latitude") }, "latitude is required"},
+		{"latitude out of range", func(req map[string]any) { req["latitude"] = 91 }, "latitude must be between -90 and 90"},
+		{"longitude out of range", func(req map[string]any) { req["longitude"] = -181 }, "longitude must be between -180 and 180"},
+		{"missing time zone", func(req map[string]any) { req["time_zone"] = "" }, "time zone is required"},
+		{"unknown time zone", func(req map[string]any) { req["time_zone"] = "Mars/Olympus" }, `unknown time zone "Mars/Olympus"`},
+		{"local time zone", func(req map[string]any) { req["time_zone"] = "Local" }, `unknown time zone "Local"`},
+		{"no capacity", func(req map[string]any) { req["default_capacity"] = 0 }, "default capacity must be greater than or equal to 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := venueRequest()
+			tt.modify(req)
+			newEnv(t).expectError(http.MethodPost, "/api/v1/venues", req, http.StatusBadRequest, tt.message)
+		})
+	}
+}
+
+func TestValidationReportsAllFields(t *testing.T) {
+	env := newEnv(t)
+
+	problem := env.expectError(http.MethodPost, "/api/v1/events", map[string]any{
+		"total_seats":              0,
+		"booking_lifetime_minutes": 90,
+		"date":                     "tomorrow",
+	}, http.StatusBadRequest, "event name is required; total number of seats must be greater than or equal to 1; "+
+		"booking lifetime minutes must be between 0 and 59; invalid date format")
+
+	var fields []string
+	for _, f := range problem.Errors {
+		fields = append(fields, f.Field)
+	}
+	if got, want := strings.Join(fields, ","), "name,total_seats,booking_lifetime_minutes,date"; got != want {
+		t.Fatalf("fields = %s, want %s", got, want)
+	}
+	if problem.Code != "validation_failed" || problem.Instance != "/api/v1/events" {
+		t.Fatalf("code = %q, instance = %q", problem.Code, problem.Instance)
+	}
+}
+
+func TestProblemResponses(t *testing.T) {
+	env := newEnv(t)
+
+	problem := env.expectError(http.MethodGet, "/api/v1/nope", nil, http.StatusNotFound, "route not found")
+	if problem.Code != "route_not_found" {
+		t.Fatalf("code = %q, want route_not_found", problem.Code)
+	}
+	env.expectError(http.MethodDelete, "/api/v1/events", nil, http.StatusMethodNotAllowed, "method not allowed")
+
+	eventID := env.createEvent(5, true)
+	env.createUser("anna@example.com")
+	env.createUser("boris@example.com")
+	bookingID := env.book(eventID, "anna@example.com")
+
+	env.repo.fail(apperrors.ResourceBusy, errors.New("pq: connection refused"))
+	busy := env.do(http.MethodPost, bookPath(eventID), map[string]any{"email": "boris@example.com"})
+	if busy.status != http.StatusServiceUnavailable || busy.header.Get("Retry-After") != "1" {
+		t.Fatalf("busy: status = %d, Retry-After = %q", busy.status, busy.header.Get("Retry-After"))
+	}
+
+	// The message of an unexpected error stays in the logs.
+	problem = env.expectError(http.MethodPost, confirmPath(eventID), map[string]any{"booking_id": bookingID},
+		http.StatusInternalServerError, "internal server error")
+	if problem.Code != "internal_error" {
+		t.Fatalf("code = %q, want internal_error", problem.Code)
+	}
+}
+
+func TestLegacyAPIAlias(t *testing.T) {
+	env := newEnv(t)
+	eventID := env.createEvent(5, false)
+
+	current := env.expect(http.MethodGet, "/api/v1/events/"+eventID.String(), nil, http.StatusOK)
+	if current.header.Get("Deprecation") != "" || current.header.Get("Sunset") != "" {
+		t.Fatalf("v1 response is marked deprecated: %v", current.header)
+	}
+
+	legacy := env.expect(http.MethodGet, "/api/events/"+eventID.String(), nil, http.StatusOK)
+	if !bytes.Equal(legacy.body, current.body) {
+		t.Errorf("legacy body = %s, want %s", legacy.body, current.body)
+	}
+
+	deprecatedAt := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC).Unix()
+	if got, want := legacy.header.Get("Deprecation"), fmt.Sprintf("@%d", deprecatedAt); got != want {
+		t.Errorf("Deprecation = %q, want %q", got, want)
+	}
+	if got := legacy.header.Get("Sunset"); got != "Fri, 30 Apr 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got, want := legacy.header.Get("Link"), fmt.Sprintf(`</api/v1/events/%s>; rel="successor-version"`, eventID); got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+
+	env.createUser("anna@example.com")
+	resp := env.do(http.MethodPost, "/api/events/"+eventID.String()+"/book", map[string]any{"email": "anna@example.com"})
+	if resp.status != http.StatusCreated || resp.header.Get("Deprecation") == "" {
+		t.Fatalf("legacy booking: status %d, headers %v", resp.status, resp.header)
+	}
+
+	problem := env.expectError(http.MethodGet, "/api/events/"+uuid.NewString(), nil, http.StatusNotFound, "event not found")
+	if !strings.HasPrefix(problem.Instance, "/api/events/") {
+		t.Errorf("instance = %q", problem.Instance)
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	env := newEnv(t)
+	srv := httptest.NewServer(env.router)
+	t.Cleanup(srv.Close)
+
+	eventID := env.createEvent(2, true)
+	env.createUser("anna@example.com")
+
+	env.expectError(http.MethodGet, "/api/v1/events/"+uuid.NewString()+"/stream", nil, http.StatusNotFound, "event not found")
+
+	resp, stream := openStream(t, srv, eventID, "")
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	initial := readAvailability(t, stream)
+	if initial.data.Total != 2 || initial.data.Available != 2 || initial.data.EventID != eventID {
+		t.Fatalf("initial availability = %+v", initial.data)
+	}
+
+	bookingID := env.book(eventID, "anna@example.com")
+	if got := readAvailability(t, stream); got.data.Reserved != 1 || got.data.Available != 1 {
+		t.Fatalf("availability after booking = %+v", got.data)
+	}
+
+	env.expect(http.MethodPost, confirmPath(eventID), map[string]any{"booking_id": bookingID}, http.StatusOK)
+	confirmed := readAvailability(t, stream)
+	if confirmed.data.Reserved != 0 || confirmed.data.Booked != 1 {
+		t.Fatalf("availability after confirmation = %+v", confirmed.data)
+	}
+	resp.Body.Close()
+
+	// A client that reconnects with an up-to-date Last-Event-ID only hears
+	// about the next change.
+	_, stream = openStream(t, srv, eventID, confirmed.id)
+	if _, err := env.svc.CancelEvent(context.Background(), eventID); err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+	cancelled := readAvailability(t, stream)
+	if !cancelled.data.Cancelled || cancelled.data.Available != 2 {
+		t.Fatalf("availability after cancellation = %+v", cancelled.data)
+	}
+	if line, err := stream.ReadString('\n'); err == nil {
+		t.Fatalf("stream continues after cancellation: %q", line)
+	}
+
+	resp, _ = openStream(t, srv, eventID, cancelled.id)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("reconnection after cancellation: status = %d, want 204", resp.StatusCode)
+	}
+}
+
+type availabilityMessage struct {
+	id   string
+	data dto.EventAvailability
+}
+
+func openStream(t *testing.T, srv *httptest.Server, eventID uuid.UUID, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/events/"+eventID.String()+"/stream", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp, bufio.NewReader(resp.Body)
+}
+
+// readAvailability returns the next availability message of the stream,
+// skipping the retry field and heartbeat comments.
+func readAvailability(t *testing.T, stream *bufio.Reader) availabilityMessage {
+	t.Helper()
+
+	var (
+		msg   availabilityMessage
+		event string
+		data  string
+	)
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		if line == "" {
+			if data == "" {
+				continue
+			}
+			break
+		}
+
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			msg.id = value
+		case "event":
+			event = value
+		case "data":
+			data = value
+		}
+	}
+
+	if event != "availability" || msg.id == "" {
+		t.Fatalf("message event = %q, id = %q", event, msg.id)
+	}
+	if err := json.Unmarshal([]byte(data), &msg.data); err != nil {
+		t.Fatalf("decode %q: %v", data, err)
+	}
+
+	return msg
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    map[string]any
+		message string
+	}{
+		{"missing name", map[string]any{"email": "anna@example.com"}, "user name is required"},
+		{"digits in name", map[string]any{"name": "Anna1", "email": "anna@example.com"}, "name must contain only letters"},
+		{"missing email", map[string]any{"name": "Anna"}, "user email is required"},
+		{"bad email", map[string]any{"name": "Anna", "email": "anna@"}, "invalid email format"},
+		{"telegram id too small", map[string]any{"name": "Anna", "email": "anna@example.com", "telegram_id": 42}, "telegram id must be >= 1000000"},
+		{"telegram id too large", map[string]any{"name": "Anna", "email": "anna@example.com", "telegram_id": int64(99999999999)}, "telegram id must be <= 9999999999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newEnv(t).expectError(http.MethodPost, "/api/v1/users", tt.body, http.StatusBadRequest, tt.message)
+		})
+	}
+
+	env := newEnv(t)
+	env.createUser("anna@example.com")
+	env.expectError(http.MethodPost, "/api/v1/users", map[string]any{"name": "Anna", "email": "anna@example.com"},
+		http.StatusConflict, apperrors.EmailAlreadyExists.Error())
+}
+
+func TestBookEventErrors(t *testing.T) {
+	env := newEnv(t)
+
+	event := env.createEvent(1, true)
+	env.createUser("anna@example.com")
+	env.createUser("boris@example.com")
+
+	env.expectError(http.MethodPost, "/api/v1/events/not-a-uuid/book", map[string]any{"email": "anna@example.com"},
+		http.StatusBadRequest, "invalid id")
+	env.expectError(http.MethodPost, bookPath(event), "{", http.StatusBadRequest, "invalid request body")
+	env.expectError(http.MethodPost, bookPath(event), map[string]any{}, http.StatusBadRequest, "email is required")
+	env.expectError(http.MethodPost, bookPath(uuid.New()), map[string]any{"email": "anna@example.com"},
+		http.StatusNotFound, "event not found")
+	env.expectError(http.MethodPost, bookPath(event), map[string]any{"email": "nobody@example.com"},
+		http.StatusNotFound, "user not found")
+
+	env.book(event, "anna@example.com")
+	env.expectError(http.MethodPost, bookPath(event), map[string]any{"email": "boris@example.com"},
+		http.StatusConflict, "no available seats")
+
+	roomy := env.createEvent(5, true)
+	env.book(roomy, "anna@example.com")
+	env.expectError(http.MethodPost, bookPath(roomy), map[string]any{"email": "anna@example.com"},
+		http.StatusConflict, "user already has a booking for this event")
+
+	past := &models.Event{
+		ID:              uuid.New(),
+		Name:            "Yesterday",
+		Date:            time.Now().Add(-24 * time.Hour),
+		TotalSeats:      5,
+		BookingLifetime: 30,
+		PaymentReq:      true,
+	}
+	if err := env.repo.CreateEvent(context.Background(), past); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	env.expectError(http.MethodPost, bookPath(past.ID), map[string]any{"email": "boris@example.com"},
+		http.StatusBadRequest, "event has expired")
+
+	cancelled := env.createEvent(5, true)
+	if _, err := env.svc.CancelEvent(context.Background(), cancelled); err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+	env.expectError(http.MethodPost, bookPath(cancelled), map[string]any{"email": "boris@example.com"},
+		http.StatusBadRequest, "event has been cancelled")
+
+	env.repo.fail(fmt.Errorf("%w: lock timeout", apperrors.ResourceBusy), nil)
+	resp := env.do(http.MethodPost, bookPath(roomy), map[string]any{"email": "boris@example.com"})
+	if resp.status != http.StatusServiceUnavailable || resp.header.Get("Retry-After") != "1" {
+		t.Fatalf("busy event: status = %d, Retry-After = %q", resp.status, resp.header.Get("Retry-After"))
+	}
+
+	env.repo.fail(errors.New("connection reset"), nil)
+	env.expectError(http.MethodPost, bookPath(roomy), map[string]any{"email": "boris@example.com"},
+		http.StatusInternalServerError, "internal server error")
+}
+
+func TestConfirmBookingErrors(t *testing.T) {
+	env := newEnv(t)
+
+	paid := env.createEvent(5, true)
+	free := env.createEvent(5, false)
+	env.createUser("anna@example.com")
+	env.createUser("boris@example.com")
+
+	confirm := func(bookingID uuid.UUID) map[string]any {
+		return map[string]any{"booking_id": bookingID}
+	}
+
+	env.expectError(http.MethodPost, "/api/v1/events/not-a-uuid/confirm", confirm(uuid.New()), http.StatusBadRequest, "invalid id")
+	env.expectError(http.MethodPost, confirmPath(paid), `{"booking_id": "nope"}`, http.StatusBadRequest, "invalid request body")
+	env.expectError(http.MethodPost, confirmPath(paid), confirm(uuid.New()), http.StatusNotFound, "booking not found")
+
+	reservation := env.book(paid, "anna@example.com")
+	env.expectError(http.MethodPost, confirmPath(free), confirm(reservation), http.StatusNotFound, "booking not found")
+
+	env.expect(http.MethodPost, confirmPath(paid), confirm(reservation), http.StatusOK)
+	env.expectError(http.MethodPost, confirmPath(paid), confirm(reservation), http.StatusBadRequest, "booking is not in reserved status")
+
+	freeBooking := env.book(free, "anna@example.com")
+	env.expectError(http.MethodPost, confirmPath(free), confirm(freeBooking), http.StatusBadRequest,
+		"event does not require payment confirmation")
+
+	other := env.book(paid, "boris@example.com")
+
+	env.repo.fail(nil, apperrors.BookingDeadlinePassed)
+	env.expectError(http.MethodPost, confirmPath(paid), confirm(other), http.StatusBadRequest, "booking deadline has passed")
+
+	env.repo.fail(nil, apperrors.EventExpired)
+	env.expectError(http.MethodPost, confirmPath(paid), confirm(other), http.StatusBadRequest, "event has expired")
+
+	env.repo.fail(nil, fmt.Errorf("%w: deadlock", apperrors.ResourceBusy))
+	env.expectError(http.MethodPost, confirmPath(paid), confirm(other), http.StatusServiceUnavailable, "resource is busy, try again later")
+
+	env.repo.fail(nil, errors.New("connection reset"))
+	env.expectError(http.MethodPost, confirmPath(paid), confirm(other), http.StatusInternalServerError, "internal server error")
+
+	env.repo.fail(nil, nil)
+	if _, err := env.svc.CancelEvent(context.Background(), paid); err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+	env.expectError(http.MethodPost, confirmPath(paid), confirm(other), http.StatusBadRequest, "event has been cancelled")
+}
+
+func TestSalesWindow(t *testing.T) {
+	env := newEnv(t)
+	env.createUser("anna@example.com")
+
+	req := eventRequest(5, false)
+	req["date"] = "2099-06-01T18:00:00Z"
+	req["sales_open_at"] = "2099-06-01T18:00:00Z"
+	env.expectError(http.MethodPost, "/api/v1/events", req, http.StatusBadRequest,
+		"sales must open before they close at 2099-06-01T18:00:00Z")
+
+	req["sales_open_at"] = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	req["sales_close_at"] = "2099-06-01T12:00:00Z"
+	var created dto.CreateEventResponse
+	env.decode(env.expect(http.MethodPost, "/api/v1/events", req, http.StatusCreated), &created)
+	event := created.Event
+	if event.SalesStatus != "upcoming" || event.SalesCountdownSeconds == nil ||
+		*event.SalesCountdownSeconds < 3500 || *event.SalesCountdownSeconds > 3600 {
+		t.Fatalf("sales status %q with countdown %v, want upcoming in an hour", event.SalesStatus, event.SalesCountdownSeconds)
+	}
+	if !event.SalesCloseAt.Equal(time.Date(2099, 6, 1, 12, 0, 0, 0, time.UTC)) || !event.BookingClosesAt.Equal(event.SalesCloseAt) {
+		t.Fatalf("sales close at %v, booking_closes_at %v", event.SalesCloseAt, event.BookingClosesAt)
+	}
+
+	env.expectError(http.MethodPost, bookPath(event.ID), map[string]any{"email": "anna@example.com"},
+		http.StatusBadRequest, "ticket sales have not opened yet")
+
+	subscribe := fmt.Sprintf("/api/v1/events/%s/subscribe", event.ID)
+	env.expect(http.MethodPost, subscribe, map[string]any{"email": "anna@example.com"}, http.StatusOK)
+	env.expect(http.MethodPost, subscribe, map[string]any{"email": "anna@example.com"}, http.StatusOK)
+	env.expectError(http.MethodPost, subscribe, map[string]any{"email": "anna@"}, http.StatusBadRequest, "invalid email format")
+	env.expectError(http.MethodPost, subscribe, map[string]any{"email": "nobody@example.com"}, http.StatusNotFound, "user not found")
+	env.expectError(http.MethodPost, fmt.Sprintf("/api/v1/events/%s/subscribe", uuid.New()),
+		map[string]any{"email": "anna@example.com"}, http.StatusNotFound, "event not found")
+
+	closed := &models.Event{
+		ID:           uuid.New(),
+		Name:         "Sold out",
+		Date:         time.Now().Add(24 * time.Hour),
+		EndDate:      time.Now().Add(26 * time.Hour),
+		SalesCloseAt: time.Now().Add(-time.Minute),
+		TotalSeats:   5,
+		CreatedAt:    time.Now().Add(-time.Hour),
+	}
+	if err := env.repo.CreateEvent(context.Background(), closed); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	env.expectError(http.MethodPost, bookPath(closed.ID), map[string]any{"email": "anna@example.com"},
+		http.StatusBadRequest, "ticket sales are closed")
+
+	var got dto.GetEventResponse
+	env.decode(env.expect(http.MethodGet, "/api/v1/events/"+closed.ID.String(), nil, http.StatusOK), &got)
+	if got.Event.SalesStatus != "closed" || got.Event.SalesCountdownSeconds != nil {
+		t.Fatalf("sales status %q with countdown %v, want closed without one", got.Event.SalesStatus, got.Event.SalesCountdownSeconds)
+	}
+}
+
+func TestConcurrentBookingsDoNotOversell(t *testing.T) {
+	const (
+		seats   = 7
+		clients = 40
+	)
+
+	env := newEnv(t)
+	event := env.createEvent(seats, true)
+	for i := range clients {
+		env.createUser(fmt.Sprintf("client%d@example.com", i))
+	}
+
+	statuses := make(chan int, clients)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(email string) {
+			defer wg.Done()
+
+			body, _ := json.Marshal(map[string]any{"email": email})
+			req := httptest.NewRequest(http.MethodPost, bookPath(event), bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			env.router.ServeHTTP(rec, req)
+			statuses <- rec.Code
+		}(fmt.Sprintf("client%d@example.com", i))
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	if counts[http.StatusCreated] != seats || counts[http.StatusConflict] != clients-seats {
+		t.Fatalf("statuses = %v, want %d created and %d conflicts", counts, seats, clients-seats)
+	}
+
+	var got struct {
+		Event models.Event `json:"event"`
+	}
+	env.decode(env.expect(http.MethodGet, "/api/v1/events/"+event.String(), nil, http.StatusOK), &got)
+	if got.Event.ReservedSeats != seats || got.Event.BookedSeats != 0 {
+		t.Fatalf("seats reserved=%d booked=%d, want %d and 0", got.Event.ReservedSeats, got.Event.BookedSeats, seats)
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	env := newEnv(t)
+	eventID := env.createEvent(10, true)
+	env.createUser("anna@example.com")
+	env.createUser("boris@example.com")
+	anna := map[string]any{"email": "anna@example.com"}
+
+	first := env.doWithKey(http.MethodPost, bookPath(eventID), anna, "book-anna")
+	if first.status != http.StatusCreated || first.header.Get("Idempotent-Replayed") != "" {
+		t.Fatalf("first request: status = %d, replayed = %q", first.status, first.header.Get("Idempotent-Replayed"))
+	}
+
+	retry := env.doWithKey(http.MethodPost, bookPath(eventID), anna, "book-anna")
+	if retry.status != http.StatusCreated || retry.header.Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("retry: status = %d, replayed = %q", retry.status, retry.header.Get("Idempotent-Replayed"))
+	}
+	if !bytes.Equal(retry.body, first.body) || retry.header.Get("Content-Type") != "application/json" {
+		t.Fatalf("retry replayed %s (%s), want %s", retry.body, retry.header.Get("Content-Type"), first.body)
+	}
+
+	var event struct {
+		Event models.Event `json:"event"`
+	}
+	env.decode(env.expect(http.MethodGet, "/api/v1/events/"+eventID.String(), nil, http.StatusOK), &event)
+	if event.Event.ReservedSeats != 1 {
+		t.Fatalf("reserved seats = %d after a retried booking, want 1", event.Event.ReservedSeats)
+	}
+
+	env.expectError(http.MethodPost, bookPath(eventID), anna, http.StatusConflict, "user already has a booking for this event")
+
+	reused := env.doWithKey(http.MethodPost, bookPath(eventID), map[string]any{"email": "boris@example.com"}, "book-anna")
+	if reused.status != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused with another body: status = %d, want 422", reused.status)
+	}
+	reused = env.doWithKey(http.MethodPost, "/api/v1/users", map[string]any{"name": "Anna", "email": "anna@example.com"}, "book-anna")
+	if reused.status != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused on another route: status = %d, want 422", reused.status)
+	}
+
+	var booked struct {
+		BookingID uuid.UUID `json:"booking_id"`
+	}
+	env.decode(first, &booked)
+	confirm := map[string]any{"booking_id": booked.BookingID}
+	for range 2 {
+		if resp := env.doWithKey(http.MethodPost, confirmPath(eventID), confirm, "confirm-anna"); resp.status != http.StatusOK {
+			t.Fatalf("confirm with a key: status = %d, want 200 for the request and its retry", resp.status)
+		}
+	}
+	env.expectError(http.MethodPost, confirmPath(eventID), confirm, http.StatusBadRequest, "booking is not in reserved status")
+
+	boris := map[string]any{"email": "boris@example.com"}
+	env.repo.fail(errors.New("connection reset"), nil)
+	if resp := env.doWithKey(http.MethodPost, bookPath(eventID), boris, "book-boris"); resp.status != http.StatusInternalServerError {
+		t.Fatalf("failing booking: status = %d, want 500", resp.status)
+	}
+	env.repo.fail(nil, nil)
+	if resp := env.doWithKey(http.MethodPost, bookPath(eventID), boris, "book-boris"); resp.status != http.StatusCreated {
+		t.Fatalf("retry after a server error: status = %d, want 201", resp.status)
+	}
+
+	entered, release := env.repo.hold()
+	done := make(chan response)
+	carl := map[string]any{"email": "carl@example.com"}
+	env.createUser("carl@example.com")
+	go func() { done <- env.doWithKey(http.MethodPost, bookPath(eventID), carl, "book-carl") }()
+	<-entered
+
+	busy := env.doWithKey(http.MethodPost, bookPath(eventID), carl, "book-carl")
+	if busy.status != http.StatusConflict || busy.header.Get("Retry-After") != "1" {
+		t.Fatalf("concurrent retry: status = %d, Retry-After = %q", busy.status, busy.header.Get("Retry-After"))
+	}
+	release()
+	if resp := <-done; resp.status != http.StatusCreated {
+		t.Fatalf("held booking: status = %d, want 201", resp.status)
+	}
+
+	if resp := env.doWithKey(http.MethodPost, bookPath(eventID), carl, strings.Repeat("k", 256)); resp.status != http.StatusBadRequest {
+		t.Fatalf("oversized key: status = %d, want 400", resp.status)
+	}
+
+	if resp := env.doWithKey(http.MethodPost, "/api/v1/admin/reconcile", nil, "reconcile"); resp.status != http.StatusOK {
+		t.Fatalf("reconcile with a key: status = %d, want 200", resp.status)
+	}
+	if resp := env.doWithKey(http.MethodPost, "/api/v1/admin/reconcile?repair=true", nil, "reconcile"); resp.status != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused with another query: status = %d, want 422", resp.status)
+	}
+
+	// The same key sent by another client is a request of its own.
+	env.createUser("dina@example.com")
+	req := httptest.NewRequest(http.MethodPost, bookPath(eventID), strings.NewReader(`{"email": "dina@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "book-anna")
+	req.RemoteAddr = "198.51.100.7:4321"
+	rec := httptest.NewRecorder()
+	env.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("key of another client: status = %d, replayed = %q, body: %s", rec.Code, rec.Header().Get("Idempotent-Replayed"), rec.Body)
+	}
+
+	// A response that could not be stored releases the key, so the retry is
+	// processed again rather than reported as still in progress.
+	env.createUser("erik@example.com")
+	erik := map[string]any{"email": "erik@example.com"}
+	env.repo.failCompletion(errors.New("connection reset"))
+	if resp := env.doWithKey(http.MethodPost, bookPath(eventID), erik, "book-erik"); resp.status != http.StatusCreated {
+		t.Fatalf("booking with a failing store: status = %d, want 201", resp.status)
+	}
+	env.repo.failCompletion(nil)
+	retry = env.doWithKey(http.MethodPost, bookPath(eventID), erik, "book-erik")
+	if retry.status != http.StatusConflict || !strings.Contains(string(retry.body), "user already has a booking for this event") {
+		t.Fatalf("retry after a failed store: status = %d, body: %s", retry.status, retry.body)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	expectLimited := func(t *testing.T, resp response) {
+		t.Helper()
+		if resp.status != http.StatusTooManyRequests {
+			t.Fatalf("status = %d, want 429, body: %s", resp.status, resp.body)
+		}
+		if seconds, err := strconv.Atoi(resp.header.Get("Retry-After")); err != nil || seconds < 1 {
+			t.Fatalf("Retry-After = %q, want a positive number of seconds", resp.header.Get("Retry-After"))
+		}
+	}
+
+	t.Run("PerIP", func(t *testing.T) {
+		env := newEnvWithLimits(t, config.BookingConfig{}, config.RateLimitConfig{Enabled: true, IPPerMinute: 1, IPBurst: 2})
+
+		env.createUser("anna@example.com")
+		env.createUser("boris@example.com")
+		expectLimited(t, env.do(http.MethodPost, "/api/v1/users", map[string]any{"name": "Vera", "email": "vera@example.com"}))
+
+		// Other endpoints are not limited.
+		env.createEvent(5, true)
+	})
+
+	t.Run("PerUser", func(t *testing.T) {
+		env := newEnvWithLimits(t, config.BookingConfig{}, config.RateLimitConfig{Enabled: true, UserPerMinute: 1, UserBurst: 2})
+		env.createUser("anna@example.com")
+		env.createUser("boris@example.com")
+		anna := map[string]any{"email": "anna@example.com"}
+
+		first, second, third := env.createEvent(5, true), env.createEvent(5, true), env.createEvent(5, true)
+		env.book(first, "anna@example.com")
+		env.expectError(http.MethodPost, bookPath(first), anna, http.StatusConflict, "user already has a booking for this event")
+		expectLimited(t, env.do(http.MethodPost, bookPath(second), anna))
+
+		env.book(third, "boris@example.com")
+	})
+
+	t.Run("PerEvent", func(t *testing.T) {
+		env := newEnvWithLimits(t, config.BookingConfig{}, config.RateLimitConfig{Enabled: true, EventPerMinute: 1, EventBurst: 1})
+		env.createUser("anna@example.com")
+		env.createUser("boris@example.com")
+
+		hot, other := env.createEvent(5, true), env.createEvent(5, true)
+		env.book(hot, "anna@example.com")
+		expectLimited(t, env.do(http.MethodPost, bookPath(hot), map[string]any{"email": "boris@example.com"}))
+
+		env.book(other, "boris@example.com")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		env := newEnvWithLimits(t, config.BookingConfig{}, config.RateLimitConfig{IPPerMinute: 1, IPBurst: 1})
+
+		env.createUser("anna@example.com")
+		env.createUser("boris@example.com")
+	})
+
+	t.Run("NotStoredForIdempotentRetry", func(t *testing.T) {
+		env := newEnvWithLimits(t, config.BookingConfig{}, config.RateLimitConfig{Enabled: true, IPPerMinute: 1, IPBurst: 1})
+		env.createUser("anna@example.com")
+
+		body := map[string]any{"name": "Boris", "email": "boris@example.com"}
+		expectLimited(t, env.doWithKey(http.MethodPost, "/api/v1/users", body, "user-boris"))
+		expectLimited(t, env.doWithKey(http.MethodPost, "/api/v1/users", body, "user-boris"))
+	})
+}
+
+func TestReservationQuota(t *testing.T) {
+	env := newEnvWithLimits(t, config.BookingConfig{MaxActiveReservations: 1}, config.RateLimitConfig{})
+	env.createUser("anna@example.com")
+	anna := map[string]any{"email": "anna@example.com"}
+
+	first, second := env.createEvent(5, true), env.createEvent(5, true)
+	bookingID := env.book(first, "anna@example.com")
+	env.expectError(http.MethodPost, bookPath(second), anna, http.StatusConflict, "user has too many unpaid reservations")
+
+	env.book(env.createEvent(5, false), "anna@example.com")
+
+	env.expect(http.MethodPost, confirmPath(first), map[string]any{"booking_id": bookingID}, http.StatusOK)
+	env.book(second, "anna@example.com")
+}
+
+func TestAdminJobs(t *testing.T) {
+	env := newEnv(t)
+
+	golden(t, "list_jobs", env.expect(http.MethodGet, "/api/v1/admin/jobs", nil, http.StatusOK))
+
+	env.expectError(http.MethodGet, "/api/v1/admin/jobs?limit=0", nil, http.StatusBadRequest, "invalid limit")
+	env.expectError(http.MethodPost, "/api/v1/admin/jobs/missing/run", nil, http.StatusNotFound, "job not found")
+
+	golden(t, "run_job", env.expect(http.MethodPost, "/api/v1/admin/jobs/blocking/run", nil, http.StatusAccepted))
+	env.expectError(http.MethodPost, "/api/v1/admin/jobs/blocking/run", nil, http.StatusConflict, "job is already running")
+}
+
+func TestAdminAuth(t *testing.T) {
+	env := newEnv(t)
+
+	for _, auth := range []string{"", "secret", "Bearer wrong", "Basic " + adminToken} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reconcile?repair=true", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		env.router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("Authorization %q: status = %d, headers %v", auth, rec.Code, rec.Header())
+		}
+	}
+
+	disabled := serverConfig
+	disabled.AdminToken = ""
+	sched := scheduler.NewScheduler(env.repo)
+	t.Cleanup(sched.Stop)
+	router := handler.NewHandler(env.svc, sched, health.NewChecker(), availability.NewFeed(availability.NewHub(), env.svc.GetEventByID), disabled, config.RateLimitConfig{}).NewRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/jobs", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("admin API without a token configured: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	env := newEnv(t)
+
+	event := env.createEvent(5, true)
+	env.createUser("anna@example.com")
+	env.book(event, "anna@example.com")
+
+	env.expectError(http.MethodPost, "/api/v1/admin/reconcile?repair=maybe", nil, http.StatusBadRequest, "invalid repair")
+	golden(t, "reconcile", env.expect(http.MethodPost, "/api/v1/admin/reconcile?repair=true", nil, http.StatusOK))
+}
+
+func TestProbes(t *testing.T) {
+	env := newEnv(t)
+
+	golden(t, "healthz", env.expect(http.MethodGet, "/healthz", nil, http.StatusOK))
+	golden(t, "readyz", env.expect(http.MethodGet, "/readyz", nil, http.StatusOK))
+
+	// Without the admin token only the overall status is shown.
+	rec := httptest.NewRecorder()
+	env.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if got := strings.TrimSpace(rec.Body.String()); rec.Code != http.StatusOK || got != `{"status":"ok"}` {
+		t.Fatalf("public /readyz = %d %s, want only the status", rec.Code, got)
+	}
+
+	resp := env.expect(http.MethodGet, "/metrics", nil, http.StatusOK)
+	if !bytes.Contains(resp.body, []byte("event_booker_http_requests_total")) {
+		t.Fatalf("/metrics does not expose HTTP request metrics")
+	}
+}
+
+func TestPages(t *testing.T) {
+	t.Chdir("../..")
+	env := newEnv(t)
+
+	for _, path := range []string{"/", "/register", "/admin", "/event", "/docs"} {
+		resp := env.expect(http.MethodGet, path, nil, http.StatusOK)
+		if !strings.HasPrefix(resp.header.Get("Content-Type"), "text/html") {
+			t.Fatalf("%s: Content-Type = %q", path, resp.header.Get("Content-Type"))
+		}
+	}
+
+	// The docs page loads Swagger UI from the server, not from a CDN.
+	docs := env.expect(http.MethodGet, "/docs", nil, http.StatusOK)
+	if bytes.Contains(docs.body, []byte("https://")) {
+		t.Fatalf("/docs loads external resources")
+	}
+	assets := regexp.MustCompile(`(?:href|src)="(/docs/assets/[^"]+)"`).FindAllSubmatch(docs.body, -1)
+	if len(assets) != 2 {
+		t.Fatalf("/docs references %d assets, want the Swagger UI script and stylesheet", len(assets))
+	}
+	for _, asset := range assets {
+		env.expect(http.MethodGet, string(asset[1]), nil, http.StatusOK)
+	}
+}
+
+func TestGoldenFlow(t *testing.T) {
+	env := newEnv(t)
+
+	created := env.expect(http.MethodPost, "/api/v1/events", map[string]any{
+		"name":                          "Go meetup",
+		"date":                          "2099-06-01T18:00:00Z",
+		"time_zone":                     "Europe/Moscow",
+		"total_seats":                   3,
+		"booking_lifetime_hours":        1,
+		"booking_lifetime_minutes":      30,
+		"requires_payment_confirmation": true,
+		"description":                   "Talks about **generics** and profiling.",
+		"category":                      "meetup",
+		"tags":                          []string{"Go", " backend ", "go"},
+		"organizer_contact":             "org@example.com",
+		"cover_image_url":               "https://example.com/cover.png",
+	}, http.StatusCreated)
+	golden(t, "create_event", created)
+
+	var event struct {
+		Event models.Event `json:"event"`
+	}
+	env.decode(created, &event)
+	id := event.Event.ID
+
+	var local dto.CreateEventResponse
+	env.decode(created, &local)
+	if local.Event.LocalDate != "2099-06-01T21:00:00+03:00" || local.Event.LocalEndDate != "2099-06-01T23:00:00+03:00" {
+		t.Fatalf("local times = %s - %s, want them in Europe/Moscow", local.Event.LocalDate, local.Event.LocalEndDate)
+	}
+
+	telegramID := int64(123456789)
+	golden(t, "create_user", env.expect(http.MethodPost, "/api/v1/users",
+		map[string]any{"name": "Anna", "email": "anna@example.com", "telegram_id": telegramID}, http.StatusCreated))
+
+	booked := env.expect(http.MethodPost, bookPath(id), map[string]any{"email": "anna@example.com"}, http.StatusCreated)
+	golden(t, "book_event", booked)
+
+	var booking struct {
+		BookingID uuid.UUID `json:"booking_id"`
+	}
+	env.decode(booked, &booking)
+
+	golden(t, "confirm_booking", env.expect(http.MethodPost, confirmPath(id),
+		map[string]any{"booking_id": booking.BookingID}, http.StatusOK))
+	golden(t, "get_event", env.expect(http.MethodGet, "/api/v1/events/"+id.String(), nil, http.StatusOK))
+	golden(t, "list_events", env.expect(http.MethodGet, "/api/v1/events", nil, http.StatusOK))
+	golden(t, "list_bookings", env.expect(http.MethodGet, "/api/v1/events/"+id.String()+"/bookings", nil, http.StatusOK))
+	golden(t, "event_not_found", env.expect(http.MethodGet, "/api/v1/events/"+uuid.NewString(), nil, http.StatusNotFound))
+	golden(t, "validation_failed", env.expect(http.MethodPost, "/api/v1/users",
+		map[string]any{"name": "Anna1", "email": "anna@", "telegram_id": 42}, http.StatusBadRequest))
+}
+
+var (
+	uuidPattern      = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	timePattern      = regexp.MustCompile(`"\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})"`)
+	durationPattern  = regexp.MustCompile(`"duration_ms": \d+`)
+	countdownPattern = regexp.MustCompile(`"sales_countdown_seconds": \d+`)
+)
+
+// golden compares the response body with testdata/<name>.golden after
+// replacing identifiers and timestamps, which differ between runs, with
+// placeholders. Run `go test ./internal/handler -update` to rewrite the files.
+func golden(t *testing.T, name string, resp response) {
+	t.Helper()
+
+	contentType := "application/json"
+	if resp.status >= http.StatusBadRequest {
+		contentType = "application/problem+json"
+	}
+	if ct := resp.header.Get("Content-Type"); ct != contentType {
+		t.Fatalf("%s: Content-Type = %q, want %s", name, ct, contentType)
+	}
+
+	var body any
+	if err := json.Unmarshal(resp.body, &body); err != nil {
+		t.Fatalf("%s: decode %s: %v", name, resp.body, err)
+	}
+	formatted, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		t.Fatalf("%s: encode: %v", name, err)
+	}
+
+	got := uuidPattern.ReplaceAll(formatted, []byte("<uuid>"))
+	got = timePattern.ReplaceAll(got, []byte(`"<time>"`))
+	got = durationPattern.ReplaceAll(got, []byte(`"duration_ms": 0`))
+	got = countdownPattern.ReplaceAll(got, []byte(`"sales_countdown_seconds": 0`))
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatalf("mkdir testdata: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v (run with -update to create it)", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s: response does not match %s\n got:\n%s\nwant:\n%s", name, path, got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"net/http"
+)
+
+func (h *Handler) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, dto.HealthResponse{
+		Status: models.CheckStatusOK,
+	})
+}
+
+// readyzHandler reports readiness. Check errors can name internal hosts, so
+// unless detailed is set the checks are shown only to admins.
+func (h *Handler) readyzHandler(detailed bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ready, checks := h.health.Ready(r.Context())
+		if !detailed && !h.isAdmin(r) {
+			checks = nil
+		}
+
+		status, result := http.StatusOK, models.CheckStatusOK
+		if !ready {
+			status, result = http.StatusServiceUnavailable, models.CheckStatusFail
+		}
+
+		respondJSON(w, status, dto.HealthResponse{
+			Status: result,
+			Checks: checks,
+		})
+	}
+}
@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/logging"
 	"github.com/kstsm/wb-event-booker/internal/models"
 	"net/http"
+	"strconv"
 )
 
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -15,21 +18,47 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
-func respondError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(models.Error{Error: message})
+// respondProblem answers with the RFC 7807 problem details of err. The status
+// and code come from the apperrors catalogue; errors outside it are reported
+// as internal errors and only their existence reaches the client.
+func respondProblem(w http.ResponseWriter, r *http.Request, err error) {
+	entry := apperrors.Describe(err)
+	if entry.Status >= http.StatusInternalServerError {
+		logging.SetError(r.Context(), err)
+	}
+
+	problem := models.Problem{
+		Type:     entry.Type(),
+		Title:    entry.Title,
+		Status:   entry.Status,
+		Detail:   entry.Err.Error(),
+		Instance: r.URL.Path,
+		Code:     entry.Code,
+	}
+
+	var validation *apperrors.ValidationError
+	if errors.As(err, &validation) {
+		problem.Detail = validation.Error()
+		problem.Errors = validation.Fields
+	}
+
+	if entry.RetryAfter > 0 && w.Header().Get("Retry-After") == "" {
+		w.Header().Set("Retry-After", strconv.Itoa(entry.RetryAfter))
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(entry.Status)
+	json.NewEncoder(w).Encode(problem)
 }
 
 func parseUUIDParam(r *http.Request, param string) (uuid.UUID, error) {
 	value := chi.URLParam(r, param)
 	if value == "" {
-		return uuid.Nil, fmt.Errorf("%s is required", param)
+		return uuid.Nil, apperrors.InvalidField(param, "%s is required", param)
 	}
 
 	id, err := uuid.Parse(value)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid %s", param)
+		return uuid.Nil, apperrors.InvalidField(param, "invalid %s", param)
 	}
 
 	return id, nil
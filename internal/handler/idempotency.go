@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"io"
+	"net/http"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	maxIdempotentBodySize    = 1 << 20
+)
+
+// idempotencyMiddleware makes mutating requests with an Idempotency-Key
+// header safe to retry. The first request with a key is processed and its
+// response stored; repeating it with the same method, URL and body replays
+// that response instead of running the handler again. Keys are scoped to the
+// client, so one client cannot replay or block the requests of another.
+// Server errors, rate limit and authentication rejections are not stored, so
+// the retry of a failed request is processed anew.
+func (h *Handler) idempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			respondProblem(w, r, apperrors.InvalidField(idempotencyKeyHeader,
+				"%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondProblem(w, r, apperrors.RequestBodyTooLarge)
+				return
+			}
+			respondProblem(w, r, apperrors.InvalidRequestBody)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		key = h.scopedIdempotencyKey(r, key)
+		stored, err := h.service.BeginIdempotentRequest(r.Context(), key, requestFingerprint(r, body), h.idempotencyTTL)
+		if err != nil {
+			respondProblem(w, r, err)
+			return
+		}
+
+		if stored != nil {
+			replay(w, stored)
+			return
+		}
+
+		// The response is already on its way to the client, so the outcome is
+		// recorded even if the client has gone away in the meantime.
+		ctx := context.WithoutCancel(r.Context())
+		recorder := &responseRecorder{ResponseWriter: w}
+
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := h.service.AbortIdempotentRequest(ctx, key); err != nil {
+				logging.FromContext(ctx).Errorf("Failed to release idempotency key: %v", err)
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		if !storable(recorder.statusCode()) {
+			return
+		}
+
+		err = h.service.CompleteIdempotentRequest(ctx, &models.IdempotencyKey{
+			Key:         key,
+			StatusCode:  recorder.statusCode(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			logging.FromContext(ctx).Errorf("Failed to store idempotent response: %v", err)
+			return
+		}
+		completed = true
+	})
+}
+
+// scopedIdempotencyKey ties key to the client that sent it: the bearer token
+// when there is one, the client address otherwise. The result is hashed to
+// fit the stored key length whatever the length of the key.
+func (h *Handler) scopedIdempotencyKey(r *http.Request, key string) string {
+	client := "ip " + h.clientIP(r)
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		client = "auth " + auth
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s", client, key)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// storable reports whether a response says something about the request
+// itself rather than about when or by whom it was sent.
+func storable(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+func replay(w http.ResponseWriter, stored *models.IdempotencyKey) {
+	w.Header().Set(idempotentReplayedHeader, "true")
+	if stored.ContentType != "" {
+		w.Header().Set("Content-Type", stored.ContentType)
+	}
+	w.WriteHeader(stored.StatusCode)
+	w.Write(stored.Body)
+}
+
+// requestFingerprint identifies what a key was used for, so that reusing the
+// key for another request can be told apart from a retry.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder passes the response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
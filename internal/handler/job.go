@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultJobRunsLimit = 50
+	maxJobRunsLimit     = 500
+)
+
+func (h *Handler) listJobsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := defaultJobRunsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxJobRunsLimit {
+			respondProblem(w, r, apperrors.InvalidField("limit", "invalid limit"))
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.scheduler.History(r.Context(), limit)
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	statuses := h.scheduler.Jobs()
+	jobs := make([]dto.JobResponse, 0, len(statuses))
+	for _, status := range statuses {
+		job := dto.JobResponse{
+			Name:    status.Name,
+			Spec:    status.Spec,
+			Running: status.Running,
+			LastRun: dto.NewJobRun(status.LastRun),
+		}
+		if !status.NextRun.IsZero() {
+			nextRun := status.NextRun.UTC()
+			job.NextRun = &nextRun
+		}
+		jobs = append(jobs, job)
+	}
+
+	respondJSON(w, http.StatusOK, dto.ListJobsResponse{
+		Jobs: jobs,
+		Runs: dto.NewJobRuns(runs),
+	})
+}
+
+func (h *Handler) runJobHandler(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	err := h.scheduler.RunNow(name)
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, dto.RunJobResponse{
+		Message: "job started",
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(metricsMiddleware)
+	r.Get("/api/events/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Get("/api/events", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		path   string
+		route  string
+		status string
+	}{
+		{"/api/events/42", "/api/events/{id}", "404"},
+		{"/api/events", "/api/events", "200"},
+		{"/missing", "unmatched", "404"},
+	}
+	for _, tt := range tests {
+		counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, tt.route, tt.status)
+		before := testutil.ToFloat64(counter)
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if got := testutil.ToFloat64(counter) - before; got != 1 {
+			t.Errorf("GET %s: %v requests counted for route %s and status %s, want 1", tt.path, got, tt.route, tt.status)
+		}
+	}
+}
+
+func TestLoggingMiddlewareRequestID(t *testing.T) {
+	var seen string
+	handler := loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"generated when missing", "", false},
+		{"kept from the client", "checkout-7f3a", true},
+		{"kept with dots and underscores", "web_1.checkout-7f3a", true},
+		{"replaced when too long", strings.Repeat("a", 129), false},
+		{"replaced with spaces", "checkout 7f3a", false},
+		{"replaced with markup", "<script>alert(1)</script>", false},
+		{"replaced with non-ASCII letters", "заказ-1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+			if tt.header != "" {
+				req.Header.Set(requestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(requestIDHeader)
+			if got != seen {
+				t.Errorf("response request ID %q, logged %q", got, seen)
+			}
+			if tt.keep && got != tt.header {
+				t.Errorf("request ID = %q, want the client one %q", got, tt.header)
+			}
+			if _, err := uuid.Parse(got); !tt.keep && err != nil {
+				t.Errorf("request ID = %q, want a generated UUID", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/gookit/slog"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/kstsm/wb-event-booker/internal/ratelimit"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxPeekedBodySize = 1 << 20
+
+// rateLimits holds one limiter per scope. A nil limiter lets every request
+// through. trustProxy also applies with the limits disabled, since the client
+// address scopes idempotency keys as well.
+type rateLimits struct {
+	ip         ratelimit.LimiterI
+	user       ratelimit.LimiterI
+	event      ratelimit.LimiterI
+	trustProxy bool
+}
+
+func newRateLimits(cfg config.RateLimitConfig) rateLimits {
+	if !cfg.Enabled {
+		return rateLimits{trustProxy: cfg.TrustProxy}
+	}
+
+	limiter := func(perMinute, burst int) ratelimit.LimiterI {
+		if perMinute <= 0 {
+			return nil
+		}
+		return ratelimit.NewLimiter(perMinute, burst)
+	}
+
+	return rateLimits{
+		ip:         limiter(cfg.IPPerMinute, cfg.IPBurst),
+		user:       limiter(cfg.UserPerMinute, cfg.UserBurst),
+		event:      limiter(cfg.EventPerMinute, cfg.EventBurst),
+		trustProxy: cfg.TrustProxy,
+	}
+}
+
+// limitByIP throttles each client address, which keeps a single script from
+// registering users in bulk.
+func (h *Handler) limitByIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.allow(w, r, h.limits.ip, metrics.RateLimitIP, h.clientIP(r)) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitBooking throttles booking attempts of each user, so that rotating
+// addresses does not help, and of each event, so that a rush on one event
+// cannot starve the others.
+func (h *Handler) limitBooking(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.limits.user != nil {
+			email, err := peekEmail(r)
+			if err != nil {
+				respondProblem(w, r, apperrors.InvalidRequestBody)
+				return
+			}
+			// Requests without an email are rejected by the handler anyway.
+			if email != "" && !h.allow(w, r, h.limits.user, metrics.RateLimitUser, email) {
+				return
+			}
+		}
+
+		if !h.allow(w, r, h.limits.event, metrics.RateLimitEvent, chi.URLParam(r, "id")) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes a token for key and answers 429 with Retry-After when there
+// is none.
+func (h *Handler) allow(w http.ResponseWriter, r *http.Request, limiter ratelimit.LimiterI, scope, key string) bool {
+	if limiter == nil {
+		return true
+	}
+
+	ok, wait := limiter.Allow(key)
+	if ok {
+		return true
+	}
+
+	metrics.RateLimited.WithLabelValues(scope, routePattern(r)).Inc()
+	logging.AddFields(r.Context(), slog.M{"rate_limited": scope})
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(wait)))
+	respondProblem(w, r, apperrors.RateLimited)
+
+	return false
+}
+
+func retryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
+
+// clientIP returns the address of the client. Forwarding headers are only
+// honoured when the server is configured to run behind a trusted proxy,
+// otherwise any client could pick its own bucket.
+func (h *Handler) clientIP(r *http.Request) string {
+	if h.limits.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return strings.TrimSpace(realIP)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// peekEmail reads the email of a booking request and puts the body back for
+// the handler. A body that is not valid JSON yields an empty email and is
+// left for the handler to reject.
+func peekEmail(r *http.Request) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekedBodySize))
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req dto.BookEventRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil
+	}
+
+	return req.Email, nil
+}
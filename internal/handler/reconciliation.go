@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) reconcileSeatsHandler(w http.ResponseWriter, r *http.Request) {
+	repair := false
+	if value := r.URL.Query().Get("repair"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondProblem(w, r, apperrors.InvalidField("repair", "invalid repair"))
+			return
+		}
+		repair = parsed
+	}
+
+	report, err := h.service.ReconcileSeatCounters(r.Context(), repair)
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ReconcileSeatsResponse{
+		Reconciliation: dto.NewSeatReconciliation(report),
+	})
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+	// sseRetry is the reconnection delay suggested to EventSource clients.
+	sseRetry = 3 * time.Second
+	// defaultSSEHeartbeat applies when the server config leaves the
+	// heartbeat unset, as tests do.
+	defaultSSEHeartbeat = 15 * time.Second
+)
+
+// eventStreamHandler sends the seat availability of an event as Server-Sent
+// Events: the current state right away and then every change of it. The id
+// of a message identifies the state it describes, so a client reconnecting
+// with Last-Event-ID only receives a message if something changed while it
+// was away. The stream ends once the event is cancelled, and a reconnection
+// that already knows about the cancellation is answered with 204, which tells
+// EventSource to stop.
+func (h *Handler) eventStreamHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	// Subscribing before the first read makes sure no change is missed
+	// between the two. Later states come from the feed, which reads the
+	// event once per change for all of its streams.
+	changes, unsubscribe := h.availability.Subscribe(eventID)
+	defer unsubscribe()
+
+	event, err := h.service.GetEventByID(r.Context(), eventID)
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	lastID := r.Header.Get(lastEventIDHeader)
+	id := availabilityID(event)
+	if event.IsCancelled() && id == lastID {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+
+	rc := http.NewResponseController(w)
+	heartbeat := time.NewTicker(h.sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		if id != lastID {
+			if err := writeAvailability(w, id, event); err != nil {
+				return
+			}
+			lastID = id
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		if event.IsCancelled() {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			continue
+		case changed, ok := <-changes:
+			if !ok {
+				return
+			}
+			event = changed
+		}
+
+		id = availabilityID(event)
+	}
+}
+
+func availabilityID(e *models.Event) string {
+	id := fmt.Sprintf("%d-%d-%d", e.TotalSeats, e.ReservedSeats, e.BookedSeats)
+	if e.IsCancelled() {
+		id += "-cancelled"
+	}
+	return id
+}
+
+func writeAvailability(w io.Writer, id string, e *models.Event) error {
+	data, err := json.Marshal(dto.NewEventAvailability(e))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: availability\ndata: %s\n\n", id, data)
+	return err
+}
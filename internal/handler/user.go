@@ -2,30 +2,35 @@ package handler
 
 import (
 	"encoding/json"
+	"github.com/gookit/slog"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/logging"
 	"net/http"
 )
 
 func (h *Handler) createUserHandler(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, apperrors.InvalidRequestBody)
 		return
 	}
 
 	if err := req.ValidateUser(); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
 	user, err := h.service.CreateUser(r.Context(), &req)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondProblem(w, r, err)
 		return
 	}
 
+	logging.AddFields(r.Context(), slog.M{logging.FieldUserID: user.ID})
+
 	respondJSON(w, http.StatusCreated, dto.CreateUserResponse{
-		User:    user,
+		User:    dto.NewUser(user),
 		Message: "user created successfully",
 	})
 }
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/kstsm/wb-event-booker/api"
+	"net/http"
+)
+
+// routesV1 registers API v1. Its handlers respond with the dto types, which
+// are frozen for the lifetime of the version: a breaking change goes into a
+// routesV2 with its own handlers and response types, mounted at /api/v2 next
+// to this one, and v1 is then deprecated the way the unversioned /api is.
+func (h *Handler) routesV1(r chi.Router) {
+	r.Use(h.idempotencyMiddleware)
+
+	r.Get("/openapi.json", openAPIHandler)
+
+	r.Post("/events", h.createEventHandler)
+	r.With(h.limitByIP, h.limitBooking).Post("/events/{id}/book", h.bookEventHandler)
+	r.Post("/events/{id}/confirm", h.ConfirmBookingHandler)
+	r.Post("/events/{id}/subscribe", h.subscribeToSalesHandler)
+	r.Get("/events/{id}", h.getEventByIDHandler)
+	r.Get("/events/{id}/stream", h.eventStreamHandler)
+
+	r.Get("/events", h.listEventsHandler)
+	r.Get("/events/{id}/bookings", h.listBookingsByEventHandler)
+
+	r.Post("/venues", h.createVenueHandler)
+	r.Get("/venues", h.listVenuesHandler)
+	r.Get("/venues/{id}", h.getVenueByIDHandler)
+	r.Put("/venues/{id}", h.updateVenueHandler)
+	r.Delete("/venues/{id}", h.deleteVenueHandler)
+
+	r.With(h.limitByIP).Post("/users", h.createUserHandler)
+
+	r.Group(func(r chi.Router) {
+		r.Use(h.requireAdmin)
+
+		r.Get("/admin/jobs", h.listJobsHandler)
+		r.Post("/admin/jobs/{name}/run", h.runJobHandler)
+		r.Post("/admin/reconcile", h.reconcileSeatsHandler)
+	})
+}
+
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(api.Spec)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"net/http"
+)
+
+func (h *Handler) createVenueHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.VenueRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, apperrors.InvalidRequestBody)
+		return
+	}
+
+	if err := req.ValidateVenue(); err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	venue, err := h.service.CreateVenue(r.Context(), &req)
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, dto.VenueResponse{
+		Venue:   dto.NewVenue(venue),
+		Message: "venue created successfully",
+	})
+}
+
+func (h *Handler) getVenueByIDHandler(w http.ResponseWriter, r *http.Request) {
+	venueID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	venue, err := h.service.GetVenueByID(r.Context(), venueID)
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.VenueResponse{
+		Venue: dto.NewVenue(venue),
+	})
+}
+
+func (h *Handler) listVenuesHandler(w http.ResponseWriter, r *http.Request) {
+	venues, err := h.service.ListVenues(r.Context())
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ListVenuesResponse{
+		Venues: dto.NewVenues(venues),
+	})
+}
+
+func (h *Handler) updateVenueHandler(w http.ResponseWriter, r *http.Request) {
+	venueID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	var req dto.VenueRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, apperrors.InvalidRequestBody)
+		return
+	}
+
+	if err := req.ValidateVenue(); err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	venue, err := h.service.UpdateVenue(r.Context(), venueID, &req)
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.VenueResponse{
+		Venue:   dto.NewVenue(venue),
+		Message: "venue updated successfully",
+	})
+}
+
+func (h *Handler) deleteVenueHandler(w http.ResponseWriter, r *http.Request) {
+	venueID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	if err := h.service.DeleteVenue(r.Context(), venueID); err != nil {
+		respondProblem(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const checkTimeout = 2 * time.Second
+
+type Check func(ctx context.Context) error
+
+type CheckerI interface {
+	Register(name string, check Check)
+	RegisterOptional(name string, check Check)
+	Ready(ctx context.Context) (bool, map[string]models.CheckResult)
+	SetShuttingDown()
+}
+
+type Checker struct {
+	mu           sync.RWMutex
+	names        []string
+	checks       map[string]Check
+	optional     map[string]bool
+	shuttingDown atomic.Bool
+}
+
+func NewChecker() CheckerI {
+	return &Checker{
+		checks:   make(map[string]Check),
+		optional: make(map[string]bool),
+	}
+}
+
+func (c *Checker) Register(name string, check Check) {
+	c.register(name, check, false)
+}
+
+// RegisterOptional adds a check of a dependency the service can run without:
+// its result is reported, but a failure does not make the service unready.
+func (c *Checker) RegisterOptional(name string, check Check) {
+	c.register(name, check, true)
+}
+
+func (c *Checker) register(name string, check Check, optional bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+	c.optional[name] = optional
+}
+
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) Ready(ctx context.Context) (bool, map[string]models.CheckResult) {
+	c.mu.RLock()
+	names := append([]string(nil), c.names...)
+	checks := make([]Check, 0, len(names))
+	optional := make([]bool, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, c.checks[name])
+		optional = append(optional, c.optional[name])
+	}
+	c.mu.RUnlock()
+
+	results := make(map[string]models.CheckResult, len(names)+1)
+	ready := true
+
+	if c.shuttingDown.Load() {
+		ready = false
+		results["shutdown"] = models.CheckResult{
+			Status: models.CheckStatusFail,
+			Error:  "server is shutting down",
+		}
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for i, name := range names {
+		wg.Add(1)
+		go func(name string, check Check, optional bool) {
+			defer wg.Done()
+
+			result := run(ctx, check)
+
+			mu.Lock()
+			results[name] = result
+			if result.Status != models.CheckStatusOK && !optional {
+				ready = false
+			}
+			mu.Unlock()
+		}(name, checks[i], optional[i])
+	}
+	wg.Wait()
+
+	return ready, results
+}
+
+func run(ctx context.Context, check Check) models.CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := models.CheckResult{
+		Status:     models.CheckStatusOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		result.Status = models.CheckStatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Cached wraps a check so that expensive or rate-limited dependencies are
+// not called on every probe.
+func Cached(check Check, ttl time.Duration) Check {
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		lastErr error
+	)
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checked.IsZero() && time.Since(checked) < ttl {
+			return lastErr
+		}
+
+		lastErr = check(ctx)
+		checked = time.Now()
+
+		return lastErr
+	}
+}
@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"github.com/kstsm/wb-event-booker/internal/health"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"testing"
+	"time"
+)
+
+func TestReady(t *testing.T) {
+	checker := health.NewChecker()
+	checker.Register("postgres", func(ctx context.Context) error { return nil })
+
+	ready, results := checker.Ready(context.Background())
+	if !ready || len(results) != 1 || results["postgres"].Status != models.CheckStatusOK {
+		t.Fatalf("Ready = %v, %+v, want ready with postgres ok", ready, results)
+	}
+
+	checker.Register("scheduler", func(ctx context.Context) error { return errors.New("not started") })
+	ready, results = checker.Ready(context.Background())
+	if ready {
+		t.Errorf("Ready with a failing check = true")
+	}
+	if got := results["scheduler"]; got.Status != models.CheckStatusFail || got.Error != "not started" {
+		t.Errorf("failing check = %+v", got)
+	}
+	if results["postgres"].Status != models.CheckStatusOK {
+		t.Errorf("passing check = %+v", results["postgres"])
+	}
+
+	checker.Register("scheduler", func(ctx context.Context) error { return nil })
+	checker.RegisterOptional("notifier", func(ctx context.Context) error { return errors.New("too many requests") })
+	ready, results = checker.Ready(context.Background())
+	if !ready {
+		t.Errorf("Ready with a failing optional check = false")
+	}
+	if got := results["notifier"]; got.Status != models.CheckStatusFail || got.Error != "too many requests" {
+		t.Errorf("failing optional check = %+v", got)
+	}
+
+	checker.SetShuttingDown()
+	ready, results = checker.Ready(context.Background())
+	if ready || results["shutdown"].Status != models.CheckStatusFail {
+		t.Errorf("Ready while shutting down = %v, %+v", ready, results)
+	}
+	if results["scheduler"].Status != models.CheckStatusOK {
+		t.Errorf("replaced check = %+v, want ok", results["scheduler"])
+	}
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	check := health.Cached(func(ctx context.Context) error {
+		calls++
+		return errors.New("rate limited")
+	}, time.Hour)
+
+	for range 3 {
+		if err := check(context.Background()); err == nil || err.Error() != "rate limited" {
+			t.Fatalf("cached check = %v, want the first result", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("check ran %d times within the TTL, want once", calls)
+	}
+}
@@ -0,0 +1,248 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Options struct {
+	BaseURL     string
+	Users       int
+	Seats       int
+	Concurrency int
+	PaymentReq  bool
+	Inventory   string
+	Timeout     time.Duration
+	// Progress, when set, receives one line per finished phase.
+	Progress io.Writer
+}
+
+type Runner struct {
+	opts   Options
+	client *http.Client
+	runID  string
+}
+
+func NewRunner(opts Options) *Runner {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = opts.Concurrency
+	transport.MaxIdleConnsPerHost = opts.Concurrency
+
+	return &Runner{
+		opts:   opts,
+		client: &http.Client{Transport: transport, Timeout: opts.Timeout},
+		runID:  strings.ReplaceAll(uuid.NewString(), "-", "")[:6],
+	}
+}
+
+// Run creates an event with opts.Seats seats, registers opts.Users users and
+// then lets every user try to book the event at the same moment.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	eventID, err := r.createEvent(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create event: %w", err)
+	}
+	r.progress("event %s created with %d seats (%s inventory)", eventID, r.opts.Seats, r.opts.Inventory)
+
+	emails, err := r.registerUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("register users: %w", err)
+	}
+	r.progress("%d users registered", len(emails))
+
+	report := &Report{
+		EventID:     eventID,
+		Seats:       r.opts.Seats,
+		Users:       len(emails),
+		Concurrency: r.opts.Concurrency,
+		Statuses:    make(map[int]int),
+		Errors:      make(map[string]int),
+	}
+
+	r.book(ctx, eventID, emails, report)
+	r.progress("%d booking requests sent in %s", report.Requests, report.Elapsed.Round(time.Millisecond))
+
+	if err := r.verify(ctx, eventID, report); err != nil {
+		return nil, fmt.Errorf("verify event: %w", err)
+	}
+
+	return report, nil
+}
+
+func (r *Runner) createEvent(ctx context.Context) (uuid.UUID, error) {
+	req := dto.CreateEventRequest{
+		Name:                 "Load test " + r.runID,
+		Date:                 time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second).Format(time.RFC3339),
+		TotalSeats:           r.opts.Seats,
+		BookingLifetimeHours: 1,
+		PaymentReq:           r.opts.PaymentReq,
+		Inventory:            r.opts.Inventory,
+	}
+
+	var resp dto.CreateEventResponse
+	if _, err := r.call(ctx, http.MethodPost, "/api/v1/events", req, http.StatusCreated, &resp); err != nil {
+		return uuid.Nil, err
+	}
+
+	return resp.Event.ID, nil
+}
+
+func (r *Runner) registerUsers(ctx context.Context) ([]string, error) {
+	emails := make([]string, r.opts.Users)
+	for i := range emails {
+		emails[i] = fmt.Sprintf("lt%s.%d@example.com", r.runID, i)
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	r.parallel(len(emails), func(i int) {
+		req := dto.CreateUserRequest{Name: "Load Test", Email: emails[i]}
+		_, err := r.call(ctx, http.MethodPost, "/api/v1/users", req, http.StatusCreated, nil)
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("user %s: %w", emails[i], err)
+			}
+			mu.Unlock()
+		}
+	})
+
+	return emails, firstErr
+}
+
+func (r *Runner) book(ctx context.Context, eventID uuid.UUID, emails []string, report *Report) {
+	path := fmt.Sprintf("/api/v1/events/%s/book", eventID)
+	latencies := make([]time.Duration, len(emails))
+	statuses := make([]int, len(emails))
+	errs := make([]error, len(emails))
+
+	start := time.Now()
+	r.parallel(len(emails), func(i int) {
+		began := time.Now()
+		statuses[i], errs[i] = r.call(ctx, http.MethodPost, path, dto.BookEventRequest{Email: emails[i]}, 0, nil)
+		latencies[i] = time.Since(began)
+	})
+	report.Elapsed = time.Since(start)
+
+	for i := range emails {
+		report.Requests++
+		if errs[i] != nil {
+			report.Errors[errs[i].Error()]++
+			continue
+		}
+		report.Statuses[statuses[i]]++
+		report.latencies = append(report.latencies, latencies[i])
+	}
+}
+
+func (r *Runner) verify(ctx context.Context, eventID uuid.UUID, report *Report) error {
+	var event dto.GetEventResponse
+	if _, err := r.call(ctx, http.MethodGet, "/api/v1/events/"+eventID.String(), nil, http.StatusOK, &event); err != nil {
+		return err
+	}
+
+	var bookings dto.ListBookingsResponse
+	if _, err := r.call(ctx, http.MethodGet, "/api/v1/events/"+eventID.String()+"/bookings", nil, http.StatusOK, &bookings); err != nil {
+		return err
+	}
+
+	report.ReservedSeats = event.Event.ReservedSeats
+	report.BookedSeats = event.Event.BookedSeats
+	for _, booking := range bookings.Bookings {
+		switch booking.Status {
+		case string(models.BookingStatusReserved):
+			report.ActualReserved++
+		case string(models.BookingStatusConfirmed):
+			report.ActualBooked++
+		}
+	}
+
+	return nil
+}
+
+// parallel calls fn for 0..n-1 from opts.Concurrency goroutines. The
+// goroutines are started before any work is handed out so that the first
+// wave of requests hits the server at the same time.
+func (r *Runner) parallel(n int, fn func(i int)) {
+	work := make(chan int)
+
+	var ready, done sync.WaitGroup
+	for range r.opts.Concurrency {
+		ready.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			ready.Done()
+			for i := range work {
+				fn(i)
+			}
+		}()
+	}
+	ready.Wait()
+
+	for i := range n {
+		work <- i
+	}
+	close(work)
+	done.Wait()
+}
+
+// call sends a JSON request. When want is not zero any other status is an
+// error; otherwise the status is returned for the caller to classify.
+func (r *Runner) call(ctx context.Context, method, path string, body any, want int, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(r.opts.BaseURL, "/")+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if want != 0 && resp.StatusCode != want {
+		return resp.StatusCode, fmt.Errorf("%s %s: unexpected status %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(data))
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("%s %s: decode response: %w", method, path, err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (r *Runner) progress(format string, args ...any) {
+	if r.opts.Progress != nil {
+		fmt.Fprintf(r.opts.Progress, format+"\n", args...)
+	}
+}
@@ -0,0 +1,120 @@
+package loadtest
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+type Report struct {
+	EventID     uuid.UUID
+	Seats       int
+	Users       int
+	Concurrency int
+
+	Requests int
+	Elapsed  time.Duration
+	Statuses map[int]int
+	Errors   map[string]int
+
+	// Counters stored on the event and the values recomputed from bookings.
+	ReservedSeats  int
+	BookedSeats    int
+	ActualReserved int
+	ActualBooked   int
+
+	latencies []time.Duration
+}
+
+func (r *Report) Succeeded() int {
+	return r.Statuses[http.StatusCreated]
+}
+
+func (r *Report) Conflicts() int {
+	return r.Statuses[http.StatusConflict]
+}
+
+func (r *Report) Busy() int {
+	return r.Statuses[http.StatusServiceUnavailable]
+}
+
+// Oversold is the number of seats handed out above capacity, judged both by
+// successful responses and by the bookings stored for the event.
+func (r *Report) Oversold() int {
+	return max(0, r.Succeeded()-r.Seats, r.ActualReserved+r.ActualBooked-r.Seats)
+}
+
+// Consistent reports whether the event counters match its active bookings and
+// every successful response is backed by one of them.
+func (r *Report) Consistent() bool {
+	return r.ReservedSeats == r.ActualReserved &&
+		r.BookedSeats == r.ActualBooked &&
+		r.ActualReserved+r.ActualBooked == r.Succeeded()
+}
+
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// Percentile returns the latency below which p percent of answered requests
+// completed, using the nearest-rank method.
+func (r *Report) Percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(r.latencies)
+	slices.Sort(sorted)
+
+	rank := int(p/100*float64(len(sorted)) + 0.5)
+	rank = min(max(rank, 1), len(sorted))
+
+	return sorted[rank-1]
+}
+
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "event:        %s (%d seats)\n", r.EventID, r.Seats)
+	fmt.Fprintf(w, "requests:     %d from %d users, concurrency %d\n", r.Requests, r.Users, r.Concurrency)
+	fmt.Fprintf(w, "elapsed:      %s\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "throughput:   %.1f req/s\n", r.Throughput())
+	fmt.Fprintf(w, "latency:      p50=%s p90=%s p99=%s max=%s\n",
+		round(r.Percentile(50)), round(r.Percentile(90)), round(r.Percentile(99)), round(r.Percentile(100)))
+
+	fmt.Fprintf(w, "booked:       %d\n", r.Succeeded())
+	fmt.Fprintf(w, "conflicts:    %d\n", r.Conflicts())
+	fmt.Fprintf(w, "busy:         %d\n", r.Busy())
+
+	codes := make([]int, 0, len(r.Statuses))
+	for code := range r.Statuses {
+		if code != http.StatusCreated && code != http.StatusConflict && code != http.StatusServiceUnavailable {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "status %d:   %d\n", code, r.Statuses[code])
+	}
+	for msg, count := range r.Errors {
+		fmt.Fprintf(w, "error:        %d x %s\n", count, msg)
+	}
+
+	fmt.Fprintf(w, "oversold:     %d\n", r.Oversold())
+	fmt.Fprintf(w, "counters:     reserved=%d/%d booked=%d/%d (stored/actual)\n",
+		r.ReservedSeats, r.ActualReserved, r.BookedSeats, r.ActualBooked)
+	if r.Consistent() {
+		fmt.Fprintln(w, "consistency:  ok")
+	} else {
+		fmt.Fprintln(w, "consistency:  MISMATCH")
+	}
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,171 @@
+package loadtest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		latencies := make([]time.Duration, len(values))
+		for i, v := range values {
+			latencies[i] = time.Duration(v) * time.Millisecond
+		}
+		return latencies
+	}
+
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		p         float64
+		want      time.Duration
+	}{
+		{"empty", nil, 50, 0},
+		{"empty p100", nil, 100, 0},
+		{"one sample p0", ms(7), 0, 7 * time.Millisecond},
+		{"one sample p50", ms(7), 50, 7 * time.Millisecond},
+		{"one sample p100", ms(7), 100, 7 * time.Millisecond},
+		{"p0 is the minimum", ms(30, 10, 20), 0, 10 * time.Millisecond},
+		{"p50 of odd count", ms(30, 10, 20), 50, 20 * time.Millisecond},
+		{"p50 of even count", ms(40, 10, 30, 20), 50, 20 * time.Millisecond},
+		{"p90 nearest rank", ms(10, 20, 30, 40, 50, 60, 70, 80, 90, 100), 90, 90 * time.Millisecond},
+		{"p99 of few samples is the maximum", ms(10, 20, 30), 99, 30 * time.Millisecond},
+		{"p100 is the maximum", ms(50, 10, 100, 20), 100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Report{latencies: tt.latencies}
+			if got := r.Percentile(tt.p); got != tt.want {
+				t.Errorf("Percentile(%v) = %s, want %s", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentileKeepsSamplesInOrder(t *testing.T) {
+	r := &Report{latencies: []time.Duration{3, 1, 2}}
+	r.Percentile(50)
+	if r.latencies[0] != 3 || r.latencies[1] != 1 || r.latencies[2] != 2 {
+		t.Errorf("Percentile sorted the recorded latencies: %v", r.latencies)
+	}
+}
+
+func TestOversoldAndConsistent(t *testing.T) {
+	tests := []struct {
+		name           string
+		report         Report
+		wantOversold   int
+		wantConsistent bool
+	}{
+		{
+			name:           "nothing booked",
+			report:         Report{Seats: 10},
+			wantOversold:   0,
+			wantConsistent: true,
+		},
+		{
+			name: "sold out exactly",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 10, http.StatusConflict: 40},
+				ReservedSeats:  10,
+				ActualReserved: 10,
+			},
+			wantOversold:   0,
+			wantConsistent: true,
+		},
+		{
+			name: "reserved and booked seats together",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 10},
+				ReservedSeats:  6,
+				BookedSeats:    4,
+				ActualReserved: 6,
+				ActualBooked:   4,
+			},
+			wantOversold:   0,
+			wantConsistent: true,
+		},
+		{
+			name: "oversold by responses",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 12},
+				ReservedSeats:  10,
+				ActualReserved: 10,
+			},
+			wantOversold:   2,
+			wantConsistent: false,
+		},
+		{
+			name: "oversold by stored bookings",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 10},
+				ReservedSeats:  10,
+				ActualReserved: 11,
+				ActualBooked:   2,
+			},
+			wantOversold:   3,
+			wantConsistent: false,
+		},
+		{
+			name: "oversold judged by the larger overshoot",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 15},
+				ReservedSeats:  12,
+				ActualReserved: 12,
+			},
+			wantOversold:   5,
+			wantConsistent: false,
+		},
+		{
+			name: "reserved counter drifted",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 5},
+				ReservedSeats:  4,
+				ActualReserved: 5,
+			},
+			wantOversold:   0,
+			wantConsistent: false,
+		},
+		{
+			name: "booked counter drifted",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 5},
+				ReservedSeats:  3,
+				BookedSeats:    3,
+				ActualReserved: 3,
+				ActualBooked:   2,
+			},
+			wantOversold:   0,
+			wantConsistent: false,
+		},
+		{
+			name: "success without a stored booking",
+			report: Report{
+				Seats:          10,
+				Statuses:       map[int]int{http.StatusCreated: 5},
+				ReservedSeats:  4,
+				ActualReserved: 4,
+			},
+			wantOversold:   0,
+			wantConsistent: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Oversold(); got != tt.wantOversold {
+				t.Errorf("Oversold() = %d, want %d", got, tt.wantOversold)
+			}
+			if got := tt.report.Consistent(); got != tt.wantConsistent {
+				t.Errorf("Consistent() = %v, want %v", got, tt.wantConsistent)
+			}
+		})
+	}
+}
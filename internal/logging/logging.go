@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"github.com/gookit/slog"
+	"maps"
+	"sync"
+)
+
+const (
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+
+	maxRequestIDLength = 128
+)
+
+type ctxKey struct{}
+
+type state struct {
+	mu     sync.Mutex
+	fields slog.M
+	err    error
+}
+
+// WithFields returns a context whose logger carries the given fields in
+// addition to the ones already attached to ctx.
+func WithFields(ctx context.Context, fields slog.M) context.Context {
+	next := &state{fields: make(slog.M)}
+
+	if parent, ok := ctx.Value(ctxKey{}).(*state); ok {
+		parent.mu.Lock()
+		maps.Copy(next.fields, parent.fields)
+		parent.mu.Unlock()
+	}
+	maps.Copy(next.fields, fields)
+
+	return context.WithValue(ctx, ctxKey{}, next)
+}
+
+// AddFields attaches fields to the logger already stored in ctx, so they are
+// also visible to whoever created that context (e.g. the access log).
+func AddFields(ctx context.Context, fields slog.M) {
+	s, ok := ctx.Value(ctxKey{}).(*state)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	maps.Copy(s.fields, fields)
+	s.mu.Unlock()
+}
+
+func SetError(ctx context.Context, err error) {
+	s, ok := ctx.Value(ctxKey{}).(*state)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func Error(ctx context.Context) error {
+	s, ok := ctx.Value(ctxKey{}).(*state)
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+func FromContext(ctx context.Context) *slog.Record {
+	s, ok := ctx.Value(ctxKey{}).(*state)
+	if !ok {
+		return slog.WithFields(slog.M{})
+	}
+
+	s.mu.Lock()
+	fields := maps.Clone(s.fields)
+	s.mu.Unlock()
+
+	return slog.WithFields(fields)
+}
+
+func RequestID(ctx context.Context) string {
+	s, ok := ctx.Value(ctxKey{}).(*state)
+	if !ok {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := s.fields[FieldRequestID].(string)
+	return id
+}
+
+// ValidRequestID reports whether a request ID sent by the client can be
+// echoed back and logged as is: it has to be short and made of letters,
+// digits, '.', '_' and '-' only.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
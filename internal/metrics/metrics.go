@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "event_booker"
+
+const (
+	OutcomeCreated       = "created"
+	OutcomeNoSeats       = "no_available_seats"
+	OutcomeAlreadyBooked = "already_booked"
+	OutcomeQuotaExceeded = "quota_exceeded"
+	OutcomeConfirmed     = "confirmed"
+	OutcomeExpired       = "expired"
+	OutcomeCancelled     = "cancelled"
+
+	IdempotencyNew        = "new"
+	IdempotencyReplayed   = "replayed"
+	IdempotencyMismatch   = "mismatch"
+	IdempotencyInProgress = "in_progress"
+
+	RateLimitIP    = "ip"
+	RateLimitUser  = "user"
+	RateLimitEvent = "event"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	GRPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "Number of gRPC calls by full method name and status code.",
+	}, []string{"method", "code"})
+
+	GRPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "gRPC call latency by full method name; streams are measured until they end.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	BookingOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "booking_outcomes_total",
+		Help:      "Number of booking operations by outcome.",
+	}, []string{"outcome"})
+
+	WorkerRunDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "worker_run_duration_seconds",
+		Help:      "Duration of expired bookings processing runs.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	WorkerProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "worker_processed_bookings_total",
+		Help:      "Number of expired bookings processed by the worker by result.",
+	}, []string{"result"})
+
+	Notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Number of notifications sent by result.",
+	}, []string{"result"})
+
+	TxConflicts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_tx_conflicts_total",
+		Help:      "Number of transactions aborted by deadlocks or lock timeouts.",
+	}, []string{"operation", "reason"})
+
+	SeatDriftEvents = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seat_counter_drift_events",
+		Help:      "Number of events whose seat counters still differ from their bookings after the last reconciliation.",
+	})
+
+	IdempotencyKeys = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "idempotency_keys_total",
+		Help:      "Number of requests carrying an Idempotency-Key header by result.",
+	}, []string{"result"})
+
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Number of requests rejected by rate limiting by bucket scope and route pattern or gRPC method.",
+	}, []string{"scope", "route"})
+
+	SeatDriftRepairs = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "seat_counter_repairs_total",
+		Help:      "Number of events whose seat counters were repaired by reconciliation.",
+	})
+)
+
+func Result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+
+	return "success"
+}
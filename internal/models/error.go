@@ -1,5 +1,16 @@
 package models
 
-type Error struct {
-	Error string `json:"error"`
+import "github.com/kstsm/wb-event-booker/internal/apperrors"
+
+// Problem is an RFC 7807 problem details object. Code is the stable
+// machine-readable identifier of the error; Errors lists the rejected fields
+// of a request that failed validation.
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code"`
+	Errors   []apperrors.FieldError `json:"errors,omitempty"`
 }
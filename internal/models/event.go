@@ -5,18 +5,126 @@ import (
 	"time"
 )
 
+// Inventory selects how the seats of an event are accounted for. Counter
+// events keep reserved/booked counters on the event row and serialise
+// bookings on it; seat events claim one pre-created row per seat and derive
+// the counters from their bookings.
+type Inventory string
+
+const (
+	InventoryCounter Inventory = "counter"
+	InventorySeats   Inventory = "seats"
+)
+
+// SalesStatus is the state of the ticket sales of an event.
+type SalesStatus string
+
+const (
+	SalesUpcoming SalesStatus = "upcoming"
+	SalesOpen     SalesStatus = "open"
+	SalesClosed   SalesStatus = "closed"
+)
+
+// EventCategory is the kind of an event. Events are filtered by it, so it is
+// picked from a fixed list, unlike the free-form tags.
+type EventCategory string
+
+const (
+	EventCategoryConcert    EventCategory = "concert"
+	EventCategoryConference EventCategory = "conference"
+	EventCategoryMeetup     EventCategory = "meetup"
+	EventCategoryWorkshop   EventCategory = "workshop"
+	EventCategoryTheatre    EventCategory = "theatre"
+	EventCategorySport      EventCategory = "sport"
+	EventCategoryExhibition EventCategory = "exhibition"
+	EventCategoryOther      EventCategory = "other"
+)
+
+var EventCategories = []EventCategory{
+	EventCategoryConcert,
+	EventCategoryConference,
+	EventCategoryMeetup,
+	EventCategoryWorkshop,
+	EventCategoryTheatre,
+	EventCategorySport,
+	EventCategoryExhibition,
+	EventCategoryOther,
+}
+
+func (c EventCategory) Valid() bool {
+	for _, category := range EventCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a bookable event running from Date until EndDate, shown in its
+// TimeZone. Tickets are on sale from SalesOpenAt, or from its creation if it
+// is nil, until SalesCloseAt. Its Description is markdown, stored and
+// returned as is; rendering it safely is up to the client.
 type Event struct {
-	ID              uuid.UUID `json:"id"`
-	Name            string    `json:"name"`
-	Date            time.Time `json:"date"`
-	TotalSeats      int       `json:"total_seats"`
-	ReservedSeats   int       `json:"reserved_seats"`
-	BookedSeats     int       `json:"booked_seats"`
-	BookingLifetime int       `json:"booking_lifetime"`
-	PaymentReq      bool      `json:"requires_payment_confirmation"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID               uuid.UUID     `json:"id"`
+	Name             string        `json:"name"`
+	Description      string        `json:"description"`
+	Category         EventCategory `json:"category"`
+	Tags             []string      `json:"tags"`
+	OrganizerContact string        `json:"organizer_contact"`
+	CoverImageURL    string        `json:"cover_image_url"`
+	VenueID          *uuid.UUID    `json:"venue_id,omitempty"`
+	Date             time.Time     `json:"date"`
+	EndDate          time.Time     `json:"end_date"`
+	TimeZone         string        `json:"time_zone"`
+	SalesOpenAt      *time.Time    `json:"sales_open_at,omitempty"`
+	SalesCloseAt     time.Time     `json:"sales_close_at"`
+	TotalSeats       int           `json:"total_seats"`
+	ReservedSeats    int           `json:"reserved_seats"`
+	BookedSeats      int           `json:"booked_seats"`
+	BookingLifetime  int           `json:"booking_lifetime"`
+	PaymentReq       bool          `json:"requires_payment_confirmation"`
+	Inventory        Inventory     `json:"inventory"`
+	CreatedAt        time.Time     `json:"created_at"`
+	CancelledAt      *time.Time    `json:"cancelled_at,omitempty"`
+}
+
+// EventFilter narrows down ListEvents. Empty fields match every event; an
+// event matches Tags if it has all of them.
+type EventFilter struct {
+	Category EventCategory
+	Tags     []string
+}
+
+func (e *Event) IsCancelled() bool {
+	return e.CancelledAt != nil
 }
 
 func (e *Event) AvailableSeats() int {
 	return e.TotalSeats - e.ReservedSeats - e.BookedSeats
 }
+
+// HasStarted reports whether the event has already started at now.
+func (e *Event) HasStarted(now time.Time) bool {
+	return !now.Before(e.Date)
+}
+
+// SalesStatus reports whether tickets for the event are on sale at now.
+// Sales of a cancelled event are closed.
+func (e *Event) SalesStatus(now time.Time) SalesStatus {
+	switch {
+	case e.IsCancelled() || !now.Before(e.SalesCloseAt):
+		return SalesClosed
+	case e.SalesOpenAt != nil && now.Before(*e.SalesOpenAt):
+		return SalesUpcoming
+	}
+	return SalesOpen
+}
+
+// Location returns the time zone of the event, UTC if it cannot be loaded.
+func (e *Event) Location() *time.Location {
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil || e.TimeZone == "" {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,14 @@
+package models
+
+type CheckStatus string
+
+const (
+	CheckStatusOK   CheckStatus = "ok"
+	CheckStatusFail CheckStatus = "fail"
+)
+
+type CheckResult struct {
+	Status     CheckStatus `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+}
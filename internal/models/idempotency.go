@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IdempotencyKey is a client-supplied key together with the request it was
+// first used for and, once that request has finished, its response.
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed reports whether the response has been stored. Until then the
+// original request is still being processed.
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
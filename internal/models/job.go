@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type JobRunStatus string
+
+const (
+	JobRunStatusSuccess JobRunStatus = "success"
+	JobRunStatusFailed  JobRunStatus = "failed"
+	JobRunStatusSkipped JobRunStatus = "skipped"
+)
+
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+type JobRun struct {
+	ID         uuid.UUID    `json:"id"`
+	JobName    string       `json:"job_name"`
+	Trigger    JobTrigger   `json:"trigger"`
+	Status     JobRunStatus `json:"status"`
+	Error      *string      `json:"error,omitempty"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at"`
+	DurationMs int64        `json:"duration_ms"`
+}
+
+type BookingStats struct {
+	Events    int `json:"events"`
+	Reserved  int `json:"reserved"`
+	Confirmed int `json:"confirmed"`
+	Cancelled int `json:"cancelled"`
+}
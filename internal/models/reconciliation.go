@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type SeatDrift struct {
+	EventID        uuid.UUID `json:"event_id"`
+	EventName      string    `json:"event_name"`
+	TotalSeats     int       `json:"total_seats"`
+	ReservedSeats  int       `json:"reserved_seats"`
+	BookedSeats    int       `json:"booked_seats"`
+	ActualReserved int       `json:"actual_reserved"`
+	ActualBooked   int       `json:"actual_booked"`
+	// With seat inventory the counters are derived from the bookings, so
+	// drift shows as seats held by cancelled bookings and as active bookings
+	// without a seat.
+	StaleSeats       int     `json:"stale_seats"`
+	UnseatedBookings int     `json:"unseated_bookings"`
+	Repaired         bool    `json:"repaired"`
+	Error            *string `json:"error,omitempty"`
+}
+
+func (d *SeatDrift) HasDrift() bool {
+	return d.ReservedSeats != d.ActualReserved || d.BookedSeats != d.ActualBooked ||
+		d.StaleSeats > 0 || d.UnseatedBookings > 0
+}
+
+type SeatReconciliation struct {
+	CheckedAt time.Time    `json:"checked_at"`
+	Repair    bool         `json:"repair"`
+	Drifts    []*SeatDrift `json:"drifts"`
+}
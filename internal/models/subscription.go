@@ -0,0 +1,16 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Subscription is a user waiting to be notified once the ticket sales of an
+// event open. NotifiedAt is set after the notification has been sent, so each
+// user is notified at most once per event.
+type Subscription struct {
+	EventID    uuid.UUID  `json:"event_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+}
@@ -5,10 +5,18 @@ import (
 	"time"
 )
 
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleAdmin UserRole = "admin"
+)
+
 type User struct {
 	ID         uuid.UUID `json:"id"`
 	Name       string    `json:"name"`
 	Email      string    `json:"email"`
 	TelegramID *int64    `json:"telegram_id,omitempty"`
+	Role       UserRole  `json:"role"`
 	CreatedAt  time.Time `json:"created_at"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Venue is a place events are held at. An event at a venue takes it for the
+// whole [Date, EndDate) range, so events at the same venue cannot overlap.
+// TimeZone is an IANA time zone name; DefaultCapacity is the number of seats
+// an event at the venue gets unless it sets its own.
+type Venue struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Address         string    `json:"address"`
+	Latitude        float64   `json:"latitude"`
+	Longitude       float64   `json:"longitude"`
+	TimeZone        string    `json:"time_zone"`
+	DefaultCapacity int       `json:"default_capacity"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
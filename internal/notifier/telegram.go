@@ -4,18 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	"github.com/gookit/slog"
 	"github.com/kstsm/wb-event-booker/internal/config"
 	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
 	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 type NotifierI interface {
 	SendNotification(ctx context.Context, userID uuid.UUID, telegramID int64, message string) error
+	Ping(ctx context.Context) error
 }
 
 type TelegramNotifier struct {
@@ -38,7 +44,16 @@ func (t *TelegramNotifier) SendNotification(
 	telegramID int64,
 	message string,
 ) error {
+	ctx, span := tracing.Start(ctx, "Telegram.SendNotification", trace.WithSpanKind(trace.SpanKindClient))
+	err := t.send(ctx, userID, telegramID, message)
+	tracing.End(span, err)
 
+	metrics.Notifications.WithLabelValues(metrics.Result(err)).Inc()
+
+	return err
+}
+
+func (t *TelegramNotifier) send(ctx context.Context, userID uuid.UUID, telegramID int64, message string) error {
 	if t.cfg.BotToken == "" {
 		return fmt.Errorf("telegram bot token is not configured")
 	}
@@ -53,19 +68,19 @@ func (t *TelegramNotifier) SendNotification(
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.cfg.BotToken)
+	endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.cfg.BotToken)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	slog.Infof("Sending Telegram notification to user: user_id=%v, telegram_id=%d", userID, telegramID)
+	logging.FromContext(ctx).Infof("Sending Telegram notification to user: user_id=%v, telegram_id=%d", userID, telegramID)
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return requestError(err)
 	}
 	defer resp.Body.Close()
 
@@ -88,3 +103,43 @@ func (t *TelegramNotifier) SendNotification(
 
 	return nil
 }
+
+func (t *TelegramNotifier) Ping(ctx context.Context) error {
+	if t.cfg.BotToken == "" {
+		return fmt.Errorf("telegram bot token is not configured")
+	}
+
+	endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/getMe", t.cfg.BotToken)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return requestError(err)
+	}
+	defer resp.Body.Close()
+
+	var telegramResp dto.TelegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&telegramResp); err != nil {
+		return fmt.Errorf("telegram API error: status %d", resp.StatusCode)
+	}
+
+	if !telegramResp.OK {
+		return fmt.Errorf("telegram API error: %s", telegramResp.Description)
+	}
+
+	return nil
+}
+
+// requestError drops the request URL from a transport error: the URL carries
+// the bot token, and the error ends up in logs, traces and health checks.
+func requestError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return fmt.Errorf("failed to send request: %w", err)
+}
@@ -0,0 +1,39 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRequestErrorHidesToken(t *testing.T) {
+	const token = "123456:secret-token"
+	n := &TelegramNotifier{
+		cfg:    config.TelegramConfig{BotToken: token},
+		client: &http.Client{Transport: failingTransport{}},
+	}
+
+	errs := map[string]error{
+		"Ping":             n.Ping(context.Background()),
+		"SendNotification": n.SendNotification(context.Background(), uuid.New(), 42, "hello"),
+	}
+	for name, err := range errs {
+		if err == nil || !strings.Contains(err.Error(), "connection refused") {
+			t.Errorf("%s = %v, want the transport error", name, err)
+			continue
+		}
+		if strings.Contains(err.Error(), token) {
+			t.Errorf("%s error %q contains the bot token", name, err)
+		}
+	}
+}
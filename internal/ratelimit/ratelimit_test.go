@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestLimiter(t *testing.T) {
+	c := &clock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := newLimiter(60, 3, c.Now)
+
+	for i := range 3 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d within the burst was rejected", i+1)
+		}
+	}
+
+	ok, wait := l.Allow("a")
+	if ok {
+		t.Fatalf("request above the burst was allowed")
+	}
+	if wait != time.Second {
+		t.Fatalf("wait = %s, want 1s", wait)
+	}
+
+	if ok, _ := l.Allow("b"); !ok {
+		t.Fatalf("keys must not share a bucket")
+	}
+
+	c.Advance(500 * time.Millisecond)
+	if ok, wait := l.Allow("a"); ok || wait != 500*time.Millisecond {
+		t.Fatalf("half a token: ok=%v wait=%s, want false and 500ms", ok, wait)
+	}
+
+	c.Advance(500 * time.Millisecond)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Fatalf("refilled token was not granted")
+	}
+
+	c.Advance(time.Hour)
+	for i := range 3 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("bucket must refill up to the burst, request %d rejected", i+1)
+		}
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Fatalf("bucket must not refill above the burst")
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	c := &clock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := newLimiter(60, 2, c.Now)
+
+	l.Allow("idle")
+	c.Advance(sweepInterval)
+	l.Allow("busy")
+
+	if _, ok := l.buckets["idle"]; ok {
+		t.Fatalf("a full bucket must be dropped by the sweep")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Fatalf("a bucket in use must be kept")
+	}
+}
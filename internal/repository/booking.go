@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
 )
 
 func (r *Repository) GetBookingsByEventID(ctx context.Context, eventID uuid.UUID) ([]*models.Booking, error) {
@@ -53,7 +55,7 @@ func (r *Repository) GetBookingByID(ctx context.Context, id uuid.UUID) (*models.
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("GetBookingByID: booking not found")
+			return nil, apperrors.BookingNotFound
 		}
 		return nil, fmt.Errorf("QueryRow-GetBookingByID query: %w", err)
 	}
@@ -110,3 +112,43 @@ func (r *Repository) countUserBookings(ctx context.Context, tx pgx.Tx, eventID,
 
 	return exists, nil
 }
+
+func (r *Repository) GetBookingsForReminder(ctx context.Context, until time.Time) ([]*models.Booking, error) {
+	rows, err := r.conn.Query(ctx, getBookingsForReminderQuery, until)
+	if err != nil {
+		return nil, fmt.Errorf("Query-GetBookingsForReminder query: %w", err)
+	}
+	defer rows.Close()
+
+	var bookings []*models.Booking
+	for rows.Next() {
+		booking := new(models.Booking)
+		if err := rows.Scan(
+			&booking.ID,
+			&booking.EventID,
+			&booking.UserID,
+			&booking.Status,
+			&booking.Deadline,
+			&booking.CreatedAt,
+			&booking.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("GetBookingsForReminder scan: %w", err)
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetBookingsForReminder rows.Err: %w", err)
+	}
+
+	return bookings, nil
+}
+
+func (r *Repository) MarkBookingReminded(ctx context.Context, bookingID uuid.UUID) error {
+	_, err := r.conn.Exec(ctx, markBookingRemindedQuery, bookingID)
+	if err != nil {
+		return fmt.Errorf("Exec-MarkBookingReminded: %w", err)
+	}
+
+	return nil
+}
@@ -5,33 +5,52 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	"github.com/gookit/slog"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/models"
 	"time"
 )
 
-func (r *Repository) BookEventWithTransaction(ctx context.Context, eventID, userID uuid.UUID) (*models.Booking, error) {
-	tx, err := r.conn.BeginTx(context.Background(), pgx.TxOptions{})
+// BookEventWithTransaction books the event for the user. A positive
+// maxReservations caps the unpaid reservations the user may hold at once.
+func (r *Repository) BookEventWithTransaction(ctx context.Context, eventID, userID uuid.UUID, maxReservations int) (*models.Booking, error) {
+	var booking *models.Booking
+
+	err := r.withTx(ctx, "BookEventWithTransaction", func(tx pgx.Tx) error {
+		var inventory models.Inventory
+		err := tx.QueryRow(ctx, selectEventInventoryQuery, eventID).Scan(&inventory)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return apperrors.EventNotFound
+			}
+			return fmt.Errorf("QueryRow-BookEventWithTransaction: %w", err)
+		}
+
+		if inventory == models.InventorySeats {
+			booking, err = r.bookSeat(ctx, tx, eventID, userID, maxReservations)
+		} else {
+			booking, err = r.bookCounter(ctx, tx, eventID, userID, maxReservations)
+		}
+
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("BeginTx-BookEventWithTransaction:: %w", err)
+		return nil, err
 	}
 
-	defer func() {
-		rbErr := tx.Rollback(context.Background())
-		if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
-			slog.Errorf("Rollback-BookEventWithTransaction: %v", rbErr)
-		}
-	}()
+	return booking, nil
+}
 
+// bookCounter serialises bookings of the event on its row lock.
+func (r *Repository) bookCounter(ctx context.Context, tx pgx.Tx, eventID, userID uuid.UUID, maxReservations int) (*models.Booking, error) {
 	event, err := r.getEventForUpdate(ctx, tx, eventID)
 	if err != nil {
-		return nil, fmt.Errorf("getEventForUpdate-BookEventWithTransaction: %w", err)
+		return nil, fmt.Errorf("getEventForUpdate-bookCounter: %w", err)
 	}
 
-	if event.Date.Before(time.Now()) {
-		return nil, apperrors.EventExpired
+	if err = checkBookable(event); err != nil {
+		return nil, err
 	}
 
 	if event.TotalSeats-event.ReservedSeats-event.BookedSeats <= 0 {
@@ -40,139 +59,290 @@ func (r *Repository) BookEventWithTransaction(ctx context.Context, eventID, user
 
 	exists, err := r.countUserBookings(ctx, tx, eventID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("countUserBookings-BookEventWithTransaction: %w", err)
+		return nil, fmt.Errorf("countUserBookings-bookCounter: %w", err)
 	}
 	if exists > 0 {
 		return nil, apperrors.UserAlreadyBookedThisEvent
 	}
 
-	status := models.BookingStatusConfirmed
-	deadline := event.Date
-	seatQuery := updateBookedSeatsQuery
+	if event.PaymentReq && maxReservations > 0 {
+		if err = r.checkReservationQuota(ctx, tx, userID, maxReservations); err != nil {
+			return nil, err
+		}
+	}
 
+	booking := newBooking(event, userID)
+
+	seatQuery := updateBookedSeatsQuery
 	if event.PaymentReq {
-		status = models.BookingStatusReserved
-		deadline = time.Now().Add(time.Duration(event.BookingLifetime) * time.Minute).UTC()
 		seatQuery = updateReservedSeatsQuery
 	}
 
-	booking := &models.Booking{
-		ID:        uuid.New(),
-		EventID:   eventID,
-		UserID:    userID,
-		Status:    status,
-		Deadline:  deadline.UTC(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-	}
-
 	if _, err = tx.Exec(ctx, seatQuery, eventID); err != nil {
-		return nil, fmt.Errorf("updateSeats-BookEventWithTransaction: %w", err)
+		return nil, fmt.Errorf("updateSeats-bookCounter: %w", err)
 	}
 
 	if err = r.insertBooking(ctx, tx, booking); err != nil {
-		return nil, fmt.Errorf("insertBooking-BookEventWithTransaction: %w", err)
-	}
-
-	if err = tx.Commit(context.Background()); err != nil {
-		return nil, fmt.Errorf("Commit-BookEventWithTransaction:: %w", err)
+		return nil, fmt.Errorf("insertBooking-bookCounter: %w", err)
 	}
 
 	return booking, nil
 }
 
-func (r *Repository) ConfirmBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
-	tx, err := r.conn.BeginTx(context.Background(), pgx.TxOptions{})
+// bookSeat claims a free seat row instead of locking the event, so bookings
+// of the same event do not wait for each other. A second active booking of
+// the same user is rejected by a unique index rather than by the lock. The
+// seat is claimed first so that a sold-out event is reported as such to
+// everyone, as with counter inventory.
+func (r *Repository) bookSeat(ctx context.Context, tx pgx.Tx, eventID, userID uuid.UUID, maxReservations int) (*models.Booking, error) {
+	event := &models.Event{ID: eventID}
+
+	err := tx.QueryRow(ctx, selectEventForBookingQuery, eventID).Scan(
+		&event.Date,
+		&event.SalesOpenAt,
+		&event.SalesCloseAt,
+		&event.BookingLifetime,
+		&event.PaymentReq,
+		&event.CancelledAt,
+	)
 	if err != nil {
-		return fmt.Errorf("BeginTx-ConfirmBookingWithTransaction: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.EventNotFound
+		}
+		return nil, fmt.Errorf("QueryRow-bookSeat: %w", err)
 	}
 
-	defer func() {
-		rbErr := tx.Rollback(context.Background())
-		if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
-			slog.Errorf("Rollback-ConfirmBookingWithTransaction: %v", rbErr)
-		}
-	}()
+	if err = checkBookable(event); err != nil {
+		return nil, err
+	}
+
+	booking := newBooking(event, userID)
 
-	booking, err := r.getBookingInTx(ctx, tx, bookingID)
+	claimed, err := r.claimSeat(ctx, tx, eventID, booking.ID)
 	if err != nil {
-		return fmt.Errorf("getBookingInTx-ConfirmBookingWithTransaction: %w", err)
+		return nil, fmt.Errorf("claimSeat-bookSeat: %w", err)
 	}
-
-	if booking.Status != models.BookingStatusReserved {
-		return apperrors.BookingNotReserved
+	if !claimed {
+		return nil, apperrors.NoAvailableSeats
 	}
 
-	if time.Now().After(booking.Deadline) {
-		return apperrors.BookingDeadlinePassed
+	exists, err := r.countUserBookings(ctx, tx, eventID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("countUserBookings-bookSeat: %w", err)
 	}
-
-	if err = r.updateBookingStatus(ctx, tx, string(models.BookingStatusConfirmed), bookingID); err != nil {
-		return fmt.Errorf("updateBookingStatus-ConfirmBookingWithTransaction: %w", err)
+	if exists > 0 {
+		return nil, apperrors.UserAlreadyBookedThisEvent
 	}
 
-	if err = r.updateEventSeatsReservedToBooked(ctx, tx, booking.EventID); err != nil {
-		return fmt.Errorf("updateEventSeatsReservedToBooked-ConfirmBookingWithTransaction: %w", err)
+	if event.PaymentReq && maxReservations > 0 {
+		if err = r.checkReservationQuota(ctx, tx, userID, maxReservations); err != nil {
+			return nil, err
+		}
 	}
 
-	if err = tx.Commit(context.Background()); err != nil {
-		return fmt.Errorf("Commit-ConfirmBookingWithTransaction: %w", err)
+	if err = r.insertBooking(ctx, tx, booking); err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" && pgError.ConstraintName == "idx_bookings_event_user_active" {
+			return nil, apperrors.UserAlreadyBookedThisEvent
+		}
+		return nil, fmt.Errorf("insertBooking-bookSeat: %w", err)
 	}
 
-	return nil
+	return booking, nil
 }
 
-func (r *Repository) CancelExpiredBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
-	tx, err := r.conn.BeginTx(context.Background(), pgx.TxOptions{})
-	if err != nil {
-		return fmt.Errorf("BeginTx-CancelExpiredBookingWithTransaction: %w", err)
-	}
+// claimSeat assigns a free seat to the booking. Seats being claimed by other
+// transactions are skipped; only when every free seat is taken that way does
+// it wait for one, so a claim that rolls back does not look like a sell-out.
+// The seat waited for may end up taken while others are still free, so the
+// claim is repeated until it succeeds or no free seat is left.
+func (r *Repository) claimSeat(ctx context.Context, tx pgx.Tx, eventID, bookingID uuid.UUID) (bool, error) {
+	for {
+		for _, query := range []string{claimSeatQuery, claimSeatWaitQuery} {
+			tag, err := tx.Exec(ctx, query, eventID, bookingID)
+			if err != nil {
+				return false, fmt.Errorf("Exec-claimSeat: %w", err)
+			}
+			if tag.RowsAffected() > 0 {
+				return true, nil
+			}
+		}
 
-	defer func() {
-		rbErr := tx.Rollback(context.Background())
-		if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
-			slog.Errorf("Rollback-CancelExpiredBookingWithTransaction: %v", rbErr)
+		var free bool
+		if err := tx.QueryRow(ctx, hasFreeSeatQuery, eventID).Scan(&free); err != nil {
+			return false, fmt.Errorf("QueryRow-claimSeat: %w", err)
 		}
-	}()
+		if !free {
+			return false, nil
+		}
+	}
+}
 
-	booking, err := r.getBookingInTx(ctx, tx, bookingID)
-	if err != nil {
-		return fmt.Errorf("getBookingInTx-CancelExpiredBookingWithTransaction: %w", err)
+// checkReservationQuota counts the user's reservations that are still awaiting
+// payment. Reservations past their deadline do not count even if the expiry
+// job has not cancelled them yet.
+func (r *Repository) checkReservationQuota(ctx context.Context, tx pgx.Tx, userID uuid.UUID, maxReservations int) error {
+	if _, err := tx.Exec(ctx, lockUserQuery, userID); err != nil {
+		return fmt.Errorf("Exec-lockUser: %w", err)
 	}
 
-	if booking.Status != models.BookingStatusReserved {
-		return apperrors.BookingNotReserved
+	var reserved int
+	if err := tx.QueryRow(ctx, countUserReservationsQuery, userID).Scan(&reserved); err != nil {
+		return fmt.Errorf("QueryRow-checkReservationQuota: %w", err)
+	}
+	if reserved >= maxReservations {
+		return apperrors.TooManyActiveReservations
 	}
 
-	if err = r.updateBookingStatus(ctx, tx, string(models.BookingStatusCancelled), bookingID); err != nil {
-		return fmt.Errorf("updateBookingStatus-CancelExpiredBookingWithTransaction: %w", err)
+	return nil
+}
 
+func checkBookable(event *models.Event) error {
+	if event.IsCancelled() {
+		return apperrors.EventCancelled
 	}
 
-	if err = r.decreaseBookingSeats(ctx, tx, booking.EventID); err != nil {
-		return err
+	now := time.Now()
+	if event.HasStarted(now) {
+		return apperrors.EventExpired
 	}
 
-	if err = tx.Commit(context.Background()); err != nil {
-		return fmt.Errorf("Commit-CancelExpiredBookingWithTransaction: %w", err)
+	switch event.SalesStatus(now) {
+	case models.SalesUpcoming:
+		return apperrors.SalesNotOpen
+	case models.SalesClosed:
+		return apperrors.SalesClosed
 	}
 
 	return nil
 }
 
+func newBooking(event *models.Event, userID uuid.UUID) *models.Booking {
+	status := models.BookingStatusConfirmed
+	deadline := event.Date
+
+	if event.PaymentReq {
+		status = models.BookingStatusReserved
+		deadline = time.Now().Add(time.Duration(event.BookingLifetime) * time.Minute).UTC()
+	}
+
+	return &models.Booking{
+		ID:        uuid.New(),
+		EventID:   event.ID,
+		UserID:    userID,
+		Status:    status,
+		Deadline:  deadline.UTC(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+func (r *Repository) ConfirmBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	return r.withTx(ctx, "ConfirmBookingWithTransaction", func(tx pgx.Tx) error {
+		booking, err := r.getBookingInTx(ctx, tx, bookingID)
+		if err != nil {
+			return fmt.Errorf("getBookingInTx-ConfirmBookingWithTransaction: %w", err)
+		}
+
+		if booking.Status != models.BookingStatusReserved {
+			return apperrors.BookingNotReserved
+		}
+
+		if time.Now().After(booking.Deadline) {
+			return apperrors.BookingDeadlinePassed
+		}
+
+		if err = r.updateBookingStatus(ctx, tx, string(models.BookingStatusConfirmed), bookingID); err != nil {
+			return fmt.Errorf("updateBookingStatus-ConfirmBookingWithTransaction: %w", err)
+		}
+
+		if err = r.updateEventSeatsReservedToBooked(ctx, tx, booking.EventID); err != nil {
+			return fmt.Errorf("updateEventSeatsReservedToBooked-ConfirmBookingWithTransaction: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) CancelExpiredBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	return r.withTx(ctx, "CancelExpiredBookingWithTransaction", func(tx pgx.Tx) error {
+		booking, err := r.getBookingInTx(ctx, tx, bookingID)
+		if err != nil {
+			return fmt.Errorf("getBookingInTx-CancelExpiredBookingWithTransaction: %w", err)
+		}
+
+		if booking.Status != models.BookingStatusReserved {
+			return apperrors.BookingNotReserved
+		}
+
+		if err = r.updateBookingStatus(ctx, tx, string(models.BookingStatusCancelled), bookingID); err != nil {
+			return fmt.Errorf("updateBookingStatus-CancelExpiredBookingWithTransaction: %w", err)
+		}
+
+		if _, err = tx.Exec(ctx, releaseBookingSeatQuery, bookingID); err != nil {
+			return fmt.Errorf("Exec-releaseBookingSeat: %w", err)
+		}
+
+		return r.decreaseBookingSeats(ctx, tx, booking.EventID)
+	})
+}
+
+func (r *Repository) CancelBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	return r.withTx(ctx, "CancelBookingWithTransaction", func(tx pgx.Tx) error {
+		booking, err := r.getBookingInTx(ctx, tx, bookingID)
+		if err != nil {
+			return fmt.Errorf("getBookingInTx-CancelBookingWithTransaction: %w", err)
+		}
+
+		seatQuery := decreaseBookingSeatsQuery
+		switch booking.Status {
+		case models.BookingStatusCancelled:
+			return apperrors.BookingAlreadyCancelled
+		case models.BookingStatusConfirmed:
+			seatQuery = decreaseBookedSeatsQuery
+		}
+
+		if err = r.updateBookingStatus(ctx, tx, string(models.BookingStatusCancelled), bookingID); err != nil {
+			return fmt.Errorf("updateBookingStatus-CancelBookingWithTransaction: %w", err)
+		}
+
+		if _, err = tx.Exec(ctx, releaseBookingSeatQuery, bookingID); err != nil {
+			return fmt.Errorf("Exec-releaseBookingSeat: %w", err)
+		}
+
+		if _, err = tx.Exec(ctx, seatQuery, booking.EventID); err != nil {
+			return fmt.Errorf("Exec-decreaseSeats: %w", err)
+		}
+
+		return nil
+	})
+}
+
 func (r *Repository) getEventForUpdate(ctx context.Context, tx pgx.Tx, eventID uuid.UUID) (*models.Event, error) {
 	var event models.Event
 
 	err := tx.QueryRow(ctx, selectEventForUpdateQuery, eventID).Scan(
 		&event.ID,
 		&event.Name,
+		&event.Description,
+		&event.Category,
+		&event.Tags,
+		&event.OrganizerContact,
+		&event.CoverImageURL,
+		&event.VenueID,
 		&event.Date,
+		&event.EndDate,
+		&event.TimeZone,
+		&event.SalesOpenAt,
+		&event.SalesCloseAt,
 		&event.TotalSeats,
 		&event.ReservedSeats,
 		&event.BookedSeats,
 		&event.BookingLifetime,
 		&event.PaymentReq,
+		&event.Inventory,
 		&event.CreatedAt,
+		&event.CancelledAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -213,6 +383,10 @@ func (r *Repository) insertBooking(ctx context.Context, tx pgx.Tx, b *models.Boo
 		b.UpdatedAt,
 	)
 	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == foreignKeyViolation && pgError.ConstraintName == "bookings_user_id_fkey" {
+			return apperrors.UserNotFound
+		}
 		return fmt.Errorf("Exec-insertBooking: %w", err)
 	}
 
@@ -239,3 +413,40 @@ func (r *Repository) getBookingInTx(ctx context.Context, tx pgx.Tx, bookingID uu
 
 	return &booking, nil
 }
+
+func (r *Repository) CancelEventWithTransaction(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	var cancelled int64
+
+	err := r.withTx(ctx, "CancelEventWithTransaction", func(tx pgx.Tx) error {
+		event, err := r.getEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return fmt.Errorf("getEventForUpdate-CancelEventWithTransaction: %w", err)
+		}
+
+		if event.IsCancelled() {
+			return apperrors.EventCancelled
+		}
+
+		tag, err := tx.Exec(ctx, cancelEventBookingsQuery, eventID)
+		if err != nil {
+			return fmt.Errorf("Exec-cancelEventBookings: %w", err)
+		}
+
+		if _, err = tx.Exec(ctx, releaseEventSeatsQuery, eventID); err != nil {
+			return fmt.Errorf("Exec-releaseEventSeats: %w", err)
+		}
+
+		if _, err = tx.Exec(ctx, cancelEventQuery, eventID); err != nil {
+			return fmt.Errorf("Exec-cancelEvent: %w", err)
+		}
+
+		cancelled = tag.RowsAffected()
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return cancelled, nil
+}
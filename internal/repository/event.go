@@ -6,24 +6,61 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/models"
 )
 
 func (r *Repository) CreateEvent(ctx context.Context, event *models.Event) error {
-	_, err := r.conn.Exec(ctx, createEventQuery,
-		event.ID,
-		event.Name,
-		event.Date,
-		event.TotalSeats,
-		event.BookingLifetime,
-		event.PaymentReq,
-		event.CreatedAt)
-	if err != nil {
-		return fmt.Errorf("Exec-CreateEvent: %w", err)
+	// A nil slice would be sent as NULL.
+	tags := event.Tags
+	if tags == nil {
+		tags = []string{}
 	}
 
-	return nil
+	return r.withTx(ctx, "CreateEvent", func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, createEventQuery,
+			event.ID,
+			event.Name,
+			event.Description,
+			event.Category,
+			tags,
+			event.OrganizerContact,
+			event.CoverImageURL,
+			event.VenueID,
+			event.Date,
+			event.EndDate,
+			event.TimeZone,
+			event.SalesOpenAt,
+			event.SalesCloseAt,
+			event.TotalSeats,
+			event.BookingLifetime,
+			event.PaymentReq,
+			event.Inventory,
+			event.CreatedAt)
+		if err != nil {
+			var pgError *pgconn.PgError
+			if errors.As(err, &pgError) {
+				switch pgError.ConstraintName {
+				case "events_venue_overlap":
+					return apperrors.VenueUnavailable
+				case "events_venue_id_fkey":
+					return apperrors.VenueNotFound
+				}
+			}
+			return fmt.Errorf("Exec-CreateEvent: %w", err)
+		}
+
+		if event.Inventory != models.InventorySeats {
+			return nil
+		}
+
+		if _, err = tx.Exec(ctx, createEventSeatsQuery, event.ID, event.TotalSeats); err != nil {
+			return fmt.Errorf("Exec-createEventSeats: %w", err)
+		}
+
+		return nil
+	})
 }
 
 func (r *Repository) GetEventByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
@@ -32,13 +69,25 @@ func (r *Repository) GetEventByID(ctx context.Context, id uuid.UUID) (*models.Ev
 	err := r.conn.QueryRow(ctx, getEventByIDQuery, id).Scan(
 		&event.ID,
 		&event.Name,
+		&event.Description,
+		&event.Category,
+		&event.Tags,
+		&event.OrganizerContact,
+		&event.CoverImageURL,
+		&event.VenueID,
 		&event.Date,
+		&event.EndDate,
+		&event.TimeZone,
+		&event.SalesOpenAt,
+		&event.SalesCloseAt,
 		&event.TotalSeats,
 		&event.ReservedSeats,
 		&event.BookedSeats,
 		&event.BookingLifetime,
 		&event.PaymentReq,
+		&event.Inventory,
 		&event.CreatedAt,
+		&event.CancelledAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -50,8 +99,14 @@ func (r *Repository) GetEventByID(ctx context.Context, id uuid.UUID) (*models.Ev
 	return &event, nil
 }
 
-func (r *Repository) ListEvents(ctx context.Context) ([]*models.Event, error) {
-	rows, err := r.conn.Query(ctx, listEventsQuery)
+func (r *Repository) ListEvents(ctx context.Context, filter models.EventFilter) ([]*models.Event, error) {
+	// Every array contains the empty one, so no tags match every event.
+	tags := filter.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	rows, err := r.conn.Query(ctx, listEventsQuery, string(filter.Category), tags)
 	if err != nil {
 		return nil, fmt.Errorf("Query-listEvents: %w", err)
 	}
@@ -63,13 +118,25 @@ func (r *Repository) ListEvents(ctx context.Context) ([]*models.Event, error) {
 		if err := rows.Scan(
 			&event.ID,
 			&event.Name,
+			&event.Description,
+			&event.Category,
+			&event.Tags,
+			&event.OrganizerContact,
+			&event.CoverImageURL,
+			&event.VenueID,
 			&event.Date,
+			&event.EndDate,
+			&event.TimeZone,
+			&event.SalesOpenAt,
+			&event.SalesCloseAt,
 			&event.TotalSeats,
 			&event.ReservedSeats,
 			&event.BookedSeats,
 			&event.BookingLifetime,
 			&event.PaymentReq,
+			&event.Inventory,
 			&event.CreatedAt,
+			&event.CancelledAt,
 		); err != nil {
 			return nil, fmt.Errorf("Scan-listEvents: %w", err)
 		}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+func (r *Repository) AcquireIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	tag, err := r.conn.Exec(ctx, acquireIdempotencyKeyQuery,
+		key.Key,
+		key.Fingerprint,
+		key.CreatedAt,
+		key.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Exec-AcquireIdempotencyKey: %w", err)
+	}
+
+	if tag.RowsAffected() > 0 {
+		return nil, nil
+	}
+
+	var stored models.IdempotencyKey
+	err = r.conn.QueryRow(ctx, getIdempotencyKeyQuery, key.Key).Scan(
+		&stored.Key,
+		&stored.Fingerprint,
+		&stored.StatusCode,
+		&stored.ContentType,
+		&stored.Body,
+		&stored.CreatedAt,
+		&stored.ExpiresAt,
+	)
+	if err != nil {
+		// The request holding the key failed and released it in between.
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.IdempotencyKeyInProgress
+		}
+		return nil, fmt.Errorf("QueryRow-AcquireIdempotencyKey: %w", err)
+	}
+
+	return &stored, nil
+}
+
+func (r *Repository) CompleteIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) error {
+	_, err := r.conn.Exec(ctx, completeIdempotencyKeyQuery, key.Key, key.StatusCode, key.ContentType, key.Body)
+	if err != nil {
+		return fmt.Errorf("Exec-CompleteIdempotencyKey: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	_, err := r.conn.Exec(ctx, deleteIdempotencyKeyQuery, key)
+	if err != nil {
+		return fmt.Errorf("Exec-DeleteIdempotencyKey: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.conn.Exec(ctx, deleteIdempotencyKeysBeforeQuery, before)
+	if err != nil {
+		return 0, fmt.Errorf("Exec-DeleteIdempotencyKeysBefore: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+func (r *Repository) CreateJobRun(ctx context.Context, run *models.JobRun) error {
+	_, err := r.conn.Exec(ctx, createJobRunQuery,
+		run.ID,
+		run.JobName,
+		run.Trigger,
+		run.Status,
+		run.Error,
+		run.StartedAt,
+		run.FinishedAt,
+		run.DurationMs,
+	)
+	if err != nil {
+		return fmt.Errorf("Exec-CreateJobRun: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListJobRuns(ctx context.Context, limit int) ([]*models.JobRun, error) {
+	rows, err := r.conn.Query(ctx, listJobRunsQuery, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Query-ListJobRuns: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*models.JobRun
+	for rows.Next() {
+		run := new(models.JobRun)
+		if err := rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.Trigger,
+			&run.Status,
+			&run.Error,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.DurationMs,
+		); err != nil {
+			return nil, fmt.Errorf("Scan-ListJobRuns: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Rows-ListJobRuns: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (r *Repository) DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.conn.Exec(ctx, deleteJobRunsBeforeQuery, before)
+	if err != nil {
+		return 0, fmt.Errorf("Exec-DeleteJobRunsBefore: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *Repository) GetBookingStats(ctx context.Context) (*models.BookingStats, error) {
+	var stats models.BookingStats
+
+	err := r.conn.QueryRow(ctx, getBookingStatsQuery).Scan(
+		&stats.Events,
+		&stats.Reserved,
+		&stats.Confirmed,
+		&stats.Cancelled,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow-GetBookingStats: %w", err)
+	}
+
+	return &stats, nil
+}
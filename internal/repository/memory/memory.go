@@ -0,0 +1,870 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+type bookingRow struct {
+	models.Booking
+	reminderSentAt *time.Time
+}
+
+// Repository keeps all data in maps guarded by a single mutex. Every
+// *WithTransaction method holds the lock for its whole duration, which is a
+// stricter version of the SELECT ... FOR UPDATE row locks used by the
+// Postgres implementation: the same invariants hold, only with less
+// concurrency. For the same reason both inventory modes behave identically.
+type Repository struct {
+	mu       sync.RWMutex
+	events   map[uuid.UUID]*models.Event
+	venues   map[uuid.UUID]*models.Venue
+	users    map[uuid.UUID]*models.User
+	bookings map[uuid.UUID]*bookingRow
+	jobRuns  []*models.JobRun
+	keys     map[string]*models.IdempotencyKey
+	// subscriptions are kept in the order they were created.
+	subscriptions []*models.Subscription
+}
+
+func NewRepository() repository.RepositoryI {
+	return &Repository{
+		events:   make(map[uuid.UUID]*models.Event),
+		venues:   make(map[uuid.UUID]*models.Venue),
+		users:    make(map[uuid.UUID]*models.User),
+		bookings: make(map[uuid.UUID]*bookingRow),
+		keys:     make(map[string]*models.IdempotencyKey),
+	}
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, event *models.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.events[event.ID]; ok {
+		return fmt.Errorf("CreateEvent: event %s already exists", event.ID)
+	}
+
+	if event.VenueID != nil {
+		if _, ok := r.venues[*event.VenueID]; !ok {
+			return apperrors.VenueNotFound
+		}
+		for _, other := range r.events {
+			if other.VenueID != nil && *other.VenueID == *event.VenueID && !other.IsCancelled() &&
+				other.Date.Before(event.EndDate) && event.Date.Before(other.EndDate) {
+				return apperrors.VenueUnavailable
+			}
+		}
+	}
+
+	stored := copyEvent(event)
+	stored.ReservedSeats = 0
+	stored.BookedSeats = 0
+	stored.CancelledAt = nil
+	r.events[event.ID] = stored
+
+	return nil
+}
+
+func (r *Repository) GetEventByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	event, ok := r.events[id]
+	if !ok {
+		return nil, apperrors.EventNotFound
+	}
+
+	return copyEvent(event), nil
+}
+
+func (r *Repository) ListEvents(ctx context.Context, filter models.EventFilter) ([]*models.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []*models.Event
+	for _, event := range r.events {
+		if matchesFilter(event, filter) {
+			events = append(events, copyEvent(event))
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Date.Equal(events[j].Date) {
+			return events[i].Date.Before(events[j].Date)
+		}
+		return events[i].ID.String() < events[j].ID.String()
+	})
+
+	return events, nil
+}
+
+func (r *Repository) CreateVenue(ctx context.Context, venue *models.Venue) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.venues[venue.ID]; ok {
+		return fmt.Errorf("CreateVenue: venue %s already exists", venue.ID)
+	}
+	c := *venue
+	r.venues[venue.ID] = &c
+
+	return nil
+}
+
+func (r *Repository) GetVenueByID(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	venue, ok := r.venues[id]
+	if !ok {
+		return nil, apperrors.VenueNotFound
+	}
+	c := *venue
+
+	return &c, nil
+}
+
+func (r *Repository) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var venues []*models.Venue
+	for _, venue := range r.venues {
+		c := *venue
+		venues = append(venues, &c)
+	}
+
+	sort.Slice(venues, func(i, j int) bool {
+		if venues[i].Name != venues[j].Name {
+			return venues[i].Name < venues[j].Name
+		}
+		return venues[i].ID.String() < venues[j].ID.String()
+	})
+
+	return venues, nil
+}
+
+func (r *Repository) UpdateVenue(ctx context.Context, venue *models.Venue) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.venues[venue.ID]
+	if !ok {
+		return apperrors.VenueNotFound
+	}
+	c := *venue
+	c.CreatedAt = stored.CreatedAt
+	r.venues[venue.ID] = &c
+
+	return nil
+}
+
+func (r *Repository) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.venues[id]; !ok {
+		return apperrors.VenueNotFound
+	}
+	for _, event := range r.events {
+		if event.VenueID != nil && *event.VenueID == id {
+			return apperrors.VenueInUse
+		}
+	}
+	delete(r.venues, id)
+
+	return nil
+}
+
+func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Email == user.Email {
+			return apperrors.EmailAlreadyExists
+		}
+		if user.TelegramID != nil && existing.TelegramID != nil && *existing.TelegramID == *user.TelegramID {
+			return apperrors.TelegramIDAlreadyExists
+		}
+	}
+
+	r.users[user.ID] = copyUser(user)
+
+	return nil
+}
+
+func (r *Repository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, apperrors.UserNotFound
+	}
+
+	return copyUser(user), nil
+}
+
+func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return copyUser(user), nil
+		}
+	}
+
+	return nil, apperrors.UserNotFound
+}
+
+func (r *Repository) UpdateUserRole(ctx context.Context, id uuid.UUID, role models.UserRole) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return apperrors.UserNotFound
+	}
+	user.Role = role
+
+	return nil
+}
+
+func (r *Repository) GetBookingByID(ctx context.Context, id uuid.UUID) (*models.Booking, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	booking, ok := r.bookings[id]
+	if !ok {
+		return nil, apperrors.BookingNotFound
+	}
+
+	return copyBooking(booking), nil
+}
+
+func (r *Repository) GetBookingsByEventID(ctx context.Context, eventID uuid.UUID) ([]*models.Booking, error) {
+	bookings := r.filterBookings(func(b *bookingRow) bool {
+		return b.EventID == eventID
+	})
+
+	sort.Slice(bookings, func(i, j int) bool {
+		if !bookings[i].CreatedAt.Equal(bookings[j].CreatedAt) {
+			return bookings[i].CreatedAt.After(bookings[j].CreatedAt)
+		}
+		return bookings[i].ID.String() < bookings[j].ID.String()
+	})
+
+	return bookings, nil
+}
+
+func (r *Repository) GetExpiredReservedBookings(ctx context.Context) ([]*models.Booking, error) {
+	now := time.Now()
+	bookings := r.filterBookings(func(b *bookingRow) bool {
+		return b.Status == models.BookingStatusReserved && !b.Deadline.After(now)
+	})
+
+	sortByDeadline(bookings)
+
+	return bookings, nil
+}
+
+func (r *Repository) GetBookingsForReminder(ctx context.Context, until time.Time) ([]*models.Booking, error) {
+	now := time.Now()
+	bookings := r.filterBookings(func(b *bookingRow) bool {
+		return b.Status == models.BookingStatusReserved &&
+			b.reminderSentAt == nil &&
+			b.Deadline.After(now) &&
+			!b.Deadline.After(until)
+	})
+
+	sortByDeadline(bookings)
+
+	return bookings, nil
+}
+
+func (r *Repository) MarkBookingReminded(ctx context.Context, bookingID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if booking, ok := r.bookings[bookingID]; ok {
+		now := time.Now()
+		booking.reminderSentAt = &now
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateSubscription(ctx context.Context, subscription *models.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.events[subscription.EventID]; !ok {
+		return apperrors.EventNotFound
+	}
+	if _, ok := r.users[subscription.UserID]; !ok {
+		return apperrors.UserNotFound
+	}
+
+	for _, existing := range r.subscriptions {
+		if existing.EventID == subscription.EventID && existing.UserID == subscription.UserID {
+			return nil
+		}
+	}
+
+	stored := *subscription
+	stored.NotifiedAt = nil
+	r.subscriptions = append(r.subscriptions, &stored)
+
+	return nil
+}
+
+func (r *Repository) GetSubscriptionsForSalesOpen(ctx context.Context, now time.Time) ([]*models.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var subscriptions []*models.Subscription
+	for _, subscription := range r.subscriptions {
+		if subscription.NotifiedAt != nil {
+			continue
+		}
+		event := r.events[subscription.EventID]
+		opensAt := event.CreatedAt
+		if event.SalesOpenAt != nil {
+			opensAt = *event.SalesOpenAt
+		}
+		if event.IsCancelled() || opensAt.After(now) || !event.SalesCloseAt.After(now) {
+			continue
+		}
+		c := *subscription
+		subscriptions = append(subscriptions, &c)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *Repository) MarkSubscriptionNotified(ctx context.Context, eventID, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, subscription := range r.subscriptions {
+		if subscription.EventID == eventID && subscription.UserID == userID {
+			now := time.Now()
+			subscription.NotifiedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) GetBookingStats(ctx context.Context) (*models.BookingStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &models.BookingStats{Events: len(r.events)}
+	for _, booking := range r.bookings {
+		switch booking.Status {
+		case models.BookingStatusReserved:
+			stats.Reserved++
+		case models.BookingStatusConfirmed:
+			stats.Confirmed++
+		case models.BookingStatusCancelled:
+			stats.Cancelled++
+		}
+	}
+
+	return stats, nil
+}
+
+func (r *Repository) BookEventWithTransaction(ctx context.Context, eventID, userID uuid.UUID, maxReservations int) (*models.Booking, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("BookEventWithTransaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[eventID]
+	if !ok {
+		return nil, fmt.Errorf("getEventForUpdate-BookEventWithTransaction: %w", apperrors.EventNotFound)
+	}
+
+	if event.IsCancelled() {
+		return nil, apperrors.EventCancelled
+	}
+
+	now := time.Now()
+	if event.HasStarted(now) {
+		return nil, apperrors.EventExpired
+	}
+
+	switch event.SalesStatus(now) {
+	case models.SalesUpcoming:
+		return nil, apperrors.SalesNotOpen
+	case models.SalesClosed:
+		return nil, apperrors.SalesClosed
+	}
+
+	if event.AvailableSeats() <= 0 {
+		return nil, apperrors.NoAvailableSeats
+	}
+
+	reserved := 0
+	for _, booking := range r.bookings {
+		if booking.EventID == eventID && booking.UserID == userID && isActive(booking.Status) {
+			return nil, apperrors.UserAlreadyBookedThisEvent
+		}
+		if booking.UserID == userID && booking.Status == models.BookingStatusReserved && booking.Deadline.After(time.Now()) {
+			reserved++
+		}
+	}
+
+	if event.PaymentReq && maxReservations > 0 && reserved >= maxReservations {
+		return nil, apperrors.TooManyActiveReservations
+	}
+
+	// Postgres finds out about an unknown user only when the foreign key
+	// rejects the booking, after every other check.
+	if _, ok := r.users[userID]; !ok {
+		return nil, fmt.Errorf("insertBooking-BookEventWithTransaction: %w", apperrors.UserNotFound)
+	}
+
+	now = now.UTC()
+	booking := &models.Booking{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		UserID:    userID,
+		Status:    models.BookingStatusConfirmed,
+		Deadline:  event.Date.UTC(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if event.PaymentReq {
+		booking.Status = models.BookingStatusReserved
+		booking.Deadline = now.Add(time.Duration(event.BookingLifetime) * time.Minute)
+		event.ReservedSeats++
+	} else {
+		event.BookedSeats++
+	}
+
+	r.bookings[booking.ID] = &bookingRow{Booking: *booking}
+
+	return booking, nil
+}
+
+func (r *Repository) ConfirmBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("ConfirmBookingWithTransaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	booking, ok := r.bookings[bookingID]
+	if !ok {
+		return fmt.Errorf("getBookingInTx-ConfirmBookingWithTransaction: %w", apperrors.BookingNotFound)
+	}
+
+	if booking.Status != models.BookingStatusReserved {
+		return apperrors.BookingNotReserved
+	}
+
+	if time.Now().After(booking.Deadline) {
+		return apperrors.BookingDeadlinePassed
+	}
+
+	event := r.events[booking.EventID]
+	if err := setSeats(event, event.ReservedSeats-1, event.BookedSeats+1); err != nil {
+		return fmt.Errorf("updateEventSeatsReservedToBooked-ConfirmBookingWithTransaction: %w", err)
+	}
+
+	booking.Status = models.BookingStatusConfirmed
+	booking.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (r *Repository) CancelExpiredBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("CancelExpiredBookingWithTransaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	booking, ok := r.bookings[bookingID]
+	if !ok {
+		return fmt.Errorf("getBookingInTx-CancelExpiredBookingWithTransaction: %w", apperrors.BookingNotFound)
+	}
+
+	if booking.Status != models.BookingStatusReserved {
+		return apperrors.BookingNotReserved
+	}
+
+	event := r.events[booking.EventID]
+	if err := setSeats(event, event.ReservedSeats-1, event.BookedSeats); err != nil {
+		return fmt.Errorf("Exec-decreaseBookingSeatsQuery: %w", err)
+	}
+
+	booking.Status = models.BookingStatusCancelled
+	booking.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (r *Repository) CancelBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("CancelBookingWithTransaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	booking, ok := r.bookings[bookingID]
+	if !ok {
+		return fmt.Errorf("getBookingInTx-CancelBookingWithTransaction: %w", apperrors.BookingNotFound)
+	}
+
+	event := r.events[booking.EventID]
+	reserved, booked := event.ReservedSeats, event.BookedSeats
+	switch booking.Status {
+	case models.BookingStatusCancelled:
+		return apperrors.BookingAlreadyCancelled
+	case models.BookingStatusConfirmed:
+		booked--
+	default:
+		reserved--
+	}
+	if err := setSeats(event, reserved, booked); err != nil {
+		return fmt.Errorf("Exec-decreaseSeats: %w", err)
+	}
+
+	booking.Status = models.BookingStatusCancelled
+	booking.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (r *Repository) CancelEventWithTransaction(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("CancelEventWithTransaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[eventID]
+	if !ok {
+		return 0, fmt.Errorf("getEventForUpdate-CancelEventWithTransaction: %w", apperrors.EventNotFound)
+	}
+
+	if event.IsCancelled() {
+		return 0, apperrors.EventCancelled
+	}
+
+	now := time.Now().UTC()
+
+	var cancelled int64
+	for _, booking := range r.bookings {
+		if booking.EventID == eventID && isActive(booking.Status) {
+			booking.Status = models.BookingStatusCancelled
+			booking.UpdatedAt = now
+			cancelled++
+		}
+	}
+
+	event.CancelledAt = &now
+	event.ReservedSeats = 0
+	event.BookedSeats = 0
+
+	return cancelled, nil
+}
+
+func (r *Repository) CreateJobRun(ctx context.Context, run *models.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *run
+	r.jobRuns = append(r.jobRuns, &stored)
+
+	return nil
+}
+
+func (r *Repository) ListJobRuns(ctx context.Context, limit int) ([]*models.JobRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	runs := make([]*models.JobRun, 0, len(r.jobRuns))
+	for _, run := range r.jobRuns {
+		stored := *run
+		runs = append(runs, &stored)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+
+	if limit >= 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+
+	return runs, nil
+}
+
+func (r *Repository) DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.jobRuns[:0]
+	for _, run := range r.jobRuns {
+		if !run.StartedAt.Before(before) {
+			kept = append(kept, run)
+		}
+	}
+
+	deleted := int64(len(r.jobRuns) - len(kept))
+	r.jobRuns = kept
+
+	return deleted, nil
+}
+
+func (r *Repository) FindSeatDrift(ctx context.Context) ([]*models.SeatDrift, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var drifts []*models.SeatDrift
+	for _, event := range r.events {
+		if drift := r.seatDrift(event); drift.HasDrift() {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return r.events[drifts[i].EventID].Date.Before(r.events[drifts[j].EventID].Date)
+	})
+
+	return drifts, nil
+}
+
+func (r *Repository) RepairSeatCountersWithTransaction(ctx context.Context, eventID uuid.UUID) (*models.SeatDrift, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("RepairSeatCountersWithTransaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[eventID]
+	if !ok {
+		return nil, fmt.Errorf("getEventForUpdate-RepairSeatCountersWithTransaction: %w", apperrors.EventNotFound)
+	}
+
+	drift := r.seatDrift(event)
+	if !drift.HasDrift() {
+		return drift, nil
+	}
+
+	if err := setSeats(event, drift.ActualReserved, drift.ActualBooked); err != nil {
+		return nil, fmt.Errorf("Exec-RepairSeatCountersWithTransaction: %w", err)
+	}
+	drift.Repaired = true
+
+	return drift, nil
+}
+
+// SetEventSeats overwrites the seat counters of an event without touching its
+// bookings. It exists so tests can simulate counter drift.
+func (r *Repository) SetEventSeats(eventID uuid.UUID, reserved, booked int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event, ok := r.events[eventID]; ok {
+		event.ReservedSeats = reserved
+		event.BookedSeats = booked
+	}
+}
+
+func (r *Repository) seatDrift(event *models.Event) *models.SeatDrift {
+	drift := &models.SeatDrift{
+		EventID:       event.ID,
+		EventName:     event.Name,
+		TotalSeats:    event.TotalSeats,
+		ReservedSeats: event.ReservedSeats,
+		BookedSeats:   event.BookedSeats,
+	}
+
+	for _, booking := range r.bookings {
+		if booking.EventID != event.ID {
+			continue
+		}
+		switch booking.Status {
+		case models.BookingStatusReserved:
+			drift.ActualReserved++
+		case models.BookingStatusConfirmed:
+			drift.ActualBooked++
+		}
+	}
+
+	return drift
+}
+
+func (r *Repository) filterBookings(match func(b *bookingRow) bool) []*models.Booking {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var bookings []*models.Booking
+	for _, booking := range r.bookings {
+		if match(booking) {
+			bookings = append(bookings, copyBooking(booking))
+		}
+	}
+
+	return bookings
+}
+
+// setSeats applies the same CHECK constraints as the events table.
+func setSeats(event *models.Event, reserved, booked int) error {
+	if reserved < 0 || booked < 0 || reserved+booked > event.TotalSeats {
+		return fmt.Errorf("seat counters of event %s violate constraints: reserved=%d booked=%d total=%d",
+			event.ID, reserved, booked, event.TotalSeats)
+	}
+
+	event.ReservedSeats = reserved
+	event.BookedSeats = booked
+
+	return nil
+}
+
+func isActive(status models.BookingStatus) bool {
+	return status == models.BookingStatusReserved || status == models.BookingStatusConfirmed
+}
+
+func sortByDeadline(bookings []*models.Booking) {
+	sort.Slice(bookings, func(i, j int) bool {
+		return bookings[i].Deadline.Before(bookings[j].Deadline)
+	})
+}
+
+func (r *Repository) AcquireIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.keys[key.Key]; ok && stored.ExpiresAt.After(key.CreatedAt) {
+		return copyIdempotencyKey(stored), nil
+	}
+
+	stored := copyIdempotencyKey(key)
+	stored.StatusCode = 0
+	stored.ContentType = ""
+	stored.Body = nil
+	r.keys[key.Key] = stored
+
+	return nil, nil
+}
+
+func (r *Repository) CompleteIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.keys[key.Key]
+	if !ok {
+		return nil
+	}
+
+	stored.StatusCode = key.StatusCode
+	stored.ContentType = key.ContentType
+	stored.Body = bytes.Clone(key.Body)
+
+	return nil
+}
+
+func (r *Repository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, key)
+
+	return nil
+}
+
+func (r *Repository) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for name, key := range r.keys {
+		if key.ExpiresAt.Before(before) {
+			delete(r.keys, name)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func matchesFilter(event *models.Event, filter models.EventFilter) bool {
+	if filter.Category != "" && event.Category != filter.Category {
+		return false
+	}
+	for _, tag := range filter.Tags {
+		if !slices.Contains(event.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyEvent also turns missing tags into an empty slice, as Postgres reads
+// them back.
+func copyEvent(event *models.Event) *models.Event {
+	c := *event
+	c.Tags = append([]string{}, event.Tags...)
+	if event.VenueID != nil {
+		venueID := *event.VenueID
+		c.VenueID = &venueID
+	}
+	if event.SalesOpenAt != nil {
+		salesOpenAt := *event.SalesOpenAt
+		c.SalesOpenAt = &salesOpenAt
+	}
+	if event.CancelledAt != nil {
+		cancelledAt := *event.CancelledAt
+		c.CancelledAt = &cancelledAt
+	}
+	return &c
+}
+
+func copyUser(user *models.User) *models.User {
+	c := *user
+	if user.TelegramID != nil {
+		telegramID := *user.TelegramID
+		c.TelegramID = &telegramID
+	}
+	return &c
+}
+
+func copyBooking(booking *bookingRow) *models.Booking {
+	c := booking.Booking
+	return &c
+}
+
+func copyIdempotencyKey(key *models.IdempotencyKey) *models.IdempotencyKey {
+	c := *key
+	c.Body = bytes.Clone(key.Body)
+	return &c
+}
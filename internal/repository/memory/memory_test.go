@@ -0,0 +1,20 @@
+package memory_test
+
+import (
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/repository/repotest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, repotest.Harness{
+		New: func(t *testing.T) repository.RepositoryI {
+			return memory.NewRepository()
+		},
+		SetEventSeats: func(t *testing.T, repo repository.RepositoryI, eventID uuid.UUID, reserved, booked int) {
+			repo.(*memory.Repository).SetEventSeats(eventID, reserved, booked)
+		},
+	})
+}
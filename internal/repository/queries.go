@@ -1,27 +1,114 @@
 package repository
 
 const (
+	// eventColumns selects an event for scanning. Events with seat inventory
+	// do not maintain the counters on their row, they are counted instead.
+	eventColumns = `
+	       e.id,
+	       e.name,
+	       e.description,
+	       e.category,
+	       e.tags,
+	       e.organizer_contact,
+	       e.cover_image_url,
+	       e.venue_id,
+	       e.date,
+	       e.end_date,
+	       e.time_zone,
+	       e.sales_open_at,
+	       e.sales_close_at,
+	       e.total_seats,
+	       CASE
+	           WHEN e.inventory = 'seats' THEN (SELECT COUNT(*) FROM bookings b WHERE b.event_id = e.id AND b.status = 'reserved')
+	           ELSE e.reserved_seats
+	       END,
+	       CASE
+	           WHEN e.inventory = 'seats' THEN (SELECT COUNT(*) FROM bookings b WHERE b.event_id = e.id AND b.status = 'confirmed')
+	           ELSE e.booked_seats
+	       END,
+	       e.booking_lifetime,
+	       e.requires_payment_confirmation,
+	       e.inventory,
+	       e.created_at,
+	       e.cancelled_at
+`
+
 	createEventQuery = `
 		INSERT INTO events (id,
 		                    name,
+		                    description,
+		                    category,
+		                    tags,
+		                    organizer_contact,
+		                    cover_image_url,
+		                    venue_id,
 		                    date,
+		                    end_date,
+		                    time_zone,
+		                    sales_open_at,
+		                    sales_close_at,
 		                    total_seats,
 		                    booking_lifetime,
 		                    requires_payment_confirmation,
+		                    inventory,
 		                    created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+`
+	createEventSeatsQuery = `
+	INSERT INTO event_seats (event_id, seat_no)
+	SELECT $1, generate_series(1, $2)
 `
 	getEventByIDQuery = `
-	SELECT id,
+	SELECT` + eventColumns + `
+	FROM events e
+	WHERE e.id = $1
+`
+	venueColumns = `
+	       id,
 	       name,
-	       date,
-	       total_seats,
-	       reserved_seats,
-	       booked_seats,
-	       booking_lifetime,
-	       requires_payment_confirmation,
-	       created_at
-	FROM events
+	       address,
+	       latitude,
+	       longitude,
+	       time_zone,
+	       default_capacity,
+	       created_at,
+	       updated_at
+`
+	createVenueQuery = `
+	INSERT INTO venues (id,
+	                    name,
+	                    address,
+	                    latitude,
+	                    longitude,
+	                    time_zone,
+	                    default_capacity,
+	                    created_at,
+	                    updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+	getVenueByIDQuery = `
+	SELECT` + venueColumns + `
+	FROM venues
+	WHERE id = $1
+`
+	listVenuesQuery = `
+	SELECT` + venueColumns + `
+	FROM venues
+	ORDER BY name, id
+`
+	updateVenueQuery = `
+	UPDATE venues
+	SET name = $2,
+	    address = $3,
+	    latitude = $4,
+	    longitude = $5,
+	    time_zone = $6,
+	    default_capacity = $7,
+	    updated_at = $8
+	WHERE id = $1
+`
+	deleteVenueQuery = `
+	DELETE FROM venues
 	WHERE id = $1
 `
 	createUserQuery = `
@@ -29,15 +116,17 @@ const (
 	                   name,
 	                   email,
 	                   telegram_id,
+	                   role,
 	                   created_at)
-	VALUES ($1, $2, $3, $4, $5)
+	VALUES ($1, $2, $3, $4, $5, $6)
 `
 	getUserByIDQuery = `
 	SELECT id, 
 	       name,
 	       email,
 	       telegram_id,
-	       created_at
+	       created_at,
+	       role
 	FROM users
 	WHERE id = $1
 `
@@ -46,23 +135,74 @@ const (
 	       name,
 	       email,
 	       telegram_id,
-	       created_at
+	       created_at,
+	       role
 	FROM users
 	WHERE email = $1
+`
+	setLockTimeoutQuery = `
+	SELECT set_config('lock_timeout', $1, true)
 `
 	selectEventForUpdateQuery = `
-	SELECT id,
-	       name,
-	       date,
-	       total_seats,
-	       reserved_seats,
-	       booked_seats,
+	SELECT` + eventColumns + `
+	FROM events e
+	WHERE e.id = $1
+	FOR UPDATE
+`
+	selectEventInventoryQuery = `
+	SELECT inventory
+	FROM events
+	WHERE id = $1
+`
+	// FOR KEY SHARE lets bookings of a seat event run side by side while
+	// still waiting for (and being waited on by) an event cancellation.
+	selectEventForBookingQuery = `
+	SELECT date,
+	       sales_open_at,
+	       sales_close_at,
 	       booking_lifetime,
 	       requires_payment_confirmation,
-	       created_at
+	       cancelled_at
 	FROM events
 	WHERE id = $1
-	FOR UPDATE
+	FOR KEY SHARE
+`
+	claimSeatQuery = `
+	UPDATE event_seats
+	SET booking_id = $2
+	WHERE (event_id, seat_no) = (SELECT event_id, seat_no
+	                             FROM event_seats
+	                             WHERE event_id = $1
+	                               AND booking_id IS NULL
+	                             LIMIT 1
+	                             FOR UPDATE SKIP LOCKED)
+`
+	claimSeatWaitQuery = `
+	UPDATE event_seats
+	SET booking_id = $2
+	WHERE (event_id, seat_no) = (SELECT event_id, seat_no
+	                             FROM event_seats
+	                             WHERE event_id = $1
+	                               AND booking_id IS NULL
+	                             LIMIT 1
+	                             FOR UPDATE)
+`
+	hasFreeSeatQuery = `
+	SELECT EXISTS (SELECT 1
+	               FROM event_seats
+	               WHERE event_id = $1
+	                 AND booking_id IS NULL)
+`
+	releaseBookingSeatQuery = `
+	UPDATE event_seats
+	SET booking_id = NULL
+	WHERE booking_id = $1
+`
+	releaseEventSeatsQuery = `
+	UPDATE event_seats
+	SET booking_id = NULL
+	WHERE event_id = $1
+	  AND booking_id IS NOT NULL
 `
 
 	insertBookingQuery = `
@@ -108,6 +248,7 @@ const (
 	SET reserved_seats = reserved_seats - 1,
 	    booked_seats = booked_seats + 1
 	WHERE id = $1
+	  AND inventory = 'counter'
 `
 	countUserBookingsQuery = `
 	SELECT 1
@@ -118,23 +259,39 @@ const (
 	LIMIT 1
 `
 
+	// The user row lock serialises the quota check of concurrent bookings by
+	// the same user. NO KEY UPDATE does not block the FK checks of inserts.
+	lockUserQuery = `
+	SELECT 1
+	FROM users
+	WHERE id = $1
+	FOR NO KEY UPDATE
+`
+	countUserReservationsQuery = `
+	SELECT COUNT(*)
+	FROM bookings
+	WHERE user_id = $1
+	  AND status = 'reserved'
+	  AND deadline > NOW()
+`
 	decreaseBookingSeatsQuery = `
 	UPDATE events
 	SET reserved_seats = reserved_seats - 1
 	WHERE id = $1
+	  AND inventory = 'counter'
+`
+	decreaseBookedSeatsQuery = `
+	UPDATE events
+	SET booked_seats = booked_seats - 1
+	WHERE id = $1
+	  AND inventory = 'counter'
 `
 	listEventsQuery = `
-	SELECT id,
-		   name,
-		   date,
-		   total_seats,
-		   reserved_seats,
-		   booked_seats,
-		   booking_lifetime,
-		   requires_payment_confirmation,
-		   created_at
-	FROM events
-	ORDER BY date
+	SELECT` + eventColumns + `
+	FROM events e
+	WHERE ($1 = '' OR e.category = $1)
+	  AND e.tags @> $2
+	ORDER BY e.date, e.id
 	`
 
 	getBookingsByEventQuery = `
@@ -147,7 +304,7 @@ const (
 	       updated_at
 	FROM bookings
 	WHERE event_id = $1
-	ORDER BY created_at DESC
+	ORDER BY created_at DESC, id
 `
 
 	getBookingByIDQuery = `
@@ -174,4 +331,277 @@ const (
 	WHERE status = 'reserved' AND deadline <= NOW()
 	ORDER BY deadline 
 `
+
+	getBookingsForReminderQuery = `
+	SELECT id,
+	       event_id,
+	       user_id,
+	       status,
+	       deadline,
+	       created_at,
+	       updated_at
+	FROM bookings
+	WHERE status = 'reserved'
+	  AND reminder_sent_at IS NULL
+	  AND deadline > NOW()
+	  AND deadline <= $1
+	ORDER BY deadline
+`
+
+	markBookingRemindedQuery = `
+	UPDATE bookings
+	SET reminder_sent_at = NOW()
+	WHERE id = $1
+`
+
+	createSubscriptionQuery = `
+	INSERT INTO event_subscriptions (event_id, user_id, created_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (event_id, user_id) DO NOTHING
+`
+
+	// getSubscriptionsForSalesOpenQuery also picks up events without
+	// sales_open_at, whose sales opened when they were created.
+	getSubscriptionsForSalesOpenQuery = `
+	SELECT s.event_id,
+	       s.user_id,
+	       s.created_at,
+	       s.notified_at
+	FROM event_subscriptions s
+	         JOIN events e ON e.id = s.event_id
+	WHERE s.notified_at IS NULL
+	  AND e.cancelled_at IS NULL
+	  AND COALESCE(e.sales_open_at, e.created_at) <= $1
+	  AND e.sales_close_at > $1
+	ORDER BY s.created_at
+`
+
+	markSubscriptionNotifiedQuery = `
+	UPDATE event_subscriptions
+	SET notified_at = NOW()
+	WHERE event_id = $1
+	  AND user_id = $2
+`
+
+	createJobRunQuery = `
+	INSERT INTO job_runs (id,
+	                      job_name,
+	                      trigger,
+	                      status,
+	                      error,
+	                      started_at,
+	                      finished_at,
+	                      duration_ms)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+	listJobRunsQuery = `
+	SELECT id,
+	       job_name,
+	       trigger,
+	       status,
+	       error,
+	       started_at,
+	       finished_at,
+	       duration_ms
+	FROM job_runs
+	ORDER BY started_at DESC
+	LIMIT $1
+`
+
+	deleteJobRunsBeforeQuery = `
+	DELETE FROM job_runs
+	WHERE started_at < $1
+`
+
+	getBookingStatsQuery = `
+	SELECT (SELECT COUNT(*) FROM events),
+	       COUNT(*) FILTER (WHERE status = 'reserved'),
+	       COUNT(*) FILTER (WHERE status = 'confirmed'),
+	       COUNT(*) FILTER (WHERE status = 'cancelled')
+	FROM bookings
+`
+
+	// Counter events drift when their counters disagree with the bookings,
+	// seat events when the seat rows disagree with them.
+	findSeatDriftQuery = `
+	SELECT id,
+	       name,
+	       total_seats,
+	       reserved_seats,
+	       booked_seats,
+	       actual_reserved,
+	       actual_booked,
+	       stale_seats,
+	       unseated_bookings
+	FROM (SELECT e.id,
+	             e.name,
+	             e.date,
+	             e.total_seats,
+	             e.reserved_seats,
+	             e.booked_seats,
+	             COUNT(b.id) FILTER (WHERE b.status = 'reserved') AS actual_reserved,
+	             COUNT(b.id) FILTER (WHERE b.status = 'confirmed') AS actual_booked,
+	             0 AS stale_seats,
+	             0 AS unseated_bookings
+	      FROM events e
+	      LEFT JOIN bookings b ON b.event_id = e.id
+	      WHERE e.inventory = 'counter'
+	      GROUP BY e.id
+	      HAVING e.reserved_seats <> COUNT(b.id) FILTER (WHERE b.status = 'reserved')
+	          OR e.booked_seats <> COUNT(b.id) FILTER (WHERE b.status = 'confirmed')
+	      UNION ALL
+	      SELECT e.id,
+	             e.name,
+	             e.date,
+	             e.total_seats,
+	             b.reserved,
+	             b.confirmed,
+	             b.reserved,
+	             b.confirmed,
+	             s.stale,
+	             b.unseated
+	      FROM events e
+	      CROSS JOIN LATERAL (SELECT COUNT(*) FILTER (WHERE bk.status = 'reserved') AS reserved,
+	                                 COUNT(*) FILTER (WHERE bk.status = 'confirmed') AS confirmed,
+	                                 COUNT(*) FILTER (WHERE bk.status IN ('reserved', 'confirmed')
+	                                                    AND NOT EXISTS (SELECT 1
+	                                                                    FROM event_seats es
+	                                                                    WHERE es.booking_id = bk.id)) AS unseated
+	                          FROM bookings bk
+	                          WHERE bk.event_id = e.id) b
+	      CROSS JOIN LATERAL (SELECT COUNT(*) AS stale
+	                          FROM event_seats es
+	                          JOIN bookings bk ON bk.id = es.booking_id
+	                          WHERE es.event_id = e.id
+	                            AND bk.status NOT IN ('reserved', 'confirmed')) s
+	      WHERE e.inventory = 'seats'
+	        AND (s.stale > 0 OR b.unseated > 0)) d
+	ORDER BY date
+`
+
+	countEventBookingsQuery = `
+	SELECT COUNT(*) FILTER (WHERE status = 'reserved'),
+	       COUNT(*) FILTER (WHERE status = 'confirmed')
+	FROM bookings
+	WHERE event_id = $1
+`
+
+	countSeatAssignmentDriftQuery = `
+	SELECT (SELECT COUNT(*)
+	        FROM event_seats es
+	        JOIN bookings b ON b.id = es.booking_id
+	        WHERE es.event_id = $1
+	          AND b.status NOT IN ('reserved', 'confirmed')),
+	       (SELECT COUNT(*)
+	        FROM bookings b
+	        WHERE b.event_id = $1
+	          AND b.status IN ('reserved', 'confirmed')
+	          AND NOT EXISTS (SELECT 1 FROM event_seats es WHERE es.booking_id = b.id))
+`
+
+	releaseStaleSeatsQuery = `
+	UPDATE event_seats es
+	SET booking_id = NULL
+	FROM bookings b
+	WHERE b.id = es.booking_id
+	  AND es.event_id = $1
+	  AND b.status NOT IN ('reserved', 'confirmed')
+`
+
+	// Unseated bookings take the free seats in the order they were made.
+	seatUnseatedBookingsQuery = `
+	WITH unseated AS (SELECT b.id,
+	                         ROW_NUMBER() OVER (ORDER BY b.created_at, b.id) AS n
+	                  FROM bookings b
+	                  WHERE b.event_id = $1
+	                    AND b.status IN ('reserved', 'confirmed')
+	                    AND NOT EXISTS (SELECT 1 FROM event_seats es WHERE es.booking_id = b.id)),
+	     free AS (SELECT seat_no,
+	                     ROW_NUMBER() OVER (ORDER BY seat_no) AS n
+	              FROM event_seats
+	              WHERE event_id = $1
+	                AND booking_id IS NULL)
+	UPDATE event_seats es
+	SET booking_id = u.id
+	FROM unseated u
+	JOIN free f ON f.n = u.n
+	WHERE es.event_id = $1
+	  AND es.seat_no = f.seat_no
+`
+
+	setEventSeatsQuery = `
+	UPDATE events
+	SET reserved_seats = $2,
+	    booked_seats = $3
+	WHERE id = $1
+`
+
+	cancelEventQuery = `
+	UPDATE events
+	SET cancelled_at = NOW(),
+	    reserved_seats = 0,
+	    booked_seats = 0
+	WHERE id = $1
+`
+
+	cancelEventBookingsQuery = `
+	UPDATE bookings
+	SET status = 'cancelled', updated_at = NOW()
+	WHERE event_id = $1
+	  AND status IN ('reserved', 'confirmed')
+`
+
+	updateUserRoleQuery = `
+	UPDATE users
+	SET role = $2
+	WHERE id = $1
+`
+
+	// An expired key is taken over as if it did not exist.
+	acquireIdempotencyKeyQuery = `
+	INSERT INTO idempotency_keys (key,
+	                              fingerprint,
+	                              created_at,
+	                              expires_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (key) DO UPDATE
+	    SET fingerprint  = EXCLUDED.fingerprint,
+	        status_code  = NULL,
+	        content_type = NULL,
+	        body         = NULL,
+	        created_at   = EXCLUDED.created_at,
+	        expires_at   = EXCLUDED.expires_at
+	    WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+`
+
+	getIdempotencyKeyQuery = `
+	SELECT key,
+	       fingerprint,
+	       COALESCE(status_code, 0),
+	       COALESCE(content_type, ''),
+	       body,
+	       created_at,
+	       expires_at
+	FROM idempotency_keys
+	WHERE key = $1
+`
+
+	completeIdempotencyKeyQuery = `
+	UPDATE idempotency_keys
+	SET status_code = $2,
+	    content_type = $3,
+	    body = $4
+	WHERE key = $1
+`
+
+	deleteIdempotencyKeyQuery = `
+	DELETE FROM idempotency_keys
+	WHERE key = $1
+`
+
+	deleteIdempotencyKeysBeforeQuery = `
+	DELETE FROM idempotency_keys
+	WHERE expires_at < $1
+`
 )
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kstsm/wb-event-booker/internal/models"
+)
+
+func (r *Repository) FindSeatDrift(ctx context.Context) ([]*models.SeatDrift, error) {
+	rows, err := r.conn.Query(ctx, findSeatDriftQuery)
+	if err != nil {
+		return nil, fmt.Errorf("Query-FindSeatDrift: %w", err)
+	}
+	defer rows.Close()
+
+	var drifts []*models.SeatDrift
+	for rows.Next() {
+		drift := new(models.SeatDrift)
+		if err := rows.Scan(
+			&drift.EventID,
+			&drift.EventName,
+			&drift.TotalSeats,
+			&drift.ReservedSeats,
+			&drift.BookedSeats,
+			&drift.ActualReserved,
+			&drift.ActualBooked,
+			&drift.StaleSeats,
+			&drift.UnseatedBookings,
+		); err != nil {
+			return nil, fmt.Errorf("Scan-FindSeatDrift: %w", err)
+		}
+		drifts = append(drifts, drift)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Rows-FindSeatDrift: %w", err)
+	}
+
+	return drifts, nil
+}
+
+func (r *Repository) RepairSeatCountersWithTransaction(ctx context.Context, eventID uuid.UUID) (*models.SeatDrift, error) {
+	var drift *models.SeatDrift
+
+	err := r.withTx(ctx, "RepairSeatCountersWithTransaction", func(tx pgx.Tx) error {
+		event, err := r.getEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return fmt.Errorf("getEventForUpdate-RepairSeatCountersWithTransaction: %w", err)
+		}
+
+		drift = &models.SeatDrift{
+			EventID:       event.ID,
+			EventName:     event.Name,
+			TotalSeats:    event.TotalSeats,
+			ReservedSeats: event.ReservedSeats,
+			BookedSeats:   event.BookedSeats,
+		}
+
+		err = tx.QueryRow(ctx, countEventBookingsQuery, eventID).Scan(&drift.ActualReserved, &drift.ActualBooked)
+		if err != nil {
+			return fmt.Errorf("QueryRow-RepairSeatCountersWithTransaction: %w", err)
+		}
+
+		if event.Inventory == models.InventorySeats {
+			return r.repairSeatAssignments(ctx, tx, drift)
+		}
+
+		if !drift.HasDrift() {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, setEventSeatsQuery, eventID, drift.ActualReserved, drift.ActualBooked)
+		if err != nil {
+			return fmt.Errorf("Exec-RepairSeatCountersWithTransaction: %w", err)
+		}
+
+		drift.Repaired = true
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return drift, nil
+}
+
+// repairSeatAssignments frees the seats held by cancelled bookings and gives
+// the active bookings without a seat the free ones. The event row lock keeps
+// bookings out meanwhile. If there are more active bookings than seats, the
+// event is oversold and nothing is changed.
+func (r *Repository) repairSeatAssignments(ctx context.Context, tx pgx.Tx, drift *models.SeatDrift) error {
+	err := tx.QueryRow(ctx, countSeatAssignmentDriftQuery, drift.EventID).Scan(&drift.StaleSeats, &drift.UnseatedBookings)
+	if err != nil {
+		return fmt.Errorf("QueryRow-repairSeatAssignments: %w", err)
+	}
+
+	if !drift.HasDrift() {
+		return nil
+	}
+
+	if drift.ActualReserved+drift.ActualBooked > drift.TotalSeats {
+		return fmt.Errorf("repairSeatAssignments: %d active bookings exceed %d seats",
+			drift.ActualReserved+drift.ActualBooked, drift.TotalSeats)
+	}
+
+	if _, err = tx.Exec(ctx, releaseStaleSeatsQuery, drift.EventID); err != nil {
+		return fmt.Errorf("Exec-releaseStaleSeats: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, seatUnseatedBookingsQuery, drift.EventID); err != nil {
+		return fmt.Errorf("Exec-seatUnseatedBookings: %w", err)
+	}
+
+	drift.Repaired = true
+
+	return nil
+}
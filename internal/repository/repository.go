@@ -4,33 +4,83 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kstsm/wb-event-booker/internal/config"
 	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
 )
 
 type RepositoryI interface {
 	CreateEvent(ctx context.Context, event *models.Event) error
 	GetEventByID(ctx context.Context, id uuid.UUID) (*models.Event, error)
-	ListEvents(ctx context.Context) ([]*models.Event, error)
+	ListEvents(ctx context.Context, filter models.EventFilter) ([]*models.Event, error)
+
+	CreateVenue(ctx context.Context, venue *models.Venue) error
+	GetVenueByID(ctx context.Context, id uuid.UUID) (*models.Venue, error)
+	ListVenues(ctx context.Context) ([]*models.Venue, error)
+	UpdateVenue(ctx context.Context, venue *models.Venue) error
+	DeleteVenue(ctx context.Context, id uuid.UUID) error
 
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role models.UserRole) error
 
 	GetBookingByID(ctx context.Context, id uuid.UUID) (*models.Booking, error)
 	GetBookingsByEventID(ctx context.Context, eventID uuid.UUID) ([]*models.Booking, error)
 	GetExpiredReservedBookings(ctx context.Context) ([]*models.Booking, error)
+	GetBookingsForReminder(ctx context.Context, until time.Time) ([]*models.Booking, error)
+	MarkBookingReminded(ctx context.Context, bookingID uuid.UUID) error
+	GetBookingStats(ctx context.Context) (*models.BookingStats, error)
+
+	// CreateSubscription subscribes a user to the opening of the ticket sales
+	// of an event. Subscribing twice is not an error.
+	CreateSubscription(ctx context.Context, subscription *models.Subscription) error
+	// GetSubscriptionsForSalesOpen returns the subscriptions not notified yet
+	// of the events whose sales are open at now.
+	GetSubscriptionsForSalesOpen(ctx context.Context, now time.Time) ([]*models.Subscription, error)
+	MarkSubscriptionNotified(ctx context.Context, eventID, userID uuid.UUID) error
 
 	CancelExpiredBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error
+	// CancelBookingWithTransaction cancels a reserved or confirmed booking on
+	// behalf of its owner and gives the seat back.
+	CancelBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error
 	ConfirmBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error
-	BookEventWithTransaction(ctx context.Context, eventID, userID uuid.UUID) (*models.Booking, error)
+	BookEventWithTransaction(ctx context.Context, eventID, userID uuid.UUID, maxReservations int) (*models.Booking, error)
+	CancelEventWithTransaction(ctx context.Context, eventID uuid.UUID) (int64, error)
+
+	CreateJobRun(ctx context.Context, run *models.JobRun) error
+	ListJobRuns(ctx context.Context, limit int) ([]*models.JobRun, error)
+	DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error)
+
+	FindSeatDrift(ctx context.Context) ([]*models.SeatDrift, error)
+	RepairSeatCountersWithTransaction(ctx context.Context, eventID uuid.UUID) (*models.SeatDrift, error)
+
+	// AcquireIdempotencyKey stores a new key without a response. If a key that
+	// has not expired yet already exists, it is returned instead.
+	AcquireIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (*models.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+	DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type Repository struct {
 	conn *pgxpool.Pool
+	tx   txSettings
+}
+
+type txSettings struct {
+	lockTimeout  time.Duration
+	maxRetries   int
+	retryBackoff time.Duration
 }
 
-func NewRepository(conn *pgxpool.Pool) RepositoryI {
+func NewRepository(conn *pgxpool.Pool, cfg config.Postgres) RepositoryI {
 	return &Repository{
 		conn: conn,
+		tx: txSettings{
+			lockTimeout:  time.Duration(cfg.LockTimeoutMs) * time.Millisecond,
+			maxRetries:   cfg.TxMaxRetries,
+			retryBackoff: time.Duration(cfg.TxRetryBackoffMs) * time.Millisecond,
+		},
 	}
 }
@@ -0,0 +1,1191 @@
+// Package repotest is a conformance suite for repository.RepositoryI. Every
+// implementation must pass it so that services and handlers behave the same
+// whichever storage they run on.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"sync"
+	"testing"
+	"time"
+)
+
+type Harness struct {
+	// New returns an empty repository. It is called once per subtest.
+	New func(t *testing.T) repository.RepositoryI
+	// SetEventSeats overwrites the seat counters of an event behind the
+	// repository's back to simulate drift.
+	SetEventSeats func(t *testing.T, repo repository.RepositoryI, eventID uuid.UUID, reserved, booked int)
+
+	// inventory is used for the events created by the tests.
+	inventory models.Inventory
+}
+
+// Run executes the suite with counter inventory and then repeats the tests
+// that book seats with seat inventory.
+func Run(t *testing.T, h Harness) {
+	tests := []struct {
+		name  string
+		fn    func(t *testing.T, repo repository.RepositoryI, h Harness)
+		seats bool
+	}{
+		{"Events", testEvents, true},
+		{"EventDetails", testEventDetails, false},
+		{"Venues", testVenues, false},
+		{"VenueOverlap", testVenueOverlap, false},
+		{"Users", testUsers, false},
+		{"BookPaidEvent", testBookPaidEvent, true},
+		{"BookFreeEvent", testBookFreeEvent, true},
+		{"BookingRules", testBookingRules, true},
+		{"CancelExpiredBooking", testCancelExpiredBooking, true},
+		{"CancelBooking", testCancelBooking, true},
+		{"CancelEvent", testCancelEvent, true},
+		{"Reminders", testReminders, true},
+		{"Subscriptions", testSubscriptions, false},
+		{"ConcurrentBookingsDoNotOversell", testConcurrentBookings, true},
+		{"ConcurrentDuplicateBookings", testConcurrentDuplicateBookings, true},
+		{"ReservationQuota", testReservationQuota, true},
+		{"ConcurrentReservationQuota", testConcurrentReservationQuota, true},
+		{"JobRuns", testJobRuns, false},
+		{"Stats", testStats, true},
+		{"SeatDrift", testSeatDrift, false},
+		{"IdempotencyKeys", testIdempotencyKeys, false},
+	}
+
+	h.inventory = models.InventoryCounter
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, h.New(t), h)
+		})
+	}
+
+	seats := h
+	seats.inventory = models.InventorySeats
+	t.Run("SeatInventory", func(t *testing.T) {
+		for _, tt := range tests {
+			if !tt.seats {
+				continue
+			}
+			t.Run(tt.name, func(t *testing.T) {
+				tt.fn(t, seats.New(t), seats)
+			})
+		}
+	})
+}
+
+func testEvents(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	later := newEvent(t, h, repo, 10, true, 48*time.Hour)
+	sooner := newEvent(t, h, repo, 5, false, 24*time.Hour)
+
+	got, err := repo.GetEventByID(ctx, later.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.Name != later.Name || !got.Date.Equal(later.Date) || got.TotalSeats != 10 ||
+		got.ReservedSeats != 0 || got.BookedSeats != 0 || !got.PaymentReq || got.Inventory != h.inventory || got.IsCancelled() {
+		t.Fatalf("GetEventByID returned %+v, want %+v", got, later)
+	}
+
+	events, err := repo.ListEvents(ctx, models.EventFilter{})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != sooner.ID || events[1].ID != later.ID {
+		t.Fatalf("ListEvents must return events ordered by date, got %v", eventIDs(events))
+	}
+
+	_, err = repo.GetEventByID(ctx, uuid.New())
+	expectError(t, err, apperrors.EventNotFound)
+}
+
+func testEventDetails(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	plain := newEvent(t, h, repo, 10, false, 24*time.Hour)
+	salesOpenAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	meetup := &models.Event{
+		ID:               uuid.New(),
+		Name:             "Go meetup",
+		Description:      "# Agenda\n\n- generics\n- profiling",
+		Category:         models.EventCategoryMeetup,
+		Tags:             []string{"go", "backend"},
+		OrganizerContact: "org@example.com",
+		CoverImageURL:    "https://example.com/cover.png",
+		Date:             time.Now().Add(48 * time.Hour).UTC().Truncate(time.Microsecond),
+		EndDate:          time.Now().Add(50 * time.Hour).UTC().Truncate(time.Microsecond),
+		TimeZone:         "Europe/Moscow",
+		SalesOpenAt:      &salesOpenAt,
+		SalesCloseAt:     time.Now().Add(47 * time.Hour).UTC().Truncate(time.Microsecond),
+		TotalSeats:       10,
+		BookingLifetime:  30,
+		Inventory:        h.inventory,
+		CreatedAt:        time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if err := repo.CreateEvent(ctx, meetup); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	workshop := newEvent(t, h, repo, 10, false, 72*time.Hour)
+
+	got, err := repo.GetEventByID(ctx, meetup.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.Description != meetup.Description || got.Category != meetup.Category ||
+		fmt.Sprint(got.Tags) != fmt.Sprint(meetup.Tags) || got.OrganizerContact != meetup.OrganizerContact ||
+		got.CoverImageURL != meetup.CoverImageURL || got.TimeZone != meetup.TimeZone ||
+		got.SalesOpenAt == nil || !got.SalesOpenAt.Equal(salesOpenAt) || !got.SalesCloseAt.Equal(meetup.SalesCloseAt) {
+		t.Fatalf("GetEventByID returned %+v, want %+v", got, meetup)
+	}
+
+	got, err = repo.GetEventByID(ctx, plain.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.Tags == nil || len(got.Tags) != 0 {
+		t.Fatalf("event without tags returned tags %#v, want an empty slice", got.Tags)
+	}
+
+	tests := []struct {
+		name   string
+		filter models.EventFilter
+		want   []uuid.UUID
+	}{
+		{"no filter", models.EventFilter{}, []uuid.UUID{plain.ID, meetup.ID, workshop.ID}},
+		{"category", models.EventFilter{Category: models.EventCategoryMeetup}, []uuid.UUID{meetup.ID}},
+		{"tag", models.EventFilter{Tags: []string{"go"}}, []uuid.UUID{meetup.ID}},
+		{"all tags", models.EventFilter{Tags: []string{"go", "backend"}}, []uuid.UUID{meetup.ID}},
+		{"missing tag", models.EventFilter{Tags: []string{"go", "rust"}}, nil},
+		{"category and tag", models.EventFilter{Category: models.EventCategoryOther, Tags: []string{"go"}}, nil},
+	}
+	for _, tt := range tests {
+		events, err := repo.ListEvents(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("ListEvents(%s): %v", tt.name, err)
+		}
+		if fmt.Sprint(eventIDs(events)) != fmt.Sprint(tt.want) {
+			t.Errorf("ListEvents(%s) = %v, want %v", tt.name, eventIDs(events), tt.want)
+		}
+	}
+}
+
+func testVenues(t *testing.T, repo repository.RepositoryI, _ Harness) {
+	ctx := context.Background()
+
+	stage := newVenue(t, repo, "Stage")
+	hall := newVenue(t, repo, "Hall")
+
+	got, err := repo.GetVenueByID(ctx, stage.ID)
+	if err != nil {
+		t.Fatalf("GetVenueByID: %v", err)
+	}
+	if got.Name != stage.Name || got.Address != stage.Address || got.Latitude != stage.Latitude ||
+		got.Longitude != stage.Longitude || got.TimeZone != stage.TimeZone || got.DefaultCapacity != stage.DefaultCapacity {
+		t.Fatalf("GetVenueByID returned %+v, want %+v", got, stage)
+	}
+
+	venues, err := repo.ListVenues(ctx)
+	if err != nil {
+		t.Fatalf("ListVenues: %v", err)
+	}
+	if len(venues) != 2 || venues[0].ID != hall.ID || venues[1].ID != stage.ID {
+		t.Fatalf("ListVenues must return venues ordered by name, got %+v", venues)
+	}
+
+	updated := *stage
+	updated.Name = "Main stage"
+	updated.DefaultCapacity = 500
+	updated.UpdatedAt = stage.UpdatedAt.Add(time.Minute)
+	if err := repo.UpdateVenue(ctx, &updated); err != nil {
+		t.Fatalf("UpdateVenue: %v", err)
+	}
+	got, err = repo.GetVenueByID(ctx, stage.ID)
+	if err != nil {
+		t.Fatalf("GetVenueByID: %v", err)
+	}
+	if got.Name != "Main stage" || got.DefaultCapacity != 500 || !got.CreatedAt.Equal(stage.CreatedAt) ||
+		!got.UpdatedAt.Equal(updated.UpdatedAt) {
+		t.Fatalf("updated venue = %+v", got)
+	}
+
+	if err := repo.DeleteVenue(ctx, hall.ID); err != nil {
+		t.Fatalf("DeleteVenue: %v", err)
+	}
+	_, err = repo.GetVenueByID(ctx, hall.ID)
+	expectError(t, err, apperrors.VenueNotFound)
+	expectError(t, repo.DeleteVenue(ctx, hall.ID), apperrors.VenueNotFound)
+
+	missing := updated
+	missing.ID = uuid.New()
+	expectError(t, repo.UpdateVenue(ctx, &missing), apperrors.VenueNotFound)
+}
+
+func testVenueOverlap(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	venue := newVenue(t, repo, "Stage")
+	other := newVenue(t, repo, "Hall")
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+
+	create := func(venueID uuid.UUID, from, to time.Duration) (*models.Event, error) {
+		event := &models.Event{
+			ID:              uuid.New(),
+			Name:            "event " + uuid.NewString()[:8],
+			Category:        models.EventCategoryOther,
+			VenueID:         &venueID,
+			Date:            start.Add(from),
+			EndDate:         start.Add(to),
+			TimeZone:        "UTC",
+			SalesCloseAt:    start.Add(from),
+			TotalSeats:      10,
+			BookingLifetime: 30,
+			Inventory:       h.inventory,
+			CreatedAt:       time.Now().UTC().Truncate(time.Microsecond),
+		}
+		return event, repo.CreateEvent(ctx, event)
+	}
+
+	first, err := create(venue.ID, 0, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	got, err := repo.GetEventByID(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.VenueID == nil || *got.VenueID != venue.ID || !got.EndDate.Equal(first.EndDate) {
+		t.Fatalf("GetEventByID returned venue %v, end %v", got.VenueID, got.EndDate)
+	}
+
+	_, err = create(venue.ID, time.Hour, 3*time.Hour)
+	expectError(t, err, apperrors.VenueUnavailable)
+	_, err = create(venue.ID, -time.Hour, 3*time.Hour)
+	expectError(t, err, apperrors.VenueUnavailable)
+
+	if _, err := create(venue.ID, 2*time.Hour, 4*time.Hour); err != nil {
+		t.Fatalf("event starting when the previous one ends: %v", err)
+	}
+	if _, err := create(other.ID, time.Hour, 3*time.Hour); err != nil {
+		t.Fatalf("overlapping event at another venue: %v", err)
+	}
+	_, err = create(uuid.New(), 0, time.Hour)
+	expectError(t, err, apperrors.VenueNotFound)
+
+	if _, err := repo.CancelEventWithTransaction(ctx, first.ID); err != nil {
+		t.Fatalf("CancelEventWithTransaction: %v", err)
+	}
+	if _, err := create(venue.ID, time.Hour, 2*time.Hour); err != nil {
+		t.Fatalf("event in the slot of a cancelled one: %v", err)
+	}
+
+	expectError(t, repo.DeleteVenue(ctx, venue.ID), apperrors.VenueInUse)
+}
+
+func testUsers(t *testing.T, repo repository.RepositoryI, _ Harness) {
+	ctx := context.Background()
+
+	telegramID := int64(42)
+	user := newUser(t, repo, "alice", &telegramID)
+
+	byID, err := repo.GetUserByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if byID.Email != user.Email || byID.TelegramID == nil || *byID.TelegramID != 42 || byID.Role != models.UserRoleUser {
+		t.Fatalf("GetUserByID returned %+v", byID)
+	}
+
+	byEmail, err := repo.GetUserByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if byEmail.ID != user.ID {
+		t.Fatalf("GetUserByEmail returned user %s, want %s", byEmail.ID, user.ID)
+	}
+
+	duplicate := *user
+	duplicate.ID = uuid.New()
+	duplicate.TelegramID = nil
+	expectError(t, repo.CreateUser(ctx, &duplicate), apperrors.EmailAlreadyExists)
+
+	duplicate.Email = "other@example.com"
+	duplicate.TelegramID = &telegramID
+	expectError(t, repo.CreateUser(ctx, &duplicate), apperrors.TelegramIDAlreadyExists)
+
+	if err := repo.UpdateUserRole(ctx, user.ID, models.UserRoleAdmin); err != nil {
+		t.Fatalf("UpdateUserRole: %v", err)
+	}
+	promoted, err := repo.GetUserByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if promoted.Role != models.UserRoleAdmin {
+		t.Fatalf("role = %s, want %s", promoted.Role, models.UserRoleAdmin)
+	}
+
+	expectError(t, repo.UpdateUserRole(ctx, uuid.New(), models.UserRoleAdmin), apperrors.UserNotFound)
+
+	_, err = repo.GetUserByID(ctx, uuid.New())
+	expectError(t, err, apperrors.UserNotFound)
+
+	_, err = repo.GetUserByEmail(ctx, "nobody@example.com")
+	expectError(t, err, apperrors.UserNotFound)
+}
+
+func testBookPaidEvent(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 2, true, 24*time.Hour)
+	user := newUser(t, repo, "bob", nil)
+
+	before := time.Now()
+	booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	if booking.Status != models.BookingStatusReserved {
+		t.Fatalf("status = %s, want %s", booking.Status, models.BookingStatusReserved)
+	}
+	lifetime := time.Duration(event.BookingLifetime) * time.Minute
+	if booking.Deadline.Before(before.Add(lifetime-time.Second)) || booking.Deadline.After(time.Now().Add(lifetime)) {
+		t.Fatalf("deadline %v is not booking_lifetime after booking", booking.Deadline)
+	}
+	expectSeats(t, repo, event.ID, 1, 0)
+
+	stored, err := repo.GetBookingByID(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("GetBookingByID: %v", err)
+	}
+	if stored.EventID != event.ID || stored.UserID != user.ID || stored.Status != models.BookingStatusReserved {
+		t.Fatalf("GetBookingByID returned %+v", stored)
+	}
+
+	if err := repo.ConfirmBookingWithTransaction(ctx, booking.ID); err != nil {
+		t.Fatalf("ConfirmBookingWithTransaction: %v", err)
+	}
+	expectSeats(t, repo, event.ID, 0, 1)
+	expectStatus(t, repo, booking.ID, models.BookingStatusConfirmed)
+
+	expectError(t, repo.ConfirmBookingWithTransaction(ctx, booking.ID), apperrors.BookingNotReserved)
+	expectError(t, repo.ConfirmBookingWithTransaction(ctx, uuid.New()), apperrors.BookingNotFound)
+
+	_, err = repo.GetBookingByID(ctx, uuid.New())
+	expectError(t, err, apperrors.BookingNotFound)
+}
+
+func testBookFreeEvent(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 2, false, 24*time.Hour)
+	user := newUser(t, repo, "carol", nil)
+
+	booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		t.Fatalf("status = %s, want %s", booking.Status, models.BookingStatusConfirmed)
+	}
+	if !booking.Deadline.Equal(event.Date) {
+		t.Fatalf("deadline = %v, want the event date %v", booking.Deadline, event.Date)
+	}
+	expectSeats(t, repo, event.ID, 0, 1)
+
+	expectError(t, repo.ConfirmBookingWithTransaction(ctx, booking.ID), apperrors.BookingNotReserved)
+}
+
+func testBookingRules(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 1, true, 24*time.Hour)
+	first := newUser(t, repo, "dave", nil)
+	second := newUser(t, repo, "erin", nil)
+
+	if _, err := repo.BookEventWithTransaction(ctx, event.ID, first.ID, 0); err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+
+	_, err := repo.BookEventWithTransaction(ctx, event.ID, first.ID, 0)
+	expectError(t, err, apperrors.NoAvailableSeats)
+
+	_, err = repo.BookEventWithTransaction(ctx, event.ID, second.ID, 0)
+	expectError(t, err, apperrors.NoAvailableSeats)
+	expectSeats(t, repo, event.ID, 1, 0)
+
+	roomy := newEvent(t, h, repo, 5, true, 24*time.Hour)
+	if _, err := repo.BookEventWithTransaction(ctx, roomy.ID, first.ID, 0); err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	_, err = repo.BookEventWithTransaction(ctx, roomy.ID, first.ID, 0)
+	expectError(t, err, apperrors.UserAlreadyBookedThisEvent)
+	_, err = repo.BookEventWithTransaction(ctx, roomy.ID, uuid.New(), 0)
+	expectError(t, err, apperrors.UserNotFound)
+	expectSeats(t, repo, roomy.ID, 1, 0)
+
+	past := newEvent(t, h, repo, 5, true, -time.Hour)
+	_, err = repo.BookEventWithTransaction(ctx, past.ID, first.ID, 0)
+	expectError(t, err, apperrors.EventExpired)
+
+	closed := newEventClosing(t, h, repo, 5, true, 24*time.Hour, -time.Minute)
+	_, err = repo.BookEventWithTransaction(ctx, closed.ID, first.ID, 0)
+	expectError(t, err, apperrors.SalesClosed)
+
+	upcoming := newEventOpening(t, h, repo, 5, 24*time.Hour, time.Hour)
+	_, err = repo.BookEventWithTransaction(ctx, upcoming.ID, first.ID, 0)
+	expectError(t, err, apperrors.SalesNotOpen)
+	expectSeats(t, repo, upcoming.ID, 0, 0)
+
+	opened := newEventOpening(t, h, repo, 5, 24*time.Hour, -time.Minute)
+	if _, err := repo.BookEventWithTransaction(ctx, opened.ID, first.ID, 0); err != nil {
+		t.Fatalf("BookEventWithTransaction after sales opened: %v", err)
+	}
+
+	_, err = repo.BookEventWithTransaction(ctx, uuid.New(), first.ID, 0)
+	expectError(t, err, apperrors.EventNotFound)
+}
+
+func testCancelExpiredBooking(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 1, true, 24*time.Hour)
+	user := newUser(t, repo, "frank", nil)
+
+	booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+
+	if err := repo.CancelExpiredBookingWithTransaction(ctx, booking.ID); err != nil {
+		t.Fatalf("CancelExpiredBookingWithTransaction: %v", err)
+	}
+	expectSeats(t, repo, event.ID, 0, 0)
+	expectStatus(t, repo, booking.ID, models.BookingStatusCancelled)
+
+	expectError(t, repo.CancelExpiredBookingWithTransaction(ctx, booking.ID), apperrors.BookingNotReserved)
+	expectError(t, repo.CancelExpiredBookingWithTransaction(ctx, uuid.New()), apperrors.BookingNotFound)
+
+	if _, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0); err != nil {
+		t.Fatalf("a cancelled booking must not prevent booking again: %v", err)
+	}
+
+	bookings, err := repo.GetBookingsByEventID(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetBookingsByEventID: %v", err)
+	}
+	if len(bookings) != 2 || bookings[1].ID != booking.ID {
+		t.Fatalf("GetBookingsByEventID must return both bookings, newest first")
+	}
+}
+
+func testCancelBooking(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 2, true, 24*time.Hour)
+	reserved := newUser(t, repo, "ivy", nil)
+	confirmed := newUser(t, repo, "jack", nil)
+
+	reservation, err := repo.BookEventWithTransaction(ctx, event.ID, reserved.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	confirmation, err := repo.BookEventWithTransaction(ctx, event.ID, confirmed.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	if err := repo.ConfirmBookingWithTransaction(ctx, confirmation.ID); err != nil {
+		t.Fatalf("ConfirmBookingWithTransaction: %v", err)
+	}
+	expectSeats(t, repo, event.ID, 1, 1)
+
+	if err := repo.CancelBookingWithTransaction(ctx, reservation.ID); err != nil {
+		t.Fatalf("CancelBookingWithTransaction(reserved): %v", err)
+	}
+	expectSeats(t, repo, event.ID, 0, 1)
+	expectStatus(t, repo, reservation.ID, models.BookingStatusCancelled)
+
+	if err := repo.CancelBookingWithTransaction(ctx, confirmation.ID); err != nil {
+		t.Fatalf("CancelBookingWithTransaction(confirmed): %v", err)
+	}
+	expectSeats(t, repo, event.ID, 0, 0)
+	expectStatus(t, repo, confirmation.ID, models.BookingStatusCancelled)
+
+	expectError(t, repo.CancelBookingWithTransaction(ctx, confirmation.ID), apperrors.BookingAlreadyCancelled)
+	expectError(t, repo.CancelBookingWithTransaction(ctx, uuid.New()), apperrors.BookingNotFound)
+
+	for _, user := range []*models.User{reserved, confirmed} {
+		if _, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0); err != nil {
+			t.Fatalf("the released seats must be bookable again: %v", err)
+		}
+	}
+	expectSeats(t, repo, event.ID, 2, 0)
+}
+
+func testCancelEvent(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 5, true, 24*time.Hour)
+	reserved := newUser(t, repo, "gina", nil)
+	confirmed := newUser(t, repo, "hank", nil)
+
+	reservation, err := repo.BookEventWithTransaction(ctx, event.ID, reserved.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	confirmation, err := repo.BookEventWithTransaction(ctx, event.ID, confirmed.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+	if err := repo.ConfirmBookingWithTransaction(ctx, confirmation.ID); err != nil {
+		t.Fatalf("ConfirmBookingWithTransaction: %v", err)
+	}
+
+	cancelled, err := repo.CancelEventWithTransaction(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("CancelEventWithTransaction: %v", err)
+	}
+	if cancelled != 2 {
+		t.Fatalf("cancelled %d bookings, want 2", cancelled)
+	}
+
+	got, err := repo.GetEventByID(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if !got.IsCancelled() {
+		t.Fatalf("event is not marked as cancelled")
+	}
+	expectSeats(t, repo, event.ID, 0, 0)
+	expectStatus(t, repo, reservation.ID, models.BookingStatusCancelled)
+	expectStatus(t, repo, confirmation.ID, models.BookingStatusCancelled)
+
+	_, err = repo.CancelEventWithTransaction(ctx, event.ID)
+	expectError(t, err, apperrors.EventCancelled)
+
+	_, err = repo.BookEventWithTransaction(ctx, event.ID, reserved.ID, 0)
+	expectError(t, err, apperrors.EventCancelled)
+
+	_, err = repo.CancelEventWithTransaction(ctx, uuid.New())
+	expectError(t, err, apperrors.EventNotFound)
+}
+
+func testReminders(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 5, true, 24*time.Hour)
+	user := newUser(t, repo, "ivan", nil)
+
+	booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+
+	due, err := repo.GetBookingsForReminder(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetBookingsForReminder: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("booking with a deadline after the window must not be due, got %d", len(due))
+	}
+
+	due, err = repo.GetBookingsForReminder(ctx, booking.Deadline.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetBookingsForReminder: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != booking.ID {
+		t.Fatalf("GetBookingsForReminder returned %d bookings, want the reservation", len(due))
+	}
+
+	if err := repo.MarkBookingReminded(ctx, booking.ID); err != nil {
+		t.Fatalf("MarkBookingReminded: %v", err)
+	}
+
+	due, err = repo.GetBookingsForReminder(ctx, booking.Deadline.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetBookingsForReminder: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("reminded booking must not be due again")
+	}
+
+	expired, err := repo.GetExpiredReservedBookings(ctx)
+	if err != nil {
+		t.Fatalf("GetExpiredReservedBookings: %v", err)
+	}
+	if len(expired) != 0 {
+		t.Fatalf("reservation before its deadline must not be expired")
+	}
+}
+
+func testSubscriptions(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	upcoming := newEventOpening(t, h, repo, 5, 24*time.Hour, time.Hour)
+	onSale := newEvent(t, h, repo, 5, false, 24*time.Hour)
+	cancelled := newEvent(t, h, repo, 5, false, 24*time.Hour)
+	user := newUser(t, repo, "ivan", nil)
+
+	for _, event := range []*models.Event{upcoming, onSale, upcoming, cancelled} {
+		err := repo.CreateSubscription(ctx, &models.Subscription{
+			EventID:   event.ID,
+			UserID:    user.ID,
+			CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		})
+		if err != nil {
+			t.Fatalf("CreateSubscription: %v", err)
+		}
+	}
+	if _, err := repo.CancelEventWithTransaction(ctx, cancelled.ID); err != nil {
+		t.Fatalf("CancelEventWithTransaction: %v", err)
+	}
+
+	due, err := repo.GetSubscriptionsForSalesOpen(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetSubscriptionsForSalesOpen: %v", err)
+	}
+	if len(due) != 1 || due[0].EventID != onSale.ID || due[0].UserID != user.ID {
+		t.Fatalf("GetSubscriptionsForSalesOpen returned %+v, want the event on sale", due)
+	}
+
+	if err := repo.MarkSubscriptionNotified(ctx, onSale.ID, user.ID); err != nil {
+		t.Fatalf("MarkSubscriptionNotified: %v", err)
+	}
+
+	due, err = repo.GetSubscriptionsForSalesOpen(ctx, upcoming.SalesOpenAt.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetSubscriptionsForSalesOpen: %v", err)
+	}
+	if len(due) != 1 || due[0].EventID != upcoming.ID {
+		t.Fatalf("GetSubscriptionsForSalesOpen returned %+v, want the subscription once sales opened", due)
+	}
+
+	due, err = repo.GetSubscriptionsForSalesOpen(ctx, upcoming.SalesCloseAt)
+	if err != nil {
+		t.Fatalf("GetSubscriptionsForSalesOpen: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("subscriptions must not be due after sales closed, got %d", len(due))
+	}
+
+	err = repo.CreateSubscription(ctx, &models.Subscription{EventID: uuid.New(), UserID: user.ID, CreatedAt: time.Now()})
+	expectError(t, err, apperrors.EventNotFound)
+}
+
+func testConcurrentBookings(t *testing.T, repo repository.RepositoryI, h Harness) {
+	const (
+		seats   = 5
+		players = 25
+	)
+
+	ctx := context.Background()
+	event := newEvent(t, h, repo, seats, true, 24*time.Hour)
+
+	users := make([]*models.User, players)
+	for i := range users {
+		users[i] = newUser(t, repo, fmt.Sprintf("p%d", i), nil)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		booked  int
+		soldOut int
+		failed  []error
+	)
+	for _, user := range users {
+		wg.Add(1)
+		go func(userID uuid.UUID) {
+			defer wg.Done()
+
+			_, err := repo.BookEventWithTransaction(ctx, event.ID, userID, 0)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				booked++
+			case errors.Is(err, apperrors.NoAvailableSeats):
+				soldOut++
+			default:
+				failed = append(failed, err)
+			}
+		}(user.ID)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		t.Fatalf("unexpected errors: %v", failed)
+	}
+	if booked != seats || soldOut != players-seats {
+		t.Fatalf("booked=%d soldOut=%d, want %d and %d", booked, soldOut, seats, players-seats)
+	}
+	expectSeats(t, repo, event.ID, seats, 0)
+}
+
+func testConcurrentDuplicateBookings(t *testing.T, repo repository.RepositoryI, h Harness) {
+	const attempts = 10
+
+	ctx := context.Background()
+	event := newEvent(t, h, repo, attempts, true, 24*time.Hour)
+	user := newUser(t, repo, "twin", nil)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		booked  int
+		refused int
+		failed  []error
+	)
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				booked++
+			case errors.Is(err, apperrors.UserAlreadyBookedThisEvent):
+				refused++
+			default:
+				failed = append(failed, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		t.Fatalf("unexpected errors: %v", failed)
+	}
+	if booked != 1 || refused != attempts-1 {
+		t.Fatalf("booked=%d refused=%d, want 1 and %d", booked, refused, attempts-1)
+	}
+	expectSeats(t, repo, event.ID, 1, 0)
+}
+
+func testReservationQuota(t *testing.T, repo repository.RepositoryI, h Harness) {
+	const limit = 2
+
+	ctx := context.Background()
+	user := newUser(t, repo, "hoarder", nil)
+
+	paid := make([]*models.Event, limit+1)
+	for i := range paid {
+		paid[i] = newEvent(t, h, repo, 5, true, 24*time.Hour)
+	}
+
+	var first *models.Booking
+	for _, event := range paid[:limit] {
+		booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, limit)
+		if err != nil {
+			t.Fatalf("BookEventWithTransaction: %v", err)
+		}
+		if first == nil {
+			first = booking
+		}
+	}
+
+	_, err := repo.BookEventWithTransaction(ctx, paid[limit].ID, user.ID, limit)
+	expectError(t, err, apperrors.TooManyActiveReservations)
+	expectSeats(t, repo, paid[limit].ID, 0, 0)
+
+	free := newEvent(t, h, repo, 5, false, 24*time.Hour)
+	if _, err := repo.BookEventWithTransaction(ctx, free.ID, user.ID, limit); err != nil {
+		t.Fatalf("free events must not count against the quota: %v", err)
+	}
+
+	if err := repo.ConfirmBookingWithTransaction(ctx, first.ID); err != nil {
+		t.Fatalf("ConfirmBookingWithTransaction: %v", err)
+	}
+	if _, err := repo.BookEventWithTransaction(ctx, paid[limit].ID, user.ID, limit); err != nil {
+		t.Fatalf("a confirmed booking must free a reservation slot: %v", err)
+	}
+
+	other := newUser(t, repo, "bystander", nil)
+	if _, err := repo.BookEventWithTransaction(ctx, paid[0].ID, other.ID, limit); err != nil {
+		t.Fatalf("the quota is per user: %v", err)
+	}
+}
+
+func testConcurrentReservationQuota(t *testing.T, repo repository.RepositoryI, h Harness) {
+	const (
+		limit  = 2
+		events = 8
+	)
+
+	ctx := context.Background()
+	user := newUser(t, repo, "rusher", nil)
+
+	ids := make([]uuid.UUID, events)
+	for i := range ids {
+		ids[i] = newEvent(t, h, repo, 5, true, 24*time.Hour).ID
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		booked  int
+		refused int
+		failed  []error
+	)
+	for _, eventID := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := repo.BookEventWithTransaction(ctx, eventID, user.ID, limit)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				booked++
+			case errors.Is(err, apperrors.TooManyActiveReservations):
+				refused++
+			default:
+				failed = append(failed, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		t.Fatalf("unexpected errors: %v", failed)
+	}
+	if booked != limit || refused != events-limit {
+		t.Fatalf("booked=%d refused=%d, want %d and %d", booked, refused, limit, events-limit)
+	}
+}
+
+func testJobRuns(t *testing.T, repo repository.RepositoryI, _ Harness) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	for i, age := range []time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour} {
+		run := &models.JobRun{
+			ID:         uuid.New(),
+			JobName:    fmt.Sprintf("job-%d", i),
+			Trigger:    models.JobTriggerSchedule,
+			Status:     models.JobRunStatusSuccess,
+			StartedAt:  now.Add(-age),
+			FinishedAt: now.Add(-age).Add(time.Second),
+			DurationMs: 1000,
+		}
+		if err := repo.CreateJobRun(ctx, run); err != nil {
+			t.Fatalf("CreateJobRun: %v", err)
+		}
+	}
+
+	runs, err := repo.ListJobRuns(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListJobRuns: %v", err)
+	}
+	if len(runs) != 2 || runs[0].JobName != "job-1" || runs[1].JobName != "job-2" {
+		t.Fatalf("ListJobRuns must return the newest runs first, got %v", jobNames(runs))
+	}
+
+	deleted, err := repo.DeleteJobRunsBefore(ctx, now.Add(-90*time.Minute))
+	if err != nil {
+		t.Fatalf("DeleteJobRunsBefore: %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("deleted %d runs, want 2", deleted)
+	}
+
+	runs, err = repo.ListJobRuns(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListJobRuns: %v", err)
+	}
+	if len(runs) != 1 || runs[0].JobName != "job-1" {
+		t.Fatalf("ListJobRuns after cleanup returned %v", jobNames(runs))
+	}
+}
+
+func testStats(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 5, true, 24*time.Hour)
+	newEvent(t, h, repo, 5, true, 24*time.Hour)
+
+	var bookings []*models.Booking
+	for _, name := range []string{"jack", "kate", "liam"} {
+		booking, err := repo.BookEventWithTransaction(ctx, event.ID, newUser(t, repo, name, nil).ID, 0)
+		if err != nil {
+			t.Fatalf("BookEventWithTransaction: %v", err)
+		}
+		bookings = append(bookings, booking)
+	}
+	if err := repo.ConfirmBookingWithTransaction(ctx, bookings[0].ID); err != nil {
+		t.Fatalf("ConfirmBookingWithTransaction: %v", err)
+	}
+	if err := repo.CancelExpiredBookingWithTransaction(ctx, bookings[1].ID); err != nil {
+		t.Fatalf("CancelExpiredBookingWithTransaction: %v", err)
+	}
+
+	stats, err := repo.GetBookingStats(ctx)
+	if err != nil {
+		t.Fatalf("GetBookingStats: %v", err)
+	}
+	want := models.BookingStats{Events: 2, Reserved: 1, Confirmed: 1, Cancelled: 1}
+	if *stats != want {
+		t.Fatalf("GetBookingStats = %+v, want %+v", *stats, want)
+	}
+}
+
+func testSeatDrift(t *testing.T, repo repository.RepositoryI, h Harness) {
+	ctx := context.Background()
+
+	event := newEvent(t, h, repo, 5, true, 24*time.Hour)
+	if _, err := repo.BookEventWithTransaction(ctx, event.ID, newUser(t, repo, "mia", nil).ID, 0); err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+
+	drifts, err := repo.FindSeatDrift(ctx)
+	if err != nil {
+		t.Fatalf("FindSeatDrift: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Fatalf("consistent counters reported as drift: %+v", drifts[0])
+	}
+
+	drift, err := repo.RepairSeatCountersWithTransaction(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("RepairSeatCountersWithTransaction: %v", err)
+	}
+	if drift.Repaired || drift.HasDrift() {
+		t.Fatalf("consistent counters must not be repaired: %+v", drift)
+	}
+
+	if h.SetEventSeats == nil {
+		return
+	}
+	h.SetEventSeats(t, repo, event.ID, 3, 2)
+
+	drifts, err = repo.FindSeatDrift(ctx)
+	if err != nil {
+		t.Fatalf("FindSeatDrift: %v", err)
+	}
+	if len(drifts) != 1 || drifts[0].ActualReserved != 1 || drifts[0].ActualBooked != 0 {
+		t.Fatalf("FindSeatDrift returned %+v", drifts)
+	}
+
+	drift, err = repo.RepairSeatCountersWithTransaction(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("RepairSeatCountersWithTransaction: %v", err)
+	}
+	if !drift.Repaired {
+		t.Fatalf("drift was not repaired: %+v", drift)
+	}
+	expectSeats(t, repo, event.ID, 1, 0)
+
+	_, err = repo.RepairSeatCountersWithTransaction(ctx, uuid.New())
+	expectError(t, err, apperrors.EventNotFound)
+}
+
+func testIdempotencyKeys(t *testing.T, repo repository.RepositoryI, _ Harness) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	acquire := func(name, fingerprint string, at time.Time, ttl time.Duration) *models.IdempotencyKey {
+		t.Helper()
+		stored, err := repo.AcquireIdempotencyKey(ctx, &models.IdempotencyKey{
+			Key:         name,
+			Fingerprint: fingerprint,
+			CreatedAt:   at,
+			ExpiresAt:   at.Add(ttl),
+		})
+		if err != nil {
+			t.Fatalf("AcquireIdempotencyKey(%s): %v", name, err)
+		}
+		return stored
+	}
+
+	if stored := acquire("k1", "aaa", now, time.Hour); stored != nil {
+		t.Fatalf("new key returned %+v, want nil", stored)
+	}
+
+	stored := acquire("k1", "bbb", now.Add(time.Minute), time.Hour)
+	if stored == nil || stored.Fingerprint != "aaa" || stored.Completed() {
+		t.Fatalf("pending key returned %+v, want the first fingerprint without a response", stored)
+	}
+
+	err := repo.CompleteIdempotencyKey(ctx, &models.IdempotencyKey{
+		Key:         "k1",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"ok":true}`),
+	})
+	if err != nil {
+		t.Fatalf("CompleteIdempotencyKey: %v", err)
+	}
+
+	stored = acquire("k1", "aaa", now.Add(time.Minute), time.Hour)
+	if stored == nil || stored.StatusCode != 201 || stored.ContentType != "application/json" || string(stored.Body) != `{"ok":true}` {
+		t.Fatalf("completed key returned %+v", stored)
+	}
+
+	if stored := acquire("k1", "ccc", now.Add(2*time.Hour), time.Hour); stored != nil {
+		t.Fatalf("expired key returned %+v, want it to be taken over", stored)
+	}
+	if stored := acquire("k1", "ddd", now.Add(2*time.Hour), time.Hour); stored == nil || stored.Fingerprint != "ccc" || stored.Completed() {
+		t.Fatalf("taken over key returned %+v, want the new fingerprint without a response", stored)
+	}
+
+	acquire("k2", "aaa", now, time.Hour)
+	if err := repo.DeleteIdempotencyKey(ctx, "k2"); err != nil {
+		t.Fatalf("DeleteIdempotencyKey: %v", err)
+	}
+	if stored := acquire("k2", "bbb", now, time.Hour); stored != nil {
+		t.Fatalf("deleted key returned %+v, want nil", stored)
+	}
+
+	deleted, err := repo.DeleteIdempotencyKeysBefore(ctx, now.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("DeleteIdempotencyKeysBefore: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted %d keys, want only k2", deleted)
+	}
+}
+
+func newEvent(t *testing.T, h Harness, repo repository.RepositoryI, seats int, paid bool, in time.Duration) *models.Event {
+	t.Helper()
+	return newEventClosing(t, h, repo, seats, paid, in, in)
+}
+
+// newEventClosing creates an event that starts in the given time and stops
+// taking bookings after closesIn.
+func newEventClosing(t *testing.T, h Harness, repo repository.RepositoryI, seats int, paid bool, in, closesIn time.Duration) *models.Event {
+	t.Helper()
+	return createEvent(t, h, repo, seats, paid, in, closesIn, nil)
+}
+
+// newEventOpening creates a free event whose ticket sales open after opensIn
+// and close at its start.
+func newEventOpening(t *testing.T, h Harness, repo repository.RepositoryI, seats int, in, opensIn time.Duration) *models.Event {
+	t.Helper()
+	opensAt := time.Now().Add(opensIn).UTC().Truncate(time.Microsecond)
+	return createEvent(t, h, repo, seats, false, in, in, &opensAt)
+}
+
+func createEvent(t *testing.T, h Harness, repo repository.RepositoryI, seats int, paid bool, in, closesIn time.Duration, opensAt *time.Time) *models.Event {
+	t.Helper()
+
+	event := &models.Event{
+		ID:              uuid.New(),
+		Name:            "event " + uuid.NewString()[:8],
+		Category:        models.EventCategoryOther,
+		Date:            time.Now().Add(in).UTC().Truncate(time.Microsecond),
+		EndDate:         time.Now().Add(in + 2*time.Hour).UTC().Truncate(time.Microsecond),
+		TimeZone:        "UTC",
+		SalesOpenAt:     opensAt,
+		SalesCloseAt:    time.Now().Add(closesIn).UTC().Truncate(time.Microsecond),
+		TotalSeats:      seats,
+		BookingLifetime: 30,
+		PaymentReq:      paid,
+		Inventory:       h.inventory,
+		CreatedAt:       time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if err := repo.CreateEvent(context.Background(), event); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	return event
+}
+
+func newVenue(t *testing.T, repo repository.RepositoryI, name string) *models.Venue {
+	t.Helper()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	venue := &models.Venue{
+		ID:              uuid.New(),
+		Name:            name,
+		Address:         "Tverskaya 1, Moscow",
+		Latitude:        55.757,
+		Longitude:       37.615,
+		TimeZone:        "Europe/Moscow",
+		DefaultCapacity: 200,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := repo.CreateVenue(context.Background(), venue); err != nil {
+		t.Fatalf("CreateVenue: %v", err)
+	}
+
+	return venue
+}
+
+func newUser(t *testing.T, repo repository.RepositoryI, name string, telegramID *int64) *models.User {
+	t.Helper()
+
+	user := &models.User{
+		ID:         uuid.New(),
+		Name:       name,
+		Email:      name + "@example.com",
+		TelegramID: telegramID,
+		Role:       models.UserRoleUser,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if err := repo.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	return user
+}
+
+func expectSeats(t *testing.T, repo repository.RepositoryI, eventID uuid.UUID, reserved, booked int) {
+	t.Helper()
+
+	event, err := repo.GetEventByID(context.Background(), eventID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if event.ReservedSeats != reserved || event.BookedSeats != booked {
+		t.Fatalf("seats reserved=%d booked=%d, want %d and %d", event.ReservedSeats, event.BookedSeats, reserved, booked)
+	}
+}
+
+func expectStatus(t *testing.T, repo repository.RepositoryI, bookingID uuid.UUID, status models.BookingStatus) {
+	t.Helper()
+
+	booking, err := repo.GetBookingByID(context.Background(), bookingID)
+	if err != nil {
+		t.Fatalf("GetBookingByID: %v", err)
+	}
+	if booking.Status != status {
+		t.Fatalf("booking status = %s, want %s", booking.Status, status)
+	}
+}
+
+func expectError(t *testing.T, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf("error = %v, want %v", err, target)
+	}
+}
+
+func eventIDs(events []*models.Event) []uuid.UUID {
+	ids := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func jobNames(runs []*models.JobRun) []string {
+	names := make([]string, len(runs))
+	for i, run := range runs {
+		names[i] = run.JobName
+	}
+	return names
+}
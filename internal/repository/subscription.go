@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+func (r *Repository) CreateSubscription(ctx context.Context, subscription *models.Subscription) error {
+	_, err := r.conn.Exec(ctx, createSubscriptionQuery,
+		subscription.EventID,
+		subscription.UserID,
+		subscription.CreatedAt)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == foreignKeyViolation {
+			switch pgError.ConstraintName {
+			case "event_subscriptions_event_id_fkey":
+				return apperrors.EventNotFound
+			case "event_subscriptions_user_id_fkey":
+				return apperrors.UserNotFound
+			}
+		}
+		return fmt.Errorf("Exec-CreateSubscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetSubscriptionsForSalesOpen(ctx context.Context, now time.Time) ([]*models.Subscription, error) {
+	rows, err := r.conn.Query(ctx, getSubscriptionsForSalesOpenQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("Query-GetSubscriptionsForSalesOpen: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*models.Subscription
+	for rows.Next() {
+		subscription := new(models.Subscription)
+		if err := rows.Scan(
+			&subscription.EventID,
+			&subscription.UserID,
+			&subscription.CreatedAt,
+			&subscription.NotifiedAt,
+		); err != nil {
+			return nil, fmt.Errorf("GetSubscriptionsForSalesOpen scan: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetSubscriptionsForSalesOpen rows.Err: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *Repository) MarkSubscriptionNotified(ctx context.Context, eventID, userID uuid.UUID) error {
+	_, err := r.conn.Exec(ctx, markSubscriptionNotifiedQuery, eventID, userID)
+	if err != nil {
+		return fmt.Errorf("Exec-MarkSubscriptionNotified: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedRepository wraps every transactional method in its own span so that
+// lock waits and commits are visible next to the individual pgx query spans.
+type tracedRepository struct {
+	RepositoryI
+}
+
+func WithTracing(next RepositoryI) RepositoryI {
+	return &tracedRepository{
+		RepositoryI: next,
+	}
+}
+
+func (r *tracedRepository) BookEventWithTransaction(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+	maxReservations int,
+) (*models.Booking, error) {
+	ctx, span := tracing.Start(ctx, "Transaction.BookEvent", trace.WithAttributes(
+		attribute.String("event.id", eventID.String()),
+		attribute.String("user.id", userID.String()),
+	))
+	booking, err := r.RepositoryI.BookEventWithTransaction(ctx, eventID, userID, maxReservations)
+	tracing.End(span, err)
+
+	return booking, err
+}
+
+func (r *tracedRepository) ConfirmBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Transaction.ConfirmBooking", bookingAttr(bookingID))
+	err := r.RepositoryI.ConfirmBookingWithTransaction(ctx, bookingID)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (r *tracedRepository) CancelExpiredBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Transaction.CancelExpiredBooking", bookingAttr(bookingID))
+	err := r.RepositoryI.CancelExpiredBookingWithTransaction(ctx, bookingID)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (r *tracedRepository) CancelBookingWithTransaction(ctx context.Context, bookingID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Transaction.CancelBooking", bookingAttr(bookingID))
+	err := r.RepositoryI.CancelBookingWithTransaction(ctx, bookingID)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (r *tracedRepository) CancelEventWithTransaction(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	ctx, span := tracing.Start(ctx, "Transaction.CancelEvent", trace.WithAttributes(
+		attribute.String("event.id", eventID.String()),
+	))
+	cancelled, err := r.RepositoryI.CancelEventWithTransaction(ctx, eventID)
+	tracing.End(span, err)
+
+	return cancelled, err
+}
+
+func (r *tracedRepository) RepairSeatCountersWithTransaction(
+	ctx context.Context,
+	eventID uuid.UUID,
+) (*models.SeatDrift, error) {
+	ctx, span := tracing.Start(ctx, "Transaction.RepairSeatCounters", trace.WithAttributes(
+		attribute.String("event.id", eventID.String()),
+	))
+	drift, err := r.RepositoryI.RepairSeatCountersWithTransaction(ctx, eventID)
+	tracing.End(span, err)
+
+	return drift, err
+}
+
+func bookingAttr(id uuid.UUID) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.String("booking.id", id.String()))
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	deadlockDetected = "40P01"
+	lockNotAvailable = "55P03"
+)
+
+// conflictReasons lists the errors that abort a transaction only because of
+// concurrent ones. Transactions run under READ COMMITTED, so serialization
+// failures do not occur.
+var conflictReasons = map[string]string{
+	deadlockDetected: "deadlock",
+	lockNotAvailable: "lock_timeout",
+}
+
+// withTx runs fn in a transaction with the configured lock timeout and retries
+// it from scratch with exponential backoff when Postgres aborts it because of
+// a deadlock or a lock timeout. Once the retries are used up the caller gets
+// apperrors.ResourceBusy.
+func (r *Repository) withTx(ctx context.Context, op string, fn func(tx pgx.Tx) error) error {
+	backoff := r.tx.retryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := r.runTx(ctx, op, fn)
+		if err == nil {
+			return nil
+		}
+
+		code := pgErrorCode(err)
+		reason, ok := conflictReasons[code]
+		if !ok {
+			return err
+		}
+
+		metrics.TxConflicts.WithLabelValues(op, reason).Inc()
+		if attempt >= r.tx.maxRetries {
+			return fmt.Errorf("%w: %w", apperrors.ResourceBusy, err)
+		}
+
+		delay := backoff + rand.N(backoff+1)
+		logging.FromContext(ctx).Warnf("%s: transaction aborted (%s), retrying in %s", op, code, delay)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", op, ctx.Err())
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
+
+func (r *Repository) runTx(ctx context.Context, op string, fn func(tx pgx.Tx) error) error {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("BeginTx-%s: %w", op, err)
+	}
+
+	defer func() {
+		rbErr := tx.Rollback(context.Background())
+		if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			logging.FromContext(ctx).Errorf("Rollback-%s: %v", op, rbErr)
+		}
+	}()
+
+	if r.tx.lockTimeout > 0 {
+		_, err = tx.Exec(ctx, setLockTimeoutQuery, fmt.Sprintf("%dms", r.tx.lockTimeout.Milliseconds()))
+		if err != nil {
+			return fmt.Errorf("setLockTimeout-%s: %w", op, err)
+		}
+	}
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("Commit-%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func pgErrorCode(err error) string {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) {
+		return pgError.Code
+	}
+	return ""
+}
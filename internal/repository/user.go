@@ -18,6 +18,7 @@ func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
 		user.Name,
 		user.Email,
 		user.TelegramID,
+		user.Role,
 		user.CreatedAt)
 	if err != nil {
 		var pgError *pgconn.PgError
@@ -47,6 +48,7 @@ func (r *Repository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.Use
 		&user.Email,
 		&telegramID,
 		&user.CreatedAt,
+		&user.Role,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -72,6 +74,7 @@ func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.
 		&user.Email,
 		&telegramID,
 		&user.CreatedAt,
+		&user.Role,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -87,3 +90,16 @@ func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.
 
 	return &user, nil
 }
+
+func (r *Repository) UpdateUserRole(ctx context.Context, id uuid.UUID, role models.UserRole) error {
+	tag, err := r.conn.Exec(ctx, updateUserRoleQuery, id, role)
+	if err != nil {
+		return fmt.Errorf("Exec-UpdateUserRole: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperrors.UserNotFound
+	}
+
+	return nil
+}
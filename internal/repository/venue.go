@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+)
+
+const foreignKeyViolation = "23503"
+
+func (r *Repository) CreateVenue(ctx context.Context, venue *models.Venue) error {
+	_, err := r.conn.Exec(ctx, createVenueQuery,
+		venue.ID,
+		venue.Name,
+		venue.Address,
+		venue.Latitude,
+		venue.Longitude,
+		venue.TimeZone,
+		venue.DefaultCapacity,
+		venue.CreatedAt,
+		venue.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("Exec-CreateVenue: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetVenueByID(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
+	venue, err := scanVenue(r.conn.QueryRow(ctx, getVenueByIDQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.VenueNotFound
+		}
+		return nil, fmt.Errorf("QueryRow-GetVenueByID: %w", err)
+	}
+
+	return venue, nil
+}
+
+func (r *Repository) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	rows, err := r.conn.Query(ctx, listVenuesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("Query-ListVenues: %w", err)
+	}
+	defer rows.Close()
+
+	var venues []*models.Venue
+	for rows.Next() {
+		venue, err := scanVenue(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Scan-ListVenues: %w", err)
+		}
+		venues = append(venues, venue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Rows-ListVenues: %w", err)
+	}
+
+	return venues, nil
+}
+
+func (r *Repository) UpdateVenue(ctx context.Context, venue *models.Venue) error {
+	tag, err := r.conn.Exec(ctx, updateVenueQuery,
+		venue.ID,
+		venue.Name,
+		venue.Address,
+		venue.Latitude,
+		venue.Longitude,
+		venue.TimeZone,
+		venue.DefaultCapacity,
+		venue.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("Exec-UpdateVenue: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.VenueNotFound
+	}
+
+	return nil
+}
+
+// DeleteVenue refuses to delete a venue that any event, past or cancelled
+// included, still refers to.
+func (r *Repository) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.conn.Exec(ctx, deleteVenueQuery, id)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == foreignKeyViolation {
+			return apperrors.VenueInUse
+		}
+		return fmt.Errorf("Exec-DeleteVenue: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.VenueNotFound
+	}
+
+	return nil
+}
+
+func scanVenue(row pgx.Row) (*models.Venue, error) {
+	var venue models.Venue
+
+	err := row.Scan(
+		&venue.ID,
+		&venue.Name,
+		&venue.Address,
+		&venue.Latitude,
+		&venue.Longitude,
+		&venue.TimeZone,
+		&venue.DefaultCapacity,
+		&venue.CreatedAt,
+		&venue.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &venue, nil
+}
@@ -2,61 +2,355 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
 	"github.com/gookit/slog"
-	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	recordTimeout = 5 * time.Second
+	// stallGrace is how late a job may start, and how long past its timeout
+	// a run may go on, before the scheduler is reported as stalled.
+	stallGrace = time.Minute
+)
+
+type Task func(ctx context.Context) error
+
+// Job describes a named periodic task. Spec accepts standard five-field cron
+// expressions as well as descriptors such as "@hourly" or "@every 30s".
+type Job struct {
+	Name       string
+	Spec       string
+	Jitter     time.Duration
+	Timeout    time.Duration
+	RunOnStart bool
+	Task       Task
+}
+
+type JobStatus struct {
+	Name    string
+	Spec    string
+	Running bool
+	NextRun time.Time
+	LastRun *models.JobRun
+}
+
 type SchedulerI interface {
-	Start(ctx context.Context, cfg config.Config, task func() error)
+	Register(job Job) error
+	Start(ctx context.Context)
 	Stop()
+	Jobs() []JobStatus
+	RunNow(name string) error
+	History(ctx context.Context, limit int) ([]*models.JobRun, error)
+	Check(ctx context.Context) error
 }
 
 type Scheduler struct {
-	ticker *time.Ticker
-	done   chan bool
+	repo repository.RepositoryI
+
+	mu     sync.RWMutex
+	jobs   map[string]*entry
+	order  []string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type entry struct {
+	job      Job
+	schedule cron.Schedule
+	running  atomic.Bool
+
+	mu      sync.RWMutex
+	nextRun time.Time
+	lastRun *models.JobRun
+	// startedAt is the start of the run in progress, zero between runs.
+	startedAt time.Time
 }
 
-func NewScheduler() SchedulerI {
+func NewScheduler(repo repository.RepositoryI) SchedulerI {
 	return &Scheduler{
-		done: make(chan bool),
+		repo: repo,
+		jobs: make(map[string]*entry),
+	}
+}
+
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+
+	if job.Task == nil {
+		return fmt.Errorf("job %s: task is required", job.Name)
+	}
+
+	schedule, err := cron.ParseStandard(job.Spec)
+	if err != nil {
+		return fmt.Errorf("job %s: invalid schedule %q: %w", job.Name, job.Spec, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+
+	s.jobs[job.Name] = &entry{
+		job:      job,
+		schedule: schedule,
+	}
+	s.order = append(s.order, job.Name)
+
+	return nil
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	entries := make([]*entry, 0, len(s.order))
+	for _, name := range s.order {
+		entries = append(entries, s.jobs[name])
+	}
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		slog.Infof("Scheduling job %s with spec %q", e.job.Name, e.job.Spec)
+		s.wg.Add(1)
+		go s.loop(s.ctx, e)
+	}
+}
+
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	slog.Info("Scheduler stopping")
+	cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		e := s.jobs[name]
+
+		e.mu.RLock()
+		statuses = append(statuses, JobStatus{
+			Name:    e.job.Name,
+			Spec:    e.job.Spec,
+			Running: e.running.Load(),
+			NextRun: e.nextRun,
+			LastRun: e.lastRun,
+		})
+		e.mu.RUnlock()
 	}
+
+	return statuses
 }
 
-func (s *Scheduler) Start(ctx context.Context, cfg config.Config, task func() error) {
-	interval := time.Duration(cfg.Scheduler.CheckInterval) * time.Second
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.RLock()
+	e, ok := s.jobs[name]
+	ctx := s.ctx
+	s.mu.RUnlock()
 
-	s.ticker = time.NewTicker(interval)
+	if !ok {
+		return apperrors.JobNotFound
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
+	if !e.running.CompareAndSwap(false, true) {
+		return apperrors.JobAlreadyRunning
+	}
+
+	s.wg.Add(1)
 	go func() {
-		if err := task(); err != nil {
-			slog.Error("Scheduler task error", "error", err)
-		}
-		for {
-			select {
-			case <-ctx.Done():
-				slog.Info("Scheduler stopping due to context cancellation")
-				s.Stop()
-				return
-			case <-s.done:
-				slog.Info("Scheduler stopping")
-				return
-			case <-s.ticker.C:
-				slog.Debug("Scheduler executing task")
-				if err := task(); err != nil {
-					slog.Error("Scheduler task error", "error", err)
-				}
+		defer s.wg.Done()
+		s.execute(ctx, e, models.JobTriggerManual)
+	}()
+
+	return nil
+}
+
+func (s *Scheduler) History(ctx context.Context, limit int) ([]*models.JobRun, error) {
+	return s.repo.ListJobRuns(ctx, limit)
+}
+
+// Check reports whether scheduled work is progressing: every job has to be
+// started at most stallGrace after it was due, and a run must not go on for
+// longer than stallGrace past its timeout, which means its task ignores the
+// cancellation.
+func (s *Scheduler) Check(ctx context.Context) error {
+	return s.check(time.Now())
+}
+
+func (s *Scheduler) check(now time.Time) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.cancel == nil {
+		return errors.New("scheduler is not started")
+	}
+
+	var problems []string
+	for _, name := range s.order {
+		e := s.jobs[name]
+
+		e.mu.RLock()
+		nextRun, startedAt := e.nextRun, e.startedAt
+		e.mu.RUnlock()
+
+		switch {
+		case !startedAt.IsZero():
+			if e.job.Timeout > 0 && now.Sub(startedAt) > e.job.Timeout+stallGrace {
+				problems = append(problems, fmt.Sprintf("job %s has been running since %s", name, startedAt.Format(time.RFC3339)))
 			}
+		case e.running.Load():
+			// The run has been claimed and is about to start.
+		case !nextRun.IsZero() && now.Sub(nextRun) > stallGrace:
+			problems = append(problems, fmt.Sprintf("job %s is overdue since %s", name, nextRun.UTC().Format(time.RFC3339)))
 		}
-	}()
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
 }
 
-func (s *Scheduler) Stop() {
-	if s.ticker != nil {
-		s.ticker.Stop()
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.wg.Done()
+
+	if e.job.RunOnStart {
+		s.trigger(ctx, e)
 	}
-	select {
-	case s.done <- true:
-	default:
+
+	for {
+		next := e.schedule.Next(time.Now())
+		if e.job.Jitter > 0 {
+			next = next.Add(rand.N(e.job.Jitter))
+		}
+
+		e.mu.Lock()
+		e.nextRun = next
+		e.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			slog.Debug("Scheduler executing job", "job", e.job.Name)
+			s.trigger(ctx, e)
+		}
+	}
+}
+
+func (s *Scheduler) trigger(ctx context.Context, e *entry) {
+	if !e.running.CompareAndSwap(false, true) {
+		slog.Warn("Scheduler job is still running, skipping", "job", e.job.Name)
+
+		now := time.Now().UTC()
+		s.finish(e, &models.JobRun{
+			ID:         uuid.New(),
+			JobName:    e.job.Name,
+			Trigger:    models.JobTriggerSchedule,
+			Status:     models.JobRunStatusSkipped,
+			StartedAt:  now,
+			FinishedAt: now,
+		})
+		return
+	}
+
+	s.execute(ctx, e, models.JobTriggerSchedule)
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry, trigger models.JobTrigger) {
+	defer e.running.Store(false)
+
+	runCtx, span := tracing.Start(ctx, "Job "+e.job.Name, trace.WithAttributes(
+		attribute.String("job.name", e.job.Name),
+		attribute.String("job.trigger", string(trigger)),
+	))
+	if e.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, e.job.Timeout)
+		defer cancel()
+	}
+
+	run := &models.JobRun{
+		ID:        uuid.New(),
+		JobName:   e.job.Name,
+		Trigger:   trigger,
+		Status:    models.JobRunStatusSuccess,
+		StartedAt: time.Now().UTC(),
+	}
+	runCtx = logging.WithFields(runCtx, slog.M{"job": e.job.Name, "job_run_id": run.ID})
+
+	e.mu.Lock()
+	e.startedAt = run.StartedAt
+	e.mu.Unlock()
+
+	err := e.job.Task(runCtx)
+	tracing.End(span, err)
+
+	e.mu.Lock()
+	e.startedAt = time.Time{}
+	e.mu.Unlock()
+
+	run.FinishedAt = time.Now().UTC()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+
+	if err != nil {
+		logging.FromContext(runCtx).WithError(err).Error("Scheduler job error")
+		msg := err.Error()
+		run.Status = models.JobRunStatusFailed
+		run.Error = &msg
+	}
+
+	s.finish(e, run)
+}
+
+func (s *Scheduler) finish(e *entry, run *models.JobRun) {
+	e.mu.Lock()
+	e.lastRun = run
+	e.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+
+	if err := s.repo.CreateJobRun(ctx, run); err != nil {
+		slog.Error("Failed to record job run", "job", run.JobName, "error", err)
 	}
 }
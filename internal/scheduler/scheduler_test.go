@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jobRuns keeps the recorded runs in memory.
+type jobRuns struct {
+	repository.RepositoryI
+
+	mu   sync.Mutex
+	runs []*models.JobRun
+}
+
+func (r *jobRuns) CreateJobRun(ctx context.Context, run *models.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+// waitForRuns returns the recorded runs once there are n of them.
+func (r *jobRuns) waitForRuns(t *testing.T, n int) []*models.JobRun {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		r.mu.Lock()
+		runs := append([]*models.JobRun(nil), r.runs...)
+		r.mu.Unlock()
+
+		if len(runs) >= n {
+			return runs
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d job runs recorded, want %d", len(runs), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func noop(ctx context.Context) error { return nil }
+
+func TestRegister(t *testing.T) {
+	s := NewScheduler(&jobRuns{})
+	if err := s.Register(Job{Name: "expiry", Spec: "@every 10s", Task: noop}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		job  Job
+		want string
+	}{
+		{"without a name", Job{Spec: "@hourly", Task: noop}, "job name is required"},
+		{"without a task", Job{Name: "reports", Spec: "@hourly"}, "job reports: task is required"},
+		{"invalid spec", Job{Name: "reports", Spec: "often", Task: noop}, `job reports: invalid schedule "often"`},
+		{"duplicate name", Job{Name: "expiry", Spec: "@hourly", Task: noop}, "job expiry is already registered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Register(tt.job)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Register = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if jobs := s.Jobs(); len(jobs) != 1 || jobs[0].Name != "expiry" {
+		t.Errorf("Jobs = %+v, want only expiry", jobs)
+	}
+}
+
+func TestRunNow(t *testing.T) {
+	repo := &jobRuns{}
+	s := NewScheduler(repo)
+
+	release := make(chan struct{})
+	jobs := []Job{
+		{Name: "blocking", Spec: "@every 1h", Task: func(ctx context.Context) error {
+			<-release
+			return nil
+		}},
+		{Name: "failing", Spec: "@every 1h", Task: func(ctx context.Context) error {
+			return errors.New("smtp is down")
+		}},
+	}
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+
+	if err := s.RunNow("missing"); !errors.Is(err, apperrors.JobNotFound) {
+		t.Errorf("RunNow of an unknown job = %v, want %v", err, apperrors.JobNotFound)
+	}
+
+	if err := s.RunNow("blocking"); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if err := s.RunNow("blocking"); !errors.Is(err, apperrors.JobAlreadyRunning) {
+		t.Errorf("RunNow of a running job = %v, want %v", err, apperrors.JobAlreadyRunning)
+	}
+	close(release)
+
+	run := repo.waitForRuns(t, 1)[0]
+	if run.JobName != "blocking" || run.Trigger != models.JobTriggerManual ||
+		run.Status != models.JobRunStatusSuccess || run.Error != nil {
+		t.Errorf("run of blocking = %+v, want a successful manual run", run)
+	}
+
+	if err := s.RunNow("failing"); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	run = repo.waitForRuns(t, 2)[1]
+	if run.Status != models.JobRunStatusFailed || run.Error == nil || *run.Error != "smtp is down" {
+		t.Errorf("run of failing = %+v, want a failed run with the task error", run)
+	}
+
+	for _, status := range s.Jobs() {
+		if status.LastRun == nil || status.LastRun.JobName != status.Name {
+			t.Errorf("status of %s = %+v, want its last run", status.Name, status)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := NewScheduler(&jobRuns{}).(*Scheduler)
+	if err := s.Check(context.Background()); err == nil {
+		t.Fatalf("Check before Start = nil, want an error")
+	}
+
+	release := make(chan struct{})
+	jobs := []Job{
+		{Name: "idle", Spec: "@every 1h", Task: func(ctx context.Context) error { return nil }},
+		{Name: "stuck", Spec: "@every 1h", Timeout: time.Millisecond, Task: func(ctx context.Context) error {
+			// Ignores the cancellation on timeout.
+			<-release
+			return nil
+		}},
+	}
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+	s.Start(context.Background())
+	t.Cleanup(s.Stop)
+	t.Cleanup(func() { close(release) })
+
+	if err := s.Check(context.Background()); err != nil {
+		t.Fatalf("Check of idle jobs = %v", err)
+	}
+
+	if err := s.RunNow("stuck"); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for s.startedAt("stuck").IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatalf("stuck job did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	now := time.Now()
+	if err := s.check(now.Add(stallGrace / 2)); err != nil {
+		t.Errorf("Check within the grace period = %v", err)
+	}
+
+	err := s.check(now.Add(2 * stallGrace))
+	if err == nil || !strings.Contains(err.Error(), "job stuck has been running") {
+		t.Errorf("Check of a run past its timeout = %v", err)
+	}
+
+	// The idle job would have started an hour after Start.
+	err = s.check(now.Add(time.Hour + 2*stallGrace))
+	if err == nil || !strings.Contains(err.Error(), "job idle is overdue") {
+		t.Errorf("Check of a job that was not started when due = %v", err)
+	}
+}
+
+func TestExecuteTracesTask(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	s := NewScheduler(&jobRuns{}).(*Scheduler)
+	spans := make(chan trace.SpanContext, 1)
+	job := Job{Name: "traced", Spec: "@every 1h", Timeout: time.Minute, Task: func(ctx context.Context) error {
+		spans <- trace.SpanContextFromContext(ctx)
+		return nil
+	}}
+	if err := s.Register(job); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	s.Start(context.Background())
+	t.Cleanup(s.Stop)
+
+	if err := s.RunNow("traced"); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if span := <-spans; !span.IsValid() {
+		t.Errorf("task of a job with a timeout runs outside the job span")
+	}
+}
+
+func (s *Scheduler) startedAt(name string) time.Time {
+	e := s.jobs[name]
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.startedAt
+}
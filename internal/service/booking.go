@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/google/uuid"
+	"github.com/gookit/slog"
 	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
 	"github.com/kstsm/wb-event-booker/internal/models"
 	"time"
 )
@@ -18,12 +22,23 @@ func (s *Service) BookEvent(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	logging.AddFields(ctx, slog.M{logging.FieldUserID: user.ID})
 
-	booking, err := s.repo.BookEventWithTransaction(ctx, eventID, user.ID)
+	booking, err := s.repo.BookEventWithTransaction(ctx, eventID, user.ID, s.maxReservations)
 	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.NoAvailableSeats):
+			metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeNoSeats).Inc()
+		case errors.Is(err, apperrors.UserAlreadyBookedThisEvent):
+			metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeAlreadyBooked).Inc()
+		case errors.Is(err, apperrors.TooManyActiveReservations):
+			metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeQuotaExceeded).Inc()
+		}
 		return nil, err
 	}
 
+	metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeCreated).Inc()
+
 	resp := &dto.BookEventResponse{
 		BookingID: booking.ID,
 	}
@@ -51,15 +66,60 @@ func (s *Service) ConfirmBooking(ctx context.Context, eventID uuid.UUID, req *dt
 		return err
 	}
 
-	if event.Date.Before(time.Now()) {
+	if event.IsCancelled() {
+		return apperrors.EventCancelled
+	}
+
+	now := time.Now()
+	if event.HasStarted(now) {
 		return apperrors.EventExpired
 	}
 
+	// Closing the sales only stops new reservations: a booking made in
+	// time can still be paid until the event starts or its deadline passes.
 	if !event.PaymentReq {
 		return apperrors.EventDoesNotRequirePayment
 	}
 
-	return s.repo.ConfirmBookingWithTransaction(ctx, req.BookingID)
+	err = s.repo.ConfirmBookingWithTransaction(ctx, req.BookingID)
+	if err != nil {
+		return err
+	}
+
+	metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeConfirmed).Inc()
+
+	return nil
+}
+
+func (s *Service) CancelBooking(ctx context.Context, eventID, bookingID uuid.UUID) error {
+	booking, err := s.repo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	if booking.EventID != eventID {
+		return apperrors.BookingNotFound
+	}
+
+	err = s.repo.CancelBookingWithTransaction(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeCancelled).Inc()
+
+	return nil
+}
+
+func (s *Service) ExpireBooking(ctx context.Context, bookingID uuid.UUID) error {
+	err := s.repo.CancelExpiredBookingWithTransaction(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeExpired).Inc()
+
+	return nil
 }
 
 func (s *Service) ListBookingsByEventID(ctx context.Context, eventID uuid.UUID) ([]*models.Booking, error) {
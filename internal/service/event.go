@@ -2,45 +2,143 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
 	"github.com/kstsm/wb-event-booker/internal/dto"
 	"github.com/kstsm/wb-event-booker/internal/models"
 	"time"
 )
 
+// defaultEventDuration applies to events created without an end date.
+const defaultEventDuration = 2 * time.Hour
+
 func (s *Service) CreateEvent(ctx context.Context, req *dto.CreateEventRequest) (*models.Event, error) {
 	date, err := time.Parse(time.RFC3339, req.Date)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse date: %w", err)
 	}
 
+	endDate := date.Add(defaultEventDuration)
+	if req.EndDate != "" {
+		endDate, err = time.Parse(time.RFC3339, req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse end date: %w", err)
+		}
+	}
+
 	bookingLifetime := req.BookingLifetimeHours*60 + req.BookingLifetimeMinutes
 
+	inventory := models.Inventory(req.Inventory)
+	if inventory == "" {
+		inventory = models.InventoryCounter
+	}
+
+	// Unless the organiser picks a time, sales close a configured time
+	// before the start; an event that would be closed from the outset is
+	// rejected like one in the past.
+	salesCloseAt := date.Add(-s.closesBeforeStart)
+	if req.SalesCloseAt != "" {
+		salesCloseAt, err = time.Parse(time.RFC3339, req.SalesCloseAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sales close time: %w", err)
+		}
+	} else if !salesCloseAt.After(time.Now()) {
+		return nil, apperrors.InvalidField("date",
+			"bookings close %d minutes before the start, which has already passed", int(s.closesBeforeStart.Minutes()))
+	}
+
+	var salesOpenAt *time.Time
+	if req.SalesOpenAt != "" {
+		opensAt, err := time.Parse(time.RFC3339, req.SalesOpenAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sales open time: %w", err)
+		}
+		if !opensAt.Before(salesCloseAt) {
+			return nil, apperrors.InvalidField("sales_open_at", "sales must open before they close at %s",
+				salesCloseAt.UTC().Format(time.RFC3339))
+		}
+		opensAt = opensAt.UTC()
+		salesOpenAt = &opensAt
+	}
+
+	totalSeats := req.TotalSeats
+	timeZone := req.TimeZone
+	if req.VenueID != nil {
+		venue, err := s.repo.GetVenueByID(ctx, *req.VenueID)
+		if err != nil {
+			return nil, unknownVenue(err)
+		}
+		if totalSeats == 0 {
+			totalSeats = venue.DefaultCapacity
+		}
+		if timeZone == "" {
+			timeZone = venue.TimeZone
+		}
+	}
+	if timeZone == "" {
+		timeZone = s.defaultTimeZone
+	}
+	if inventory == models.InventorySeats && totalSeats > dto.MaxSeatInventory {
+		return nil, apperrors.InvalidField("total_seats",
+			"events with seat inventory can have at most %d seats", dto.MaxSeatInventory)
+	}
+
+	category := models.EventCategory(req.Category)
+	if category == "" {
+		category = models.EventCategoryOther
+	}
+
 	event := &models.Event{
-		ID:              uuid.New(),
-		Name:            req.Name,
-		Date:            date.UTC(),
-		TotalSeats:      req.TotalSeats,
-		ReservedSeats:   0,
-		BookedSeats:     0,
-		BookingLifetime: bookingLifetime,
-		PaymentReq:      req.PaymentReq,
-		CreatedAt:       time.Now().UTC(),
+		ID:               uuid.New(),
+		Name:             req.Name,
+		Description:      req.Description,
+		Category:         category,
+		Tags:             dto.NormalizeTags(req.Tags),
+		OrganizerContact: req.OrganizerContact,
+		CoverImageURL:    req.CoverImageURL,
+		VenueID:          req.VenueID,
+		Date:             date.UTC(),
+		EndDate:          endDate.UTC(),
+		TimeZone:         timeZone,
+		SalesOpenAt:      salesOpenAt,
+		SalesCloseAt:     salesCloseAt.UTC(),
+		TotalSeats:       totalSeats,
+		ReservedSeats:    0,
+		BookedSeats:      0,
+		BookingLifetime:  bookingLifetime,
+		PaymentReq:       req.PaymentReq,
+		Inventory:        inventory,
+		CreatedAt:        time.Now().UTC(),
 	}
 
 	err = s.repo.CreateEvent(ctx, event)
 	if err != nil {
-		return nil, err
+		return nil, unknownVenue(err)
 	}
 
 	return event, nil
 }
 
+// unknownVenue reports a venue that does not exist, or was deleted while the
+// event was being created, as an invalid field of the request rather than a
+// missing resource: the URL names the events, not the venue.
+func unknownVenue(err error) error {
+	if errors.Is(err, apperrors.VenueNotFound) {
+		return apperrors.InvalidField("venue_id", "venue does not exist")
+	}
+	return err
+}
+
 func (s *Service) GetEventByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
 	return s.repo.GetEventByID(ctx, id)
 }
 
-func (s *Service) ListEvents(ctx context.Context) ([]*models.Event, error) {
-	return s.repo.ListEvents(ctx)
+func (s *Service) ListEvents(ctx context.Context, filter models.EventFilter) ([]*models.Event, error) {
+	return s.repo.ListEvents(ctx, filter)
+}
+
+func (s *Service) CancelEvent(ctx context.Context, id uuid.UUID) (int64, error) {
+	return s.repo.CancelEventWithTransaction(ctx, id)
 }
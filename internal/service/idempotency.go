@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+// BeginIdempotentRequest claims key for a request identified by fingerprint.
+// It returns nil when the request should be processed, or the stored
+// response when the same request has already been completed.
+func (s *Service) BeginIdempotentRequest(
+	ctx context.Context,
+	key, fingerprint string,
+	ttl time.Duration,
+) (*models.IdempotencyKey, error) {
+	now := time.Now().UTC()
+
+	stored, err := s.repo.AcquireIdempotencyKey(ctx, &models.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	})
+	if err != nil {
+		if errors.Is(err, apperrors.IdempotencyKeyInProgress) {
+			metrics.IdempotencyKeys.WithLabelValues(metrics.IdempotencyInProgress).Inc()
+		}
+		return nil, err
+	}
+
+	switch {
+	case stored == nil:
+		metrics.IdempotencyKeys.WithLabelValues(metrics.IdempotencyNew).Inc()
+		return nil, nil
+	case stored.Fingerprint != fingerprint:
+		metrics.IdempotencyKeys.WithLabelValues(metrics.IdempotencyMismatch).Inc()
+		return nil, apperrors.IdempotencyKeyMismatch
+	case !stored.Completed():
+		metrics.IdempotencyKeys.WithLabelValues(metrics.IdempotencyInProgress).Inc()
+		return nil, apperrors.IdempotencyKeyInProgress
+	}
+
+	metrics.IdempotencyKeys.WithLabelValues(metrics.IdempotencyReplayed).Inc()
+
+	return stored, nil
+}
+
+func (s *Service) CompleteIdempotentRequest(ctx context.Context, key *models.IdempotencyKey) error {
+	return s.repo.CompleteIdempotencyKey(ctx, key)
+}
+
+// AbortIdempotentRequest releases the key so that a retry is processed anew.
+func (s *Service) AbortIdempotentRequest(ctx context.Context, key string) error {
+	return s.repo.DeleteIdempotencyKey(ctx, key)
+}
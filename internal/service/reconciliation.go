@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+func (s *Service) ReconcileSeatCounters(ctx context.Context, repair bool) (*models.SeatReconciliation, error) {
+	drifts, err := s.repo.FindSeatDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SeatReconciliation{
+		CheckedAt: time.Now().UTC(),
+		Repair:    repair,
+		Drifts:    make([]*models.SeatDrift, 0, len(drifts)),
+	}
+
+	remaining := 0
+	for _, drift := range drifts {
+		logging.FromContext(ctx).Warn("Seat counter drift detected",
+			"event_id", drift.EventID,
+			"reserved_seats", drift.ReservedSeats,
+			"actual_reserved", drift.ActualReserved,
+			"booked_seats", drift.BookedSeats,
+			"actual_booked", drift.ActualBooked,
+		)
+
+		if repair {
+			repaired, err := s.repo.RepairSeatCountersWithTransaction(ctx, drift.EventID)
+			if err != nil {
+				logging.FromContext(ctx).Error("Failed to repair seat counters", "event_id", drift.EventID, "error", err)
+				msg := err.Error()
+				drift.Error = &msg
+			} else {
+				drift = repaired
+				if drift.Repaired {
+					metrics.SeatDriftRepairs.Inc()
+				}
+			}
+		}
+
+		if drift.HasDrift() {
+			remaining++
+		}
+		report.Drifts = append(report.Drifts, drift)
+	}
+
+	metrics.SeatDriftEvents.Set(float64(remaining))
+
+	return report, nil
+}
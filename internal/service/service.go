@@ -3,27 +3,57 @@ package service
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/config"
 	"github.com/kstsm/wb-event-booker/internal/dto"
 	"github.com/kstsm/wb-event-booker/internal/models"
 	"github.com/kstsm/wb-event-booker/internal/repository"
+	"time"
 )
 
 type ServiceI interface {
 	CreateEvent(ctx context.Context, req *dto.CreateEventRequest) (*models.Event, error)
 	GetEventByID(ctx context.Context, id uuid.UUID) (*models.Event, error)
-	ListEvents(ctx context.Context) ([]*models.Event, error)
+	ListEvents(ctx context.Context, filter models.EventFilter) ([]*models.Event, error)
+	CancelEvent(ctx context.Context, id uuid.UUID) (int64, error)
+	CreateVenue(ctx context.Context, req *dto.VenueRequest) (*models.Venue, error)
+	GetVenueByID(ctx context.Context, id uuid.UUID) (*models.Venue, error)
+	ListVenues(ctx context.Context) ([]*models.Venue, error)
+	UpdateVenue(ctx context.Context, id uuid.UUID, req *dto.VenueRequest) (*models.Venue, error)
+	DeleteVenue(ctx context.Context, id uuid.UUID) error
 	BookEvent(ctx context.Context, eventID uuid.UUID, req *dto.BookEventRequest) (*dto.BookEventResponse, error)
 	ConfirmBooking(ctx context.Context, eventID uuid.UUID, req *dto.ConfirmBookingRequest) error
+	CancelBooking(ctx context.Context, eventID, bookingID uuid.UUID) error
+	SubscribeToSales(ctx context.Context, eventID uuid.UUID, req *dto.SubscribeRequest) error
+	ExpireBooking(ctx context.Context, bookingID uuid.UUID) error
 	CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*models.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	PromoteUser(ctx context.Context, email string) (*models.User, error)
 	ListBookingsByEventID(ctx context.Context, eventID uuid.UUID) ([]*models.Booking, error)
+	ReconcileSeatCounters(ctx context.Context, repair bool) (*models.SeatReconciliation, error)
+
+	BeginIdempotentRequest(ctx context.Context, key, fingerprint string, ttl time.Duration) (*models.IdempotencyKey, error)
+	CompleteIdempotentRequest(ctx context.Context, key *models.IdempotencyKey) error
+	AbortIdempotentRequest(ctx context.Context, key string) error
 }
 
 type Service struct {
-	repo repository.RepositoryI
+	repo              repository.RepositoryI
+	maxReservations   int
+	closesBeforeStart time.Duration
+	defaultTimeZone   string
 }
 
-func NewService(repo repository.RepositoryI) ServiceI {
+func NewService(repo repository.RepositoryI, cfg config.BookingConfig) ServiceI {
+	defaultTimeZone := cfg.DefaultTimeZone
+	if defaultTimeZone == "" {
+		defaultTimeZone = "UTC"
+	}
+
 	return &Service{
-		repo: repo,
+		repo:              repo,
+		maxReservations:   cfg.MaxActiveReservations,
+		closesBeforeStart: time.Duration(cfg.ClosesBeforeStartMinutes) * time.Minute,
+		defaultTimeZone:   defaultTimeZone,
 	}
 }
@@ -0,0 +1,386 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/service"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"testing"
+	"time"
+)
+
+func newEvent(t *testing.T, svc service.ServiceI, paid bool) *models.Event {
+	t.Helper()
+
+	event, err := svc.CreateEvent(context.Background(), &dto.CreateEventRequest{
+		Name:                   "Go meetup",
+		Date:                   time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		TotalSeats:             2,
+		BookingLifetimeMinutes: 30,
+		PaymentReq:             paid,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	return event
+}
+
+func newUser(t *testing.T, svc service.ServiceI, email string) *models.User {
+	t.Helper()
+
+	user, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{Name: "user", Email: email})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	return user
+}
+
+func TestBookAndConfirm(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewService(memory.NewRepository(), config.BookingConfig{})
+
+	event := newEvent(t, svc, true)
+	user := newUser(t, svc, "anna@example.com")
+
+	resp, err := svc.BookEvent(ctx, event.ID, &dto.BookEventRequest{Email: user.Email})
+	if err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+	if resp.Deadline == nil {
+		t.Fatalf("paid booking must have a deadline")
+	}
+
+	err = svc.ConfirmBooking(ctx, uuid.New(), &dto.ConfirmBookingRequest{BookingID: resp.BookingID})
+	if !errors.Is(err, apperrors.BookingNotFound) {
+		t.Fatalf("confirming through another event: error = %v, want %v", err, apperrors.BookingNotFound)
+	}
+
+	if err := svc.ConfirmBooking(ctx, event.ID, &dto.ConfirmBookingRequest{BookingID: resp.BookingID}); err != nil {
+		t.Fatalf("ConfirmBooking: %v", err)
+	}
+
+	got, err := svc.GetEventByID(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.ReservedSeats != 0 || got.BookedSeats != 1 {
+		t.Fatalf("seats reserved=%d booked=%d, want 0 and 1", got.ReservedSeats, got.BookedSeats)
+	}
+}
+
+// salesClosedRepo reports the sales of every event as closed a minute ago.
+type salesClosedRepo struct {
+	repository.RepositoryI
+}
+
+func (r salesClosedRepo) GetEventByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	event, err := r.RepositoryI.GetEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	event.SalesCloseAt = time.Now().Add(-time.Minute)
+	return event, nil
+}
+
+func TestConfirmAfterSalesClose(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	svc := service.NewService(repo, config.BookingConfig{})
+
+	event := newEvent(t, svc, true)
+	user := newUser(t, svc, "anna@example.com")
+	resp, err := svc.BookEvent(ctx, event.ID, &dto.BookEventRequest{Email: user.Email})
+	if err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+
+	// Closing the sales stops new reservations, not the payment of existing ones.
+	closed := service.NewService(salesClosedRepo{repo}, config.BookingConfig{})
+	if err := closed.ConfirmBooking(ctx, event.ID, &dto.ConfirmBookingRequest{BookingID: resp.BookingID}); err != nil {
+		t.Fatalf("ConfirmBooking after sales closed: %v", err)
+	}
+}
+
+func TestBookEventErrors(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewService(memory.NewRepository(), config.BookingConfig{})
+
+	free := newEvent(t, svc, false)
+	user := newUser(t, svc, "boris@example.com")
+
+	_, err := svc.BookEvent(ctx, free.ID, &dto.BookEventRequest{Email: "nobody@example.com"})
+	if !errors.Is(err, apperrors.UserNotFound) {
+		t.Fatalf("unknown user: error = %v, want %v", err, apperrors.UserNotFound)
+	}
+
+	resp, err := svc.BookEvent(ctx, free.ID, &dto.BookEventRequest{Email: user.Email})
+	if err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+
+	err = svc.ConfirmBooking(ctx, free.ID, &dto.ConfirmBookingRequest{BookingID: resp.BookingID})
+	if !errors.Is(err, apperrors.EventDoesNotRequirePayment) {
+		t.Fatalf("free event: error = %v, want %v", err, apperrors.EventDoesNotRequirePayment)
+	}
+
+	if _, err := svc.CancelEvent(ctx, free.ID); err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+
+	_, err = svc.BookEvent(ctx, free.ID, &dto.BookEventRequest{Email: newUser(t, svc, "vera@example.com").Email})
+	if !errors.Is(err, apperrors.EventCancelled) {
+		t.Fatalf("cancelled event: error = %v, want %v", err, apperrors.EventCancelled)
+	}
+}
+
+func TestCancelBooking(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewService(memory.NewRepository(), config.BookingConfig{})
+
+	event := newEvent(t, svc, true)
+	user := newUser(t, svc, "gleb@example.com")
+
+	resp, err := svc.BookEvent(ctx, event.ID, &dto.BookEventRequest{Email: user.Email})
+	if err != nil {
+		t.Fatalf("BookEvent: %v", err)
+	}
+
+	err = svc.CancelBooking(ctx, uuid.New(), resp.BookingID)
+	if !errors.Is(err, apperrors.BookingNotFound) {
+		t.Fatalf("cancelling through another event: error = %v, want %v", err, apperrors.BookingNotFound)
+	}
+
+	if err := svc.CancelBooking(ctx, event.ID, resp.BookingID); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+
+	got, err := svc.GetEventByID(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.ReservedSeats != 0 || got.BookedSeats != 0 {
+		t.Fatalf("seats reserved=%d booked=%d, want 0 and 0", got.ReservedSeats, got.BookedSeats)
+	}
+
+	err = svc.CancelBooking(ctx, event.ID, resp.BookingID)
+	if !errors.Is(err, apperrors.BookingAlreadyCancelled) {
+		t.Fatalf("second cancel: error = %v, want %v", err, apperrors.BookingAlreadyCancelled)
+	}
+}
+
+func TestPromoteUser(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewService(memory.NewRepository(), config.BookingConfig{})
+
+	user := newUser(t, svc, "gleb@example.com")
+
+	promoted, err := svc.PromoteUser(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("PromoteUser: %v", err)
+	}
+	if promoted.Role != models.UserRoleAdmin {
+		t.Fatalf("role = %s, want %s", promoted.Role, models.UserRoleAdmin)
+	}
+}
+
+// driftRepo reports a fixed set of drifted events and repairs all of them
+// but the failing one.
+type driftRepo struct {
+	repository.RepositoryI
+
+	drifts  []*models.SeatDrift
+	failing uuid.UUID
+}
+
+func (r *driftRepo) FindSeatDrift(ctx context.Context) ([]*models.SeatDrift, error) {
+	drifts := make([]*models.SeatDrift, len(r.drifts))
+	for i, d := range r.drifts {
+		copied := *d
+		drifts[i] = &copied
+	}
+	return drifts, nil
+}
+
+func (r *driftRepo) RepairSeatCountersWithTransaction(ctx context.Context, eventID uuid.UUID) (*models.SeatDrift, error) {
+	if eventID == r.failing {
+		return nil, errors.New("event is locked")
+	}
+	for _, d := range r.drifts {
+		if d.EventID == eventID {
+			repaired := *d
+			repaired.ReservedSeats, repaired.BookedSeats = d.ActualReserved, d.ActualBooked
+			repaired.Repaired = true
+			return &repaired, nil
+		}
+	}
+	return nil, errors.New("event not found")
+}
+
+func TestReconcileSeatCounters(t *testing.T) {
+	ctx := context.Background()
+	repo := &driftRepo{
+		drifts: []*models.SeatDrift{
+			{EventID: uuid.New(), TotalSeats: 10, ReservedSeats: 3, ActualReserved: 2},
+			{EventID: uuid.New(), TotalSeats: 10, BookedSeats: 1, ActualBooked: 4},
+		},
+	}
+	repo.failing = repo.drifts[1].EventID
+	svc := service.NewService(repo, config.BookingConfig{})
+
+	report, err := svc.ReconcileSeatCounters(ctx, false)
+	if err != nil {
+		t.Fatalf("ReconcileSeatCounters: %v", err)
+	}
+	if report.Repair || len(report.Drifts) != 2 {
+		t.Fatalf("check: repair = %v with %d drifts, want false with 2", report.Repair, len(report.Drifts))
+	}
+	for _, d := range report.Drifts {
+		if d.Repaired || d.Error != nil || !d.HasDrift() {
+			t.Errorf("check changed drift %+v", d)
+		}
+	}
+	if got := testutil.ToFloat64(metrics.SeatDriftEvents); got != 2 {
+		t.Errorf("drift gauge after a check = %v, want 2", got)
+	}
+
+	report, err = svc.ReconcileSeatCounters(ctx, true)
+	if err != nil {
+		t.Fatalf("ReconcileSeatCounters: %v", err)
+	}
+	repaired, failed := report.Drifts[0], report.Drifts[1]
+	if !report.Repair || !repaired.Repaired || repaired.HasDrift() || repaired.Error != nil {
+		t.Errorf("repaired drift = %+v, want repaired counters", repaired)
+	}
+	if failed.Repaired || failed.Error == nil || *failed.Error != "event is locked" || !failed.HasDrift() {
+		t.Errorf("failed repair = %+v, want the drift with the error", failed)
+	}
+	if got := testutil.ToFloat64(metrics.SeatDriftEvents); got != 1 {
+		t.Errorf("drift gauge after a repair = %v, want the 1 unrepaired event", got)
+	}
+}
+
+func TestCreateEventSchedule(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	svc := service.NewService(repo, config.BookingConfig{ClosesBeforeStartMinutes: 60, DefaultTimeZone: "Asia/Almaty"})
+
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	event, err := svc.CreateEvent(ctx, &dto.CreateEventRequest{
+		Name:       "Go meetup",
+		Date:       start.Format(time.RFC3339),
+		TotalSeats: 10,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if event.TimeZone != "Asia/Almaty" || !event.SalesCloseAt.Equal(start.Add(-time.Hour)) ||
+		!event.EndDate.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("event time zone %q, bookings close at %v, ends at %v", event.TimeZone, event.SalesCloseAt, event.EndDate)
+	}
+
+	lat, lon := 55.75, 37.61
+	venue, err := svc.CreateVenue(ctx, &dto.VenueRequest{
+		Name:            "Hall",
+		Address:         "Moscow",
+		Latitude:        &lat,
+		Longitude:       &lon,
+		TimeZone:        "Europe/Moscow",
+		DefaultCapacity: 50,
+	})
+	if err != nil {
+		t.Fatalf("CreateVenue: %v", err)
+	}
+	atVenue, err := svc.CreateEvent(ctx, &dto.CreateEventRequest{
+		Name:    "Concert",
+		Date:    start.Format(time.RFC3339),
+		VenueID: &venue.ID,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if atVenue.TimeZone != "Europe/Moscow" || atVenue.TotalSeats != 50 {
+		t.Fatalf("event at venue has time zone %q and %d seats", atVenue.TimeZone, atVenue.TotalSeats)
+	}
+
+	_, err = svc.CreateEvent(ctx, &dto.CreateEventRequest{
+		Name:       "Soon",
+		Date:       time.Now().Add(30 * time.Minute).Format(time.RFC3339),
+		TotalSeats: 10,
+	})
+	var validation *apperrors.ValidationError
+	if !errors.As(err, &validation) || !validation.Has("date") {
+		t.Fatalf("event closing for bookings before creation: error = %v, want a date validation error", err)
+	}
+}
+
+func TestSalesWindow(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	svc := service.NewService(repo, config.BookingConfig{})
+	user := newUser(t, svc, "anna@example.com")
+
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	opensAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	event, err := svc.CreateEvent(ctx, &dto.CreateEventRequest{
+		Name:         "Go meetup",
+		Date:         start.Format(time.RFC3339),
+		TotalSeats:   10,
+		SalesOpenAt:  opensAt.Format(time.RFC3339),
+		SalesCloseAt: start.Add(-30 * time.Minute).Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if event.SalesOpenAt == nil || !event.SalesOpenAt.Equal(opensAt) || !event.SalesCloseAt.Equal(start.Add(-30*time.Minute)) {
+		t.Fatalf("sales open at %v and close at %v", event.SalesOpenAt, event.SalesCloseAt)
+	}
+
+	_, err = svc.BookEvent(ctx, event.ID, &dto.BookEventRequest{Email: user.Email})
+	if !errors.Is(err, apperrors.SalesNotOpen) {
+		t.Fatalf("booking before sales open: error = %v, want %v", err, apperrors.SalesNotOpen)
+	}
+
+	for range 2 {
+		if err := svc.SubscribeToSales(ctx, event.ID, &dto.SubscribeRequest{Email: user.Email}); err != nil {
+			t.Fatalf("SubscribeToSales: %v", err)
+		}
+	}
+
+	_, err = svc.CreateEvent(ctx, &dto.CreateEventRequest{
+		Name:        "Late sales",
+		Date:        start.Format(time.RFC3339),
+		TotalSeats:  10,
+		SalesOpenAt: start.Format(time.RFC3339),
+	})
+	var validation *apperrors.ValidationError
+	if !errors.As(err, &validation) || !validation.Has("sales_open_at") {
+		t.Fatalf("sales opening after they close: error = %v, want a sales_open_at validation error", err)
+	}
+
+	closed := &models.Event{
+		ID:           uuid.New(),
+		Name:         "Sold out",
+		Date:         start,
+		EndDate:      start.Add(2 * time.Hour),
+		SalesCloseAt: time.Now().Add(-time.Minute),
+		TotalSeats:   10,
+		CreatedAt:    time.Now().Add(-time.Hour),
+	}
+	if err := repo.CreateEvent(ctx, closed); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	err = svc.SubscribeToSales(ctx, closed.ID, &dto.SubscribeRequest{Email: user.Email})
+	if !errors.Is(err, apperrors.SalesClosed) {
+		t.Fatalf("subscribing after sales closed: error = %v, want %v", err, apperrors.SalesClosed)
+	}
+}
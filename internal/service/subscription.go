@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/gookit/slog"
+	"github.com/kstsm/wb-event-booker/internal/apperrors"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+// SubscribeToSales registers the user to be notified once ticket sales of
+// the event open. Subscribing to an event on sale already is accepted too:
+// the notification then goes out on the next run of the sales job.
+func (s *Service) SubscribeToSales(ctx context.Context, eventID uuid.UUID, req *dto.SubscribeRequest) error {
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return err
+	}
+	logging.AddFields(ctx, slog.M{logging.FieldUserID: user.ID})
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch {
+	case event.IsCancelled():
+		return apperrors.EventCancelled
+	case event.HasStarted(now):
+		return apperrors.EventExpired
+	case event.SalesStatus(now) == models.SalesClosed:
+		return apperrors.SalesClosed
+	}
+
+	return s.repo.CreateSubscription(ctx, &models.Subscription{
+		EventID:   eventID,
+		UserID:    user.ID,
+		CreatedAt: now.UTC(),
+	})
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+type tracedService struct {
+	next ServiceI
+}
+
+func WithTracing(next ServiceI) ServiceI {
+	return &tracedService{
+		next: next,
+	}
+}
+
+func (s *tracedService) CreateEvent(ctx context.Context, req *dto.CreateEventRequest) (*models.Event, error) {
+	ctx, span := tracing.Start(ctx, "Service.CreateEvent")
+	event, err := s.next.CreateEvent(ctx, req)
+	tracing.End(span, err)
+
+	return event, err
+}
+
+func (s *tracedService) GetEventByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetEventByID", eventAttr(id))
+	event, err := s.next.GetEventByID(ctx, id)
+	tracing.End(span, err)
+
+	return event, err
+}
+
+func (s *tracedService) ListEvents(ctx context.Context, filter models.EventFilter) ([]*models.Event, error) {
+	ctx, span := tracing.Start(ctx, "Service.ListEvents", trace.WithAttributes(
+		attribute.String("filter.category", string(filter.Category)),
+		attribute.StringSlice("filter.tags", filter.Tags),
+	))
+	events, err := s.next.ListEvents(ctx, filter)
+	tracing.End(span, err)
+
+	return events, err
+}
+
+func (s *tracedService) CreateVenue(ctx context.Context, req *dto.VenueRequest) (*models.Venue, error) {
+	ctx, span := tracing.Start(ctx, "Service.CreateVenue")
+	venue, err := s.next.CreateVenue(ctx, req)
+	tracing.End(span, err)
+
+	return venue, err
+}
+
+func (s *tracedService) GetVenueByID(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetVenueByID", venueAttr(id))
+	venue, err := s.next.GetVenueByID(ctx, id)
+	tracing.End(span, err)
+
+	return venue, err
+}
+
+func (s *tracedService) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	ctx, span := tracing.Start(ctx, "Service.ListVenues")
+	venues, err := s.next.ListVenues(ctx)
+	tracing.End(span, err)
+
+	return venues, err
+}
+
+func (s *tracedService) UpdateVenue(ctx context.Context, id uuid.UUID, req *dto.VenueRequest) (*models.Venue, error) {
+	ctx, span := tracing.Start(ctx, "Service.UpdateVenue", venueAttr(id))
+	venue, err := s.next.UpdateVenue(ctx, id, req)
+	tracing.End(span, err)
+
+	return venue, err
+}
+
+func (s *tracedService) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Service.DeleteVenue", venueAttr(id))
+	err := s.next.DeleteVenue(ctx, id)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *tracedService) CancelEvent(ctx context.Context, id uuid.UUID) (int64, error) {
+	ctx, span := tracing.Start(ctx, "Service.CancelEvent", eventAttr(id))
+	cancelled, err := s.next.CancelEvent(ctx, id)
+	tracing.End(span, err)
+
+	return cancelled, err
+}
+
+func (s *tracedService) BookEvent(
+	ctx context.Context,
+	eventID uuid.UUID,
+	req *dto.BookEventRequest,
+) (*dto.BookEventResponse, error) {
+	ctx, span := tracing.Start(ctx, "Service.BookEvent", eventAttr(eventID))
+	resp, err := s.next.BookEvent(ctx, eventID, req)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+func (s *tracedService) ConfirmBooking(ctx context.Context, eventID uuid.UUID, req *dto.ConfirmBookingRequest) error {
+	ctx, span := tracing.Start(ctx, "Service.ConfirmBooking", eventAttr(eventID))
+	err := s.next.ConfirmBooking(ctx, eventID, req)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *tracedService) SubscribeToSales(ctx context.Context, eventID uuid.UUID, req *dto.SubscribeRequest) error {
+	ctx, span := tracing.Start(ctx, "Service.SubscribeToSales", eventAttr(eventID))
+	err := s.next.SubscribeToSales(ctx, eventID, req)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *tracedService) CancelBooking(ctx context.Context, eventID, bookingID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Service.CancelBooking", trace.WithAttributes(
+		attribute.String("event.id", eventID.String()),
+		attribute.String("booking.id", bookingID.String()),
+	))
+	err := s.next.CancelBooking(ctx, eventID, bookingID)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *tracedService) ExpireBooking(ctx context.Context, bookingID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Service.ExpireBooking",
+		trace.WithAttributes(attribute.String("booking.id", bookingID.String())))
+	err := s.next.ExpireBooking(ctx, bookingID)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *tracedService) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "Service.CreateUser")
+	user, err := s.next.CreateUser(ctx, req)
+	tracing.End(span, err)
+
+	return user, err
+}
+
+func (s *tracedService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetUserByID",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	user, err := s.next.GetUserByID(ctx, id)
+	tracing.End(span, err)
+
+	return user, err
+}
+
+func (s *tracedService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetUserByEmail")
+	user, err := s.next.GetUserByEmail(ctx, email)
+	tracing.End(span, err)
+
+	return user, err
+}
+
+func (s *tracedService) PromoteUser(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "Service.PromoteUser")
+	user, err := s.next.PromoteUser(ctx, email)
+	tracing.End(span, err)
+
+	return user, err
+}
+
+func (s *tracedService) ListBookingsByEventID(ctx context.Context, eventID uuid.UUID) ([]*models.Booking, error) {
+	ctx, span := tracing.Start(ctx, "Service.ListBookingsByEventID", eventAttr(eventID))
+	bookings, err := s.next.ListBookingsByEventID(ctx, eventID)
+	tracing.End(span, err)
+
+	return bookings, err
+}
+
+func (s *tracedService) ReconcileSeatCounters(ctx context.Context, repair bool) (*models.SeatReconciliation, error) {
+	ctx, span := tracing.Start(ctx, "Service.ReconcileSeatCounters",
+		trace.WithAttributes(attribute.Bool("reconcile.repair", repair)))
+	report, err := s.next.ReconcileSeatCounters(ctx, repair)
+	tracing.End(span, err)
+
+	return report, err
+}
+
+func (s *tracedService) BeginIdempotentRequest(
+	ctx context.Context,
+	key, fingerprint string,
+	ttl time.Duration,
+) (*models.IdempotencyKey, error) {
+	ctx, span := tracing.Start(ctx, "Service.BeginIdempotentRequest")
+	stored, err := s.next.BeginIdempotentRequest(ctx, key, fingerprint, ttl)
+	tracing.End(span, err)
+
+	return stored, err
+}
+
+func (s *tracedService) CompleteIdempotentRequest(ctx context.Context, key *models.IdempotencyKey) error {
+	ctx, span := tracing.Start(ctx, "Service.CompleteIdempotentRequest")
+	err := s.next.CompleteIdempotentRequest(ctx, key)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *tracedService) AbortIdempotentRequest(ctx context.Context, key string) error {
+	ctx, span := tracing.Start(ctx, "Service.AbortIdempotentRequest")
+	err := s.next.AbortIdempotentRequest(ctx, key)
+	tracing.End(span, err)
+
+	return err
+}
+
+func eventAttr(id uuid.UUID) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.String("event.id", id.String()))
+}
+
+func venueAttr(id uuid.UUID) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.String("venue.id", id.String()))
+}
@@ -14,6 +14,7 @@ func (s *Service) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*
 		Name:       req.Name,
 		Email:      req.Email,
 		TelegramID: req.TelegramID,
+		Role:       models.UserRoleUser,
 		CreatedAt:  time.Now(),
 	}
 
@@ -24,3 +25,31 @@ func (s *Service) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*
 
 	return user, nil
 }
+
+func (s *Service) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return s.repo.GetUserByID(ctx, id)
+}
+
+func (s *Service) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.repo.GetUserByEmail(ctx, email)
+}
+
+func (s *Service) PromoteUser(ctx context.Context, email string) (*models.User, error) {
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == models.UserRoleAdmin {
+		return user, nil
+	}
+
+	err = s.repo.UpdateUserRole(ctx, user.ID, models.UserRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = models.UserRoleAdmin
+
+	return user, nil
+}
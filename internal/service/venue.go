@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"time"
+)
+
+func (s *Service) CreateVenue(ctx context.Context, req *dto.VenueRequest) (*models.Venue, error) {
+	now := time.Now().UTC()
+	venue := &models.Venue{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	applyVenueRequest(venue, req)
+
+	if err := s.repo.CreateVenue(ctx, venue); err != nil {
+		return nil, err
+	}
+
+	return venue, nil
+}
+
+func (s *Service) GetVenueByID(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
+	return s.repo.GetVenueByID(ctx, id)
+}
+
+func (s *Service) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	return s.repo.ListVenues(ctx)
+}
+
+// UpdateVenue replaces the fields of a venue. Events already scheduled there
+// keep their seats and times.
+func (s *Service) UpdateVenue(ctx context.Context, id uuid.UUID, req *dto.VenueRequest) (*models.Venue, error) {
+	venue, err := s.repo.GetVenueByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyVenueRequest(venue, req)
+	venue.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpdateVenue(ctx, venue); err != nil {
+		return nil, err
+	}
+
+	return venue, nil
+}
+
+func (s *Service) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteVenue(ctx, id)
+}
+
+func applyVenueRequest(venue *models.Venue, req *dto.VenueRequest) {
+	venue.Name = req.Name
+	venue.Address = req.Address
+	venue.Latitude = *req.Latitude
+	venue.Longitude = *req.Longitude
+	venue.TimeZone = req.TimeZone
+	venue.DefaultCapacity = req.DefaultCapacity
+}
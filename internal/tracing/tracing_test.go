@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/kstsm/wb-event-booker/internal/config"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestNewExporter(t *testing.T) {
+	ctx := context.Background()
+
+	for _, name := range []string{"", ExporterNone} {
+		exporter, err := newExporter(ctx, config.TracingConfig{Exporter: name}, nil)
+		if exporter != nil || err != nil {
+			t.Errorf("exporter %q = %v, %v, want none", name, exporter, err)
+		}
+	}
+
+	exporter, err := newExporter(ctx, config.TracingConfig{Exporter: ExporterStdout}, &bytes.Buffer{})
+	if exporter == nil || err != nil {
+		t.Errorf("stdout exporter = %v, %v", exporter, err)
+	}
+
+	if _, err := newExporter(ctx, config.TracingConfig{Exporter: "jaeger"}, nil); err == nil {
+		t.Errorf("unknown exporter: error = nil")
+	}
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	End(failed, errors.New("connection refused"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("%d spans ended, want 2", len(spans))
+	}
+	if status := spans[0].Status(); status.Code != codes.Unset {
+		t.Errorf("status of a span without an error = %v", status)
+	}
+	if status := spans[1].Status(); status.Code != codes.Error || status.Description != "connection refused" {
+		t.Errorf("status of a failed span = %v", status)
+	}
+	if events := spans[1].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("events of a failed span = %v, want the recorded error", events)
+	}
+}
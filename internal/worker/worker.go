@@ -3,11 +3,16 @@ package worker
 import (
 	"context"
 	"fmt"
-	"github.com/gookit/slog"
 	"github.com/kstsm/wb-event-booker/internal/dto"
+	"github.com/kstsm/wb-event-booker/internal/logging"
+	"github.com/kstsm/wb-event-booker/internal/metrics"
 	"github.com/kstsm/wb-event-booker/internal/models"
 	"github.com/kstsm/wb-event-booker/internal/notifier"
 	"github.com/kstsm/wb-event-booker/internal/repository"
+	"github.com/kstsm/wb-event-booker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"time"
 )
 
 type Worker struct {
@@ -23,32 +28,46 @@ func NewWorker(repo repository.RepositoryI, notifier notifier.NotifierI) *Worker
 }
 
 func (w *Worker) ProcessExpiredBookings(ctx context.Context) error {
-	slog.Info("Processing expired bookings...")
+	ctx, span := tracing.Start(ctx, "Worker.ProcessExpiredBookings")
+	defer span.End()
+
+	logging.FromContext(ctx).Info("Processing expired bookings...")
+
+	start := time.Now()
+	defer func() {
+		metrics.WorkerRunDuration.Observe(time.Since(start).Seconds())
+	}()
 
 	bookings, err := w.repo.GetExpiredReservedBookings(ctx)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("failed to get expired bookings: %w", err)
 	}
 
+	span.SetAttributes(attribute.Int("bookings.expired", len(bookings)))
+
 	if len(bookings) == 0 {
-		slog.Debug("No expired bookings found")
+		logging.FromContext(ctx).Debug("No expired bookings found")
 		return nil
 	}
 
-	slog.Infof("Found %d expired bookings to process", len(bookings))
+	logging.FromContext(ctx).Infof("Found %d expired bookings to process", len(bookings))
 
 	for _, booking := range bookings {
 		if err := w.processExpiredBooking(ctx, booking); err != nil {
-			slog.Error("Failed to process expired booking", "booking_id", booking.ID, "error", err)
+			logging.FromContext(ctx).Error("Failed to process expired booking", "booking_id", booking.ID, "error", err)
+			metrics.WorkerProcessed.WithLabelValues("failed").Inc()
 			continue
 		}
+		metrics.WorkerProcessed.WithLabelValues("processed").Inc()
 	}
 
 	return nil
 }
 
 func (w *Worker) processExpiredBooking(ctx context.Context, booking *models.Booking) error {
-	slog.Infof("Processing expired booking: booking_id=%s, event_id=%s, deadline=%v",
+	logging.FromContext(ctx).Infof("Processing expired booking: booking_id=%s, event_id=%s, deadline=%v",
 		booking.ID, booking.EventID, booking.Deadline)
 
 	currentBooking, err := w.repo.GetBookingByID(ctx, booking.ID)
@@ -57,7 +76,7 @@ func (w *Worker) processExpiredBooking(ctx context.Context, booking *models.Book
 	}
 
 	if currentBooking.Status != models.BookingStatusReserved {
-		slog.Infof("Booking %s is not in reserved status (status=%s), skipping", booking.ID, currentBooking.Status)
+		logging.FromContext(ctx).Infof("Booking %s is not in reserved status (status=%s), skipping", booking.ID, currentBooking.Status)
 		return nil
 	}
 
@@ -69,11 +88,11 @@ func (w *Worker) processExpiredBooking(ctx context.Context, booking *models.Book
 	if w.notifier != nil {
 		err = w.sendTelegramNotification(ctx, booking)
 		if err != nil {
-			slog.Warn("Failed to send Telegram notification", "booking_id", booking.ID, "error", err)
+			logging.FromContext(ctx).Warn("Failed to send Telegram notification", "booking_id", booking.ID, "error", err)
 		}
 	}
 
-	slog.Infof("Successfully processed expired booking: booking_id=%s", booking.ID)
+	logging.FromContext(ctx).Infof("Successfully processed expired booking: booking_id=%s", booking.ID)
 	return nil
 }
 
@@ -83,7 +102,9 @@ func (w *Worker) cancelExpiredBooking(ctx context.Context, booking *models.Booki
 		return fmt.Errorf("failed to cancel booking: %w", err)
 	}
 
-	slog.Infof("Cancelled expired booking: booking_id=%s, event_id=%s", booking.ID, booking.EventID)
+	metrics.BookingOutcomes.WithLabelValues(metrics.OutcomeExpired).Inc()
+
+	logging.FromContext(ctx).Infof("Cancelled expired booking: booking_id=%s, event_id=%s", booking.ID, booking.EventID)
 	return nil
 }
 
@@ -99,7 +120,7 @@ func (w *Worker) sendTelegramNotification(ctx context.Context, booking *models.B
 	}
 
 	if user.TelegramID == nil {
-		slog.Infof("User %s has no Telegram ID, skipping Telegram notification", user.ID)
+		logging.FromContext(ctx).Infof("User %s has no Telegram ID, skipping Telegram notification", user.ID)
 		return nil
 	}
 
@@ -108,7 +129,155 @@ func (w *Worker) sendTelegramNotification(ctx context.Context, booking *models.B
 		event.Name,
 		booking.ID,
 		event.Name,
-		event.Date.Format("2006-01-02 15:04"),
+		event.Date.In(event.Location()).Format(dto.TelegramTimeLayout),
+	)
+
+	err = w.notifier.SendNotification(ctx, user.ID, *user.TelegramID, message)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Worker) SendBookingReminders(ctx context.Context, before time.Duration) error {
+	if w.notifier == nil {
+		logging.FromContext(ctx).Debug("Notifier is not configured, skipping booking reminders")
+		return nil
+	}
+
+	bookings, err := w.repo.GetBookingsForReminder(ctx, time.Now().Add(before))
+	if err != nil {
+		return fmt.Errorf("failed to get bookings for reminder: %w", err)
+	}
+
+	for _, booking := range bookings {
+		if err := w.sendBookingReminder(ctx, booking); err != nil {
+			logging.FromContext(ctx).Warn("Failed to send booking reminder", "booking_id", booking.ID, "error", err)
+			continue
+		}
+
+		if err := w.repo.MarkBookingReminded(ctx, booking.ID); err != nil {
+			logging.FromContext(ctx).Error("Failed to mark booking reminded", "booking_id", booking.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// NotifySalesOpened tells the subscribers of events whose ticket sales have
+// opened about it. A subscription is marked notified even if its user has no
+// Telegram chat, so it is not picked up again.
+func (w *Worker) NotifySalesOpened(ctx context.Context) error {
+	if w.notifier == nil {
+		logging.FromContext(ctx).Debug("Notifier is not configured, skipping sales notifications")
+		return nil
+	}
+
+	subscriptions, err := w.repo.GetSubscriptionsForSalesOpen(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get subscriptions for sales open: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		if err := w.sendSalesOpened(ctx, subscription); err != nil {
+			logging.FromContext(ctx).Warn("Failed to send sales notification",
+				"event_id", subscription.EventID, "user_id", subscription.UserID, "error", err)
+			continue
+		}
+
+		if err := w.repo.MarkSubscriptionNotified(ctx, subscription.EventID, subscription.UserID); err != nil {
+			logging.FromContext(ctx).Error("Failed to mark subscription notified",
+				"event_id", subscription.EventID, "user_id", subscription.UserID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Worker) CleanupJobRuns(ctx context.Context, retention time.Duration) error {
+	deleted, err := w.repo.DeleteJobRunsBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return fmt.Errorf("failed to delete old job runs: %w", err)
+	}
+
+	logging.FromContext(ctx).Infof("Deleted %d old job runs", deleted)
+	return nil
+}
+
+func (w *Worker) CleanupIdempotencyKeys(ctx context.Context) error {
+	deleted, err := w.repo.DeleteIdempotencyKeysBefore(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	logging.FromContext(ctx).Infof("Deleted %d expired idempotency keys", deleted)
+	return nil
+}
+
+func (w *Worker) ReportStats(ctx context.Context) error {
+	stats, err := w.repo.GetBookingStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get booking stats: %w", err)
+	}
+
+	logging.FromContext(ctx).Infof("Booking report: events=%d, reserved=%d, confirmed=%d, cancelled=%d",
+		stats.Events, stats.Reserved, stats.Confirmed, stats.Cancelled)
+	return nil
+}
+
+func (w *Worker) sendBookingReminder(ctx context.Context, booking *models.Booking) error {
+	user, err := w.repo.GetUserByID(ctx, booking.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.TelegramID == nil {
+		return nil
+	}
+
+	event, err := w.repo.GetEventByID(ctx, booking.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+
+	message := fmt.Sprintf(
+		dto.TelegramBookingReminder,
+		event.Name,
+		booking.Deadline.In(event.Location()).Format(dto.TelegramTimeLayout),
+		booking.ID,
+	)
+
+	err = w.notifier.SendNotification(ctx, user.ID, *user.TelegramID, message)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Worker) sendSalesOpened(ctx context.Context, subscription *models.Subscription) error {
+	user, err := w.repo.GetUserByID(ctx, subscription.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.TelegramID == nil {
+		return nil
+	}
+
+	event, err := w.repo.GetEventByID(ctx, subscription.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+
+	loc := event.Location()
+	message := fmt.Sprintf(
+		dto.TelegramSalesOpened,
+		event.Name,
+		event.Date.In(loc).Format(dto.TelegramTimeLayout),
+		event.SalesCloseAt.In(loc).Format(dto.TelegramTimeLayout),
+		event.ID,
 	)
 
 	err = w.notifier.SendNotification(ctx, user.ID, *user.TelegramID, message)
@@ -0,0 +1,251 @@
+package worker_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kstsm/wb-event-booker/internal/models"
+	"github.com/kstsm/wb-event-booker/internal/repository/memory"
+	"github.com/kstsm/wb-event-booker/internal/worker"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeNotifier struct {
+	mu       sync.Mutex
+	sent     []int64
+	messages []string
+}
+
+func (n *fakeNotifier) SendNotification(ctx context.Context, userID uuid.UUID, telegramID int64, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, telegramID)
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func (n *fakeNotifier) Ping(ctx context.Context) error {
+	return nil
+}
+
+func TestProcessExpiredBookings(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	notifier := &fakeNotifier{}
+	w := worker.NewWorker(repo, notifier)
+
+	// A zero booking lifetime puts the deadline of the reservation at the
+	// moment it is made, so it is due for expiry straight away.
+	var events []*models.Event
+	for _, lifetime := range []int{0, 30} {
+		event := &models.Event{
+			ID:              uuid.New(),
+			Name:            "Go meetup",
+			Date:            time.Now().Add(24 * time.Hour),
+			TimeZone:        "UTC",
+			SalesCloseAt:    time.Now().Add(24 * time.Hour),
+			TotalSeats:      5,
+			BookingLifetime: lifetime,
+			PaymentReq:      true,
+		}
+		if err := repo.CreateEvent(ctx, event); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	telegramID := int64(1001)
+	user := &models.User{ID: uuid.New(), Name: "anna", Email: "anna@example.com", TelegramID: &telegramID}
+	if err := repo.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	var bookings []*models.Booking
+	for _, event := range events {
+		booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+		if err != nil {
+			t.Fatalf("BookEventWithTransaction: %v", err)
+		}
+		bookings = append(bookings, booking)
+	}
+
+	for range 2 {
+		if err := w.ProcessExpiredBookings(ctx); err != nil {
+			t.Fatalf("ProcessExpiredBookings: %v", err)
+		}
+	}
+
+	wantStatus := []models.BookingStatus{models.BookingStatusCancelled, models.BookingStatusReserved}
+	wantReserved := []int{0, 1}
+	for i, booking := range bookings {
+		got, err := repo.GetBookingByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("GetBookingByID: %v", err)
+		}
+		if got.Status != wantStatus[i] {
+			t.Errorf("booking %d: status = %s, want %s", i, got.Status, wantStatus[i])
+		}
+
+		event, err := repo.GetEventByID(ctx, events[i].ID)
+		if err != nil {
+			t.Fatalf("GetEventByID: %v", err)
+		}
+		if event.ReservedSeats != wantReserved[i] {
+			t.Errorf("event %d: reserved seats = %d, want %d", i, event.ReservedSeats, wantReserved[i])
+		}
+	}
+
+	if len(notifier.sent) != 1 || notifier.sent[0] != telegramID {
+		t.Fatalf("want exactly one cancellation notice to %d, got %v", telegramID, notifier.sent)
+	}
+	if !strings.Contains(notifier.messages[0], bookings[0].ID.String()) {
+		t.Errorf("notice %q does not name the booking %s", notifier.messages[0], bookings[0].ID)
+	}
+}
+
+func TestSendBookingReminders(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	notifier := &fakeNotifier{}
+	w := worker.NewWorker(repo, notifier)
+
+	event := &models.Event{
+		ID:              uuid.New(),
+		Name:            "Go meetup",
+		Date:            time.Now().Add(24 * time.Hour),
+		TimeZone:        "Europe/Moscow",
+		SalesCloseAt:    time.Now().Add(24 * time.Hour),
+		TotalSeats:      5,
+		BookingLifetime: 30,
+		PaymentReq:      true,
+	}
+	if err := repo.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	telegramID := int64(1001)
+	user := &models.User{ID: uuid.New(), Name: "anna", Email: "anna@example.com", TelegramID: &telegramID}
+	if err := repo.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	booking, err := repo.BookEventWithTransaction(ctx, event.ID, user.ID, 0)
+	if err != nil {
+		t.Fatalf("BookEventWithTransaction: %v", err)
+	}
+
+	if err := w.SendBookingReminders(ctx, 10*time.Minute); err != nil {
+		t.Fatalf("SendBookingReminders: %v", err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Fatalf("reminder sent before the window: %v", notifier.sent)
+	}
+
+	for range 2 {
+		if err := w.SendBookingReminders(ctx, time.Hour); err != nil {
+			t.Fatalf("SendBookingReminders: %v", err)
+		}
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != telegramID {
+		t.Fatalf("want exactly one reminder to %d, got %v", telegramID, notifier.sent)
+	}
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	if local := booking.Deadline.In(moscow).Format("2006-01-02 15:04 MSK"); !strings.Contains(notifier.messages[0], local) {
+		t.Errorf("reminder %q does not show the deadline in the event time zone, %s", notifier.messages[0], local)
+	}
+}
+
+func TestNotifySalesOpened(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	notifier := &fakeNotifier{}
+	w := worker.NewWorker(repo, notifier)
+
+	opened := time.Now().Add(-time.Minute)
+	opensLater := time.Now().Add(time.Hour)
+	var events []*models.Event
+	for _, opensAt := range []*time.Time{&opened, &opensLater} {
+		event := &models.Event{
+			ID:           uuid.New(),
+			Name:         "Go meetup",
+			Date:         time.Now().Add(24 * time.Hour),
+			TimeZone:     "UTC",
+			SalesOpenAt:  opensAt,
+			SalesCloseAt: time.Now().Add(24 * time.Hour),
+			TotalSeats:   5,
+			CreatedAt:    time.Now().Add(-time.Hour),
+		}
+		if err := repo.CreateEvent(ctx, event); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	telegramID := int64(1001)
+	users := []*models.User{
+		{ID: uuid.New(), Name: "anna", Email: "anna@example.com", TelegramID: &telegramID},
+		{ID: uuid.New(), Name: "ivan", Email: "ivan@example.com"},
+	}
+	for _, user := range users {
+		if err := repo.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		for _, event := range events {
+			subscription := &models.Subscription{EventID: event.ID, UserID: user.ID, CreatedAt: time.Now()}
+			if err := repo.CreateSubscription(ctx, subscription); err != nil {
+				t.Fatalf("CreateSubscription: %v", err)
+			}
+		}
+	}
+
+	for range 2 {
+		if err := w.NotifySalesOpened(ctx); err != nil {
+			t.Fatalf("NotifySalesOpened: %v", err)
+		}
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != telegramID {
+		t.Fatalf("want exactly one notification to %d, got %v", telegramID, notifier.sent)
+	}
+	if !strings.Contains(notifier.messages[0], events[0].ID.String()) {
+		t.Errorf("notification %q does not name the event %s", notifier.messages[0], events[0].ID)
+	}
+
+	due, err := repo.GetSubscriptionsForSalesOpen(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetSubscriptionsForSalesOpen: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("subscriptions of users without Telegram must be marked notified too, %d left", len(due))
+	}
+}
+
+func TestCleanupJobRuns(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	w := worker.NewWorker(repo, nil)
+
+	for _, age := range []time.Duration{time.Hour, 48 * time.Hour} {
+		run := &models.JobRun{ID: uuid.New(), JobName: "expiry", StartedAt: time.Now().Add(-age)}
+		if err := repo.CreateJobRun(ctx, run); err != nil {
+			t.Fatalf("CreateJobRun: %v", err)
+		}
+	}
+
+	if err := w.CleanupJobRuns(ctx, 24*time.Hour); err != nil {
+		t.Fatalf("CleanupJobRuns: %v", err)
+	}
+
+	runs, err := repo.ListJobRuns(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListJobRuns: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("want 1 run left after cleanup, got %d", len(runs))
+	}
+}
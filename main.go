@@ -1,7 +1,12 @@
 package main
 
-import "github.com/kstsm/wb-event-booker/cmd"
+import (
+	"github.com/kstsm/wb-event-booker/cmd"
+	// Venue time zones are validated with time.LoadLocation, which must not
+	// depend on the zoneinfo files of the host or container.
+	_ "time/tzdata"
+)
 
 func main() {
-	cmd.Run()
+	cmd.Execute()
 }
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS job_runs
+(
+    id          UUID PRIMARY KEY,
+    job_name    VARCHAR(64) NOT NULL,
+    trigger     VARCHAR(16) NOT NULL,
+    status      VARCHAR(16) NOT NULL,
+    error       TEXT,
+    started_at  TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    duration_ms BIGINT      NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name ON job_runs (job_name);
+CREATE INDEX IF NOT EXISTS idx_job_runs_started_at ON job_runs (started_at);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE bookings DROP COLUMN IF EXISTS reminder_sent_at;
+DROP TABLE IF EXISTS job_runs;
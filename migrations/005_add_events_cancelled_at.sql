@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE events ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE events DROP COLUMN IF EXISTS cancelled_at;
@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS inventory VARCHAR(16) NOT NULL DEFAULT 'counter'
+        CHECK (inventory IN ('counter', 'seats'));
+
+-- One row per seat of an event with seat inventory. A booking claims a free
+-- row instead of locking the event, so concurrent bookings do not queue up.
+CREATE TABLE IF NOT EXISTS event_seats
+(
+    event_id   UUID NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    seat_no    INT  NOT NULL,
+    booking_id UUID REFERENCES bookings (id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED,
+    PRIMARY KEY (event_id, seat_no)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_seats_free ON event_seats (event_id, seat_no) WHERE booking_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_seats_booking_id ON event_seats (booking_id);
+
+-- Without the event lock nothing else stops a user from booking twice.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_event_user_active ON bookings (event_id, user_id)
+    WHERE status IN ('reserved', 'confirmed');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_bookings_event_user_active;
+DROP TABLE IF EXISTS event_seats;
+ALTER TABLE events DROP COLUMN IF EXISTS inventory;
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS description       TEXT          NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS category          VARCHAR(32)   NOT NULL DEFAULT 'other'
+        CHECK (category IN ('concert', 'conference', 'meetup', 'workshop', 'theatre', 'sport', 'exhibition', 'other')),
+    ADD COLUMN IF NOT EXISTS tags              TEXT[]        NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS organizer_contact VARCHAR(255)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cover_image_url   VARCHAR(2048) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_events_category ON events (category);
+-- Serves tags @> ARRAY[...] of the tag filter.
+CREATE INDEX IF NOT EXISTS idx_events_tags ON events USING GIN (tags);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_tags;
+DROP INDEX IF EXISTS idx_events_category;
+ALTER TABLE events
+    DROP COLUMN IF EXISTS cover_image_url,
+    DROP COLUMN IF EXISTS organizer_contact,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS category,
+    DROP COLUMN IF EXISTS description;
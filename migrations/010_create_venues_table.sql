@@ -0,0 +1,45 @@
+-- +goose Up
+-- btree_gist lets the exclusion constraint below compare venue ids with =.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS venues
+(
+    id               UUID PRIMARY KEY,
+    name             VARCHAR(128)     NOT NULL,
+    address          VARCHAR(255)     NOT NULL,
+    latitude         DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude        DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    time_zone        VARCHAR(64)      NOT NULL,
+    default_capacity INT              NOT NULL CHECK (default_capacity > 0),
+    created_at       TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_venues_name ON venues (name);
+
+-- Events created before venues existed did not record when they end; they
+-- are assumed to last two hours, the default of the API.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS venue_id UUID REFERENCES venues (id),
+    ADD COLUMN IF NOT EXISTS end_date TIMESTAMPTZ;
+UPDATE events
+SET end_date = date + INTERVAL '2 hours'
+WHERE end_date IS NULL;
+ALTER TABLE events
+    ALTER COLUMN end_date SET NOT NULL,
+    ADD CONSTRAINT events_end_date_check CHECK (end_date > date),
+    -- A venue hosts one event at a time. Ranges are half-open, so an event
+    -- may start when the previous one ends; cancelled events free the venue.
+    ADD CONSTRAINT events_venue_overlap EXCLUDE USING gist (venue_id WITH =, tstzrange(date, end_date) WITH &&)
+        WHERE (venue_id IS NOT NULL AND cancelled_at IS NULL);
+
+CREATE INDEX IF NOT EXISTS idx_events_venue_id ON events (venue_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_venue_id;
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_venue_overlap,
+    DROP CONSTRAINT IF EXISTS events_end_date_check,
+    DROP COLUMN IF EXISTS end_date,
+    DROP COLUMN IF EXISTS venue_id;
+DROP TABLE IF EXISTS venues;
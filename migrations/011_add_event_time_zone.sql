@@ -0,0 +1,17 @@
+-- +goose Up
+-- Existing events keep closing for bookings at their start, as before.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS time_zone         VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS booking_closes_at TIMESTAMPTZ;
+UPDATE events
+SET booking_closes_at = date
+WHERE booking_closes_at IS NULL;
+ALTER TABLE events
+    ALTER COLUMN booking_closes_at SET NOT NULL,
+    ADD CONSTRAINT events_booking_closes_at_check CHECK (booking_closes_at <= end_date);
+
+-- +goose Down
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_booking_closes_at_check,
+    DROP COLUMN IF EXISTS booking_closes_at,
+    DROP COLUMN IF EXISTS time_zone;
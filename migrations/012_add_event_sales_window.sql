@@ -0,0 +1,33 @@
+-- +goose Up
+-- booking_closes_at becomes the end of the ticket sales window. Sales of
+-- existing events opened when they were created, and none of them closes
+-- after its start, which is what the new check requires.
+ALTER TABLE events
+    RENAME COLUMN booking_closes_at TO sales_close_at;
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS sales_open_at TIMESTAMPTZ,
+    DROP CONSTRAINT IF EXISTS events_booking_closes_at_check,
+    ADD CONSTRAINT events_sales_window_check
+        CHECK (sales_close_at <= date AND (sales_open_at IS NULL OR sales_open_at < sales_close_at));
+
+CREATE TABLE IF NOT EXISTS event_subscriptions
+(
+    event_id    UUID        NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    user_id     UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMPTZ,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_subscriptions_pending ON event_subscriptions (event_id)
+    WHERE notified_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS event_subscriptions;
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_sales_window_check,
+    DROP COLUMN IF EXISTS sales_open_at;
+ALTER TABLE events
+    RENAME COLUMN sales_close_at TO booking_closes_at;
+ALTER TABLE events
+    ADD CONSTRAINT events_booking_closes_at_check CHECK (booking_closes_at <= end_date);
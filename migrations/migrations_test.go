@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// TestMigrations checks that the embedded migrations are numbered without
+// gaps and that every one of them can be rolled back.
+func TestMigrations(t *testing.T) {
+	names, err := fs.Glob(FS, "*.sql")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(names) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, name := range names {
+		if prefix := fmt.Sprintf("%03d_", i+1); !strings.HasPrefix(name, prefix) {
+			t.Errorf("migration %s, want the prefix %s", name, prefix)
+		}
+
+		content, err := fs.ReadFile(FS, name)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		for _, section := range []string{"-- +goose Up", "-- +goose Down"} {
+			if !strings.Contains(string(content), section) {
+				t.Errorf("migration %s has no %q section", name, section)
+			}
+		}
+	}
+}